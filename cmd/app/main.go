@@ -1,7 +1,35 @@
 package main
 
-import "avito-internship/internal/app"
+import (
+	"avito-internship/internal/app"
+	"flag"
+	"fmt"
+	"os"
+)
 
 func main() {
-	app.Run()
+	noMigrate := flag.Bool("no-migrate", false, "do not apply migrations on startup")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [migrate|seed <command>]\n\nflags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var err error
+	switch flag.Arg(0) {
+	case "":
+		err = app.Run(app.Options{NoMigrate: *noMigrate})
+	case "migrate":
+		err = app.Migrate(flag.Args()[1:], os.Stdout)
+	case "seed":
+		err = app.Seed(flag.Args()[1:], os.Stdout)
+	default:
+		err = fmt.Errorf("unknown command %q", flag.Arg(0))
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
 }
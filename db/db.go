@@ -0,0 +1,51 @@
+// Package db contains database migrations embedded into the binary.
+package db
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// PostgresMigrations are the schema migrations of the PostgreSQL storage backend.
+//
+//go:embed migrations/*.sql
+var PostgresMigrations embed.FS
+
+// SqliteMigrations are the schema migrations of the SQLite storage backend.
+//
+//go:embed sqlite/migrations/*.sql
+var SqliteMigrations embed.FS
+
+// Fixtures are named sets of demo data loaded by the seed command.
+//
+//go:embed fixtures/*.yaml
+var Fixtures embed.FS
+
+// LatestVersion returns the highest migration version found in dir of fsys,
+// i.e. the schema version the binary expects after all migrations are applied.
+func LatestVersion(fsys fs.FS, dir string) (uint, error) {
+	source, err := iofs.New(fsys, dir)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1 @@
+-- Demo data is not restored by migrations, load it with `server seed load demo`.
@@ -0,0 +1,30 @@
+-- Removes the demo data inserted by 000003_add_data.
+-- Rows that real data depends on are kept.
+DELETE FROM pull_requests pr
+WHERE (pr.id, pr.name) IN (
+    ('pr-1001', 'Add login feature'),
+    ('pr-1002', 'Fix bug #42'),
+    ('pr-1003', 'Improve performance'),
+    ('pr-1004', 'Refactor module'),
+    ('pr-1005', 'Update README'),
+    ('pr-1006', 'Add tests')
+);
+
+DELETE FROM users u
+WHERE (u.id, u.name) IN (
+    ('u1', 'Alice'),
+    ('u2', 'Bob'),
+    ('u3', 'Carol'),
+    ('u4', 'Dave'),
+    ('u5', 'Eve'),
+    ('u6', 'Frank'),
+    ('u7', 'Grace'),
+    ('u8', 'Hank')
+)
+AND u.team_id = (SELECT id FROM teams WHERE name = 'alpha_team')
+AND NOT EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.reviewer_id = u.id);
+
+DELETE FROM teams t
+WHERE t.name = 'alpha_team'
+AND NOT EXISTS (SELECT 1 FROM users u WHERE u.team_id = t.id);
@@ -0,0 +1,2 @@
+-- Fails while some users are outside any team; add them to a team first.
+ALTER TABLE users ALTER COLUMN team_id SET NOT NULL;
@@ -0,0 +1,2 @@
+-- Users removed from a team keep their accounts and authored pull requests.
+ALTER TABLE users ALTER COLUMN team_id DROP NOT NULL;
@@ -0,0 +1,15 @@
+-- Users can belong to several teams; users.team_id stays their primary team.
+CREATE TABLE IF NOT EXISTS team_members(
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+
+INSERT INTO team_members (team_id, user_id)
+SELECT team_id, id FROM users WHERE team_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- A pull request may name the team its reviewers come from.
+ALTER TABLE pull_requests ADD COLUMN team_id INT REFERENCES teams(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- A team may have a parent; reviewer search widens up to max_depth levels of it.
+ALTER TABLE teams ADD COLUMN parent_id INT REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE teams ADD COLUMN max_depth INT NOT NULL DEFAULT 0 CHECK (max_depth >= 0);
+
+CREATE INDEX idx_teams_parent_id ON teams(parent_id);
@@ -0,0 +1 @@
+DELETE FROM statuses WHERE name IN ('OPEN', 'MERGED');
@@ -0,0 +1,2 @@
+INSERT INTO statuses(name)
+VALUES ('OPEN'), ('MERGED');
@@ -0,0 +1,16 @@
+-- Versions 3 and 4 only touched demo data in PostgreSQL, so SQLite skips them
+-- and keeps the version numbers of schema changes in step.
+CREATE TABLE IF NOT EXISTS outbox_events(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    failed_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
@@ -0,0 +1,24 @@
+-- Users removed from a team keep their accounts and authored pull requests.
+-- SQLite cannot drop NOT NULL, so users is rebuilt as described in
+-- https://www.sqlite.org/lang_altertable.html#otheralter. Migrations run with
+-- foreign keys off, so dropping the old table leaves pull_requests and
+-- pr_reviewers untouched.
+CREATE TABLE users_new(
+    id VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    team_id INTEGER REFERENCES teams(id) ON DELETE RESTRICT
+);
+
+INSERT INTO users_new (id, name, is_active, team_id)
+SELECT id, name, is_active, team_id FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_team_active ON users(team_id, is_active);
+
+-- Fails the migration when a reference no longer resolves.
+CREATE TEMP TABLE foreign_key_violations(tbl TEXT CHECK (tbl IS NULL));
+INSERT INTO foreign_key_violations SELECT "table" FROM pragma_foreign_key_check;
+DROP TABLE foreign_key_violations;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_teams_parent_id;
+ALTER TABLE teams DROP COLUMN max_depth;
+ALTER TABLE teams DROP COLUMN parent_id;
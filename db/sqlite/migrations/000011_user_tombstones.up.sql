@@ -0,0 +1,6 @@
+-- Users created by anonymization. Only rows listed here are tombstones,
+-- whatever their id looks like.
+CREATE TABLE IF NOT EXISTS user_tombstones(
+    user_id VARCHAR(50) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
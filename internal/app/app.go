@@ -1,57 +1,137 @@
 package app
 
 import (
+	"avito-internship/internal/config"
 	v1 "avito-internship/internal/delivery/v1"
-	"avito-internship/internal/repository/pgdb"
+	"avito-internship/internal/github"
+	"avito-internship/internal/health"
+	"avito-internship/internal/idempotency"
+	"avito-internship/internal/outbox"
 	"avito-internship/internal/server"
 	"avito-internship/internal/usecase"
+	"avito-internship/internal/webhook"
 	"avito-internship/pkg/logger"
-	"avito-internship/pkg/postgres"
+	"avito-internship/pkg/tracing"
 	v "avito-internship/pkg/validator"
 	"context"
 	"errors"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func Run() {
-	slogLogger := logger.NewSlogLogger()
+// Options are command-line overrides of the loaded configuration.
+type Options struct {
+	NoMigrate bool
+}
+
+func Run(opts Options) error {
+	bootLogger := logger.NewSlogLogger()
+
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		bootLogger.Errorf(err, "invalid configuration")
+		return err
+	}
+
+	rootLogger, err := logger.NewSlogLoggerWithConfig(cfg.Log, os.Stdout)
+	if err != nil {
+		bootLogger.Errorf(err, "unable to initialize logger")
+		return err
+	}
+	slogLogger := rootLogger.Package("app")
+	storageLogger := rootLogger.Package("storage")
+	if opts.NoMigrate {
+		cfg.AutoMigrate = false
+	}
+	slogLogger.Infof("effective config: %s", cfg)
 
-	db, err := postgres.Connect()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
-		slogLogger.Errorf(err, "unable to connect to database")
-		return
+		slogLogger.Errorf(err, "unable to initialize tracing")
+		return err
 	}
-	defer db.Close()
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slogLogger.Errorf(err, "unable to flush traces")
+		}
+	}()
 
-	if err := db.RunMigrations(slogLogger); err != nil {
-		slogLogger.Errorf(err, "unable to run migrations")
-		return
+	store, err := initStorage(ctx, cfg, storageLogger)
+	if errors.Is(err, context.Canceled) {
+		slogLogger.Infof("startup interrupted while waiting for storage")
+		return nil
+	}
+	if err != nil {
+		slogLogger.Errorf(err, "unable to initialize storage")
+		return err
+	}
+	defer store.close()
+
+	if cfg.AutoMigrate {
+		if err := store.migrate(ctx, storageLogger, migrateUp(storageLogger)); err != nil {
+			slogLogger.Errorf(err, "unable to apply migrations")
+			return err
+		}
+	} else {
+		slogLogger.Infof("auto-migrate is disabled, skipping migrations")
 	}
 
-	userUC, teamUC, prUC, middleware := initDeps(slogLogger, db)
+	if err := loadFixtures(ctx, cfg, storageLogger, store); err != nil {
+		slogLogger.Errorf(err, "unable to load fixtures")
+		return err
+	}
+
+	dispatcherDone := startDispatcher(ctx, cfg.Outbox, rootLogger.Package("outbox"), store)
+	webhookDone := startWebhookWorker(ctx, cfg, rootLogger.Package("webhook"), store)
+	idempotencyDone := startIdempotencyCleanup(ctx, cfg.Idempotency, rootLogger.Package("idempotency"), store)
+
+	userUC, teamUC, prUC, webhookUC, middleware := initDeps(cfg, rootLogger.Package("http"), store)
 	handler := v1.NewHandler(userUC, teamUC, prUC, middleware)
 
-	r := gin.Default()
-	if err := v.RegisterValidators(); err != nil {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	if cfg.Tracing.Enabled() {
+		r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
+	if err := v.RegisterValidators(cfg.IDs); err != nil {
 		slogLogger.Errorf(err, "unable to register validators")
-		return
+		return err
 	}
 
+	monitor, err := initHealth(cfg, store)
+	if err != nil {
+		slogLogger.Errorf(err, "unable to initialize health checks")
+		return err
+	}
+
+	v1.NewHealthHandler(monitor).Init(r)
 	handler.Init(r)
+	v1.NewAdminHandler(rootLogger.Levels(), middleware).Init(r)
+	v1.NewWebhookHandler(webhookUC, middleware).Init(r)
 
-	serverCfg := server.LoadHttpServerConfig(slogLogger)
-	srv := server.NewServer(r, serverCfg)
+	ingester, err := github.NewIngester(prUC, cfg.GitHub)
+	if err != nil {
+		slogLogger.Errorf(err, "unable to initialize github ingestion")
+		return err
+	}
+	reconciler, reconcilerDone := startReconciler(ctx, cfg.GitHub, rootLogger.Package("github"), ingester, store)
+	v1.NewGitHubHandler(ingester, reconciler, cfg.GitHub.WebhookSecret, middleware).Init(r)
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
+	srv := server.NewServer(r, cfg.HTTP)
+	srv.OnStop(monitor.SetDraining)
 
 	go func() {
-		slogLogger.Infof("starting server on port %s", serverCfg.Port)
+		slogLogger.Infof("starting server on port %d", cfg.HTTP.Port)
 		if err := srv.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slogLogger.Errorf(err, "server failed")
 		}
@@ -60,34 +140,142 @@ func Run() {
 	<-ctx.Done()
 	slogLogger.Infof("server shutting down...")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second+cfg.HTTP.DrainDelay)
 	defer cancel()
 
 	if err := srv.Stop(shutdownCtx); err != nil {
 		slogLogger.Errorf(err, "server forced to shutdown")
 	}
 
+	<-dispatcherDone
+	<-webhookDone
+	<-idempotencyDone
+	<-reconcilerDone
 	slogLogger.Infof("server stopped gracefully")
-
+	return nil
 }
 
-func initDeps(logger *logger.SlogLogger, db *postgres.PgDatabase) (
-	userUC *usecase.UserUseCase,
-	teamUC *usecase.TeamUseCase,
-	prUC *usecase.PullRequestUseCase,
+func initDeps(cfg config.Config, logger logger.Logger, store *storage) (
+	userUC usecase.UserUC,
+	teamUC usecase.TeamUC,
+	prUC usecase.PullRequestUC,
+	webhookUC usecase.WebhookUC,
 	middleware *v1.Middleware,
 ) {
-	userRepo := pgdb.NewUserRepository(db.Pool)
-	reviewerRepo := pgdb.NewPrReviewerRepository(db.Pool)
-	teamRepo := pgdb.NewTeamRepository(db.Pool)
-	prRepo := pgdb.NewPullRequestsRepository(db.Pool)
-	statusRepo := pgdb.NewStatusRepo(db.Pool)
-
-	prUC = usecase.NewPullRequestUseCase(prRepo, reviewerRepo, userRepo, statusRepo, db.Pool)
-	userUC = usecase.NewUserUseCase(reviewerRepo, userRepo, teamRepo)
-	teamUC = usecase.NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, db.Pool, reviewerRepo)
-
-	adminToken := os.Getenv("ADMIN_TOKEN")
-	middleware = v1.NewMiddleware(logger, adminToken)
+	prUC = usecase.NewPullRequestUseCase(store.prRepo, store.reviewerRepo, store.userRepo, store.teamRepo, store.statusRepo, store.outboxRepo, store.txManager, cfg.Review)
+	userUC = usecase.NewUserUseCase(store.reviewerRepo, store.userRepo, store.teamRepo, store.prRepo,
+		store.statusRepo, store.outboxRepo, store.txManager)
+	teamUC = usecase.NewTeamUseCase(store.teamRepo, store.userRepo, store.prRepo, store.statusRepo, store.txManager, store.reviewerRepo, store.outboxRepo)
+	webhookUC = usecase.NewWebhookUseCase(store.webhookRepo, store.txManager)
+
+	if cfg.Tracing.Enabled() {
+		tracer := tracing.Tracer("usecase")
+		userUC = usecase.NewTracedUserUC(userUC, tracer)
+		teamUC = usecase.NewTracedTeamUC(teamUC, tracer)
+		prUC = usecase.NewTracedPullRequestUC(prUC, tracer)
+		webhookUC = usecase.NewTracedWebhookUC(webhookUC, tracer)
+	}
+
+	middleware = v1.NewMiddleware(logger, cfg.AdminToken, idempotency.NewService(store.idemRepo, cfg.Idempotency, logger))
 	return
 }
+
+// startDispatcher runs the outbox dispatcher until ctx is done. The returned
+// channel is closed once it has stopped.
+func startDispatcher(ctx context.Context, cfg outbox.Config, logger *logger.SlogLogger, store *storage) <-chan struct{} {
+	done := make(chan struct{})
+	if !cfg.Enabled {
+		logger.Infof("outbox dispatcher is disabled")
+		close(done)
+		return done
+	}
+
+	var sinks []outbox.Sink
+	for _, name := range cfg.SinkNames() {
+		switch name {
+		case outbox.SinkLog:
+			sinks = append(sinks, outbox.NewLogSink(logger))
+		case outbox.SinkWebhook:
+			sinks = append(sinks, webhook.NewSink(store.webhookRepo))
+		}
+	}
+
+	dispatcher := outbox.NewDispatcher(store.outboxRepo, cfg, logger, sinks...)
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+
+	return done
+}
+
+// startWebhookWorker sends queued webhook deliveries until ctx is done. It only
+// runs when the outbox feeds the webhook sink.
+func startWebhookWorker(ctx context.Context, cfg config.Config, logger *logger.SlogLogger, store *storage) <-chan struct{} {
+	done := make(chan struct{})
+	if !cfg.Outbox.Enabled || !slices.Contains(cfg.Outbox.SinkNames(), outbox.SinkWebhook) {
+		logger.Infof("webhook worker is disabled")
+		close(done)
+		return done
+	}
+
+	worker := webhook.NewWorker(store.webhookRepo, cfg.Webhook, logger)
+	go func() {
+		defer close(done)
+		worker.Run(ctx)
+	}()
+
+	return done
+}
+
+// startIdempotencyCleanup deletes expired idempotency keys until ctx is done.
+func startIdempotencyCleanup(ctx context.Context, cfg idempotency.Config, logger *logger.SlogLogger, store *storage) <-chan struct{} {
+	done := make(chan struct{})
+
+	service := idempotency.NewService(store.idemRepo, cfg, logger)
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+
+	return done
+}
+
+// startReconciler builds the GitHub reconciler when a repository is configured
+// and runs it periodically when an interval is set. The reconciler is nil when
+// reconciliation is not configured.
+func startReconciler(ctx context.Context, cfg github.Config, logger *logger.SlogLogger, ingester *github.Ingester, store *storage) (*github.Reconciler, <-chan struct{}) {
+	done := make(chan struct{})
+	if !cfg.ReconcileEnabled() {
+		logger.Infof("github reconciliation is disabled")
+		close(done)
+		return nil, done
+	}
+
+	reconciler := github.NewReconciler(github.NewClient(cfg), ingester, store.prRepo, cfg, logger)
+	if cfg.ReconcileInterval <= 0 {
+		logger.Infof("github reconciliation runs on demand only")
+		close(done)
+		return reconciler, done
+	}
+
+	go func() {
+		defer close(done)
+		reconciler.Run(ctx)
+	}()
+
+	return reconciler, done
+}
+
+func initHealth(cfg config.Config, store *storage) (*health.Monitor, error) {
+	expected, err := store.health.ExpectedSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	monitor := health.NewMonitor(cfg.Health)
+	monitor.Register("database", store.health.Ping)
+	monitor.Register("schema", health.SchemaCheck(store.health.SchemaVersion, expected))
+
+	return monitor, nil
+}
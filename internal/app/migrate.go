@@ -0,0 +1,165 @@
+package app
+
+import (
+	"avito-internship/internal/config"
+	"avito-internship/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up [N]       apply all or N pending migrations
+  down [N]     roll back N migrations (default 1)
+  version      print the current schema version
+  force V      set the schema version to V and clear the dirty flag`
+
+// Migrate executes the migrate subcommand described by args against the configured storage.
+func Migrate(args []string, out io.Writer) error {
+	slogLogger := logger.NewSlogLogger()
+
+	fn, err := parseMigrateCommand(args, out, slogLogger)
+	if err != nil {
+		fmt.Fprintln(out, migrateUsage)
+		return err
+	}
+
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		slogLogger.Errorf(err, "invalid configuration")
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := initStorage(ctx, cfg, slogLogger)
+	if err != nil {
+		slogLogger.Errorf(err, "unable to initialize storage")
+		return err
+	}
+	defer store.close()
+
+	if err := store.migrate(ctx, slogLogger, fn); err != nil {
+		slogLogger.Errorf(err, "migrate %s failed", args[0])
+		return err
+	}
+
+	return nil
+}
+
+func parseMigrateCommand(args []string, out io.Writer, logger logger.Logger) (func(m *migrate.Migrate) error, error) {
+	if len(args) == 0 {
+		return nil, errors.New("migrate: command is required")
+	}
+
+	cmd, rest := args[0], args[1:]
+	if len(rest) > 1 {
+		return nil, fmt.Errorf("migrate %s: too many arguments", cmd)
+	}
+
+	arg := func(def int, required bool) (int, error) {
+		if len(rest) == 0 {
+			if required {
+				return 0, fmt.Errorf("migrate %s: argument is required", cmd)
+			}
+			return def, nil
+		}
+
+		n, err := strconv.Atoi(rest[0])
+		if err != nil {
+			return 0, fmt.Errorf("migrate %s: invalid argument %q", cmd, rest[0])
+		}
+		return n, nil
+	}
+
+	switch cmd {
+	case "up":
+		n, err := arg(0, false)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("migrate up: N must be positive, got %d", n)
+		}
+		if n == 0 {
+			return migrateUp(logger), nil
+		}
+		return func(m *migrate.Migrate) error {
+			return ignoreNoChange(m.Steps(n))
+		}, nil
+	case "down":
+		n, err := arg(1, false)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("migrate down: N must be positive, got %d", n)
+		}
+		return func(m *migrate.Migrate) error {
+			return ignoreNoChange(m.Steps(-n))
+		}, nil
+	case "version":
+		if len(rest) != 0 {
+			return nil, errors.New("migrate version: unexpected argument")
+		}
+		return func(m *migrate.Migrate) error {
+			version, dirty, err := m.Version()
+			if errors.Is(err, migrate.ErrNilVersion) {
+				fmt.Fprintln(out, "no migrations applied")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "version %d", version)
+			if dirty {
+				fmt.Fprint(out, " (dirty)")
+			}
+			fmt.Fprintln(out)
+			return nil
+		}, nil
+	case "force":
+		v, err := arg(0, true)
+		if err != nil {
+			return nil, err
+		}
+		return func(m *migrate.Migrate) error {
+			return m.Force(v)
+		}, nil
+	default:
+		return nil, fmt.Errorf("migrate: unknown command %q", cmd)
+	}
+}
+
+func migrateUp(logger logger.Logger) func(m *migrate.Migrate) error {
+	return func(m *migrate.Migrate) error {
+		err := m.Up()
+		if errors.Is(err, migrate.ErrNoChange) {
+			logger.Infof("schema is up to date")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("migrations applied successfully")
+		return nil
+	}
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
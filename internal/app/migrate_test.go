@@ -0,0 +1,42 @@
+package app
+
+import (
+	"avito-internship/pkg/logger"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigrateCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "up", args: []string{"up"}},
+		{name: "up steps", args: []string{"up", "2"}},
+		{name: "down default", args: []string{"down"}},
+		{name: "version", args: []string{"version"}},
+		{name: "force", args: []string{"force", "3"}},
+		{name: "no command", args: nil, wantErr: "migrate: command is required"},
+		{name: "unknown", args: []string{"redo"}, wantErr: `migrate: unknown command "redo"`},
+		{name: "force without version", args: []string{"force"}, wantErr: "migrate force: argument is required"},
+		{name: "down not a number", args: []string{"down", "all"}, wantErr: `migrate down: invalid argument "all"`},
+		{name: "down zero", args: []string{"down", "0"}, wantErr: "migrate down: N must be positive, got 0"},
+		{name: "too many arguments", args: []string{"up", "1", "2"}, wantErr: "migrate up: too many arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := parseMigrateCommand(tt.args, io.Discard, logger.NewSlogLogger())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, fn)
+		})
+	}
+}
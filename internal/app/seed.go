@@ -0,0 +1,118 @@
+package app
+
+import (
+	"avito-internship/internal/config"
+	"avito-internship/internal/fixtures"
+	"avito-internship/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const seedUsage = `usage: seed <command> [set...]
+
+commands:
+  list         print the available fixture sets
+  load SET...  load fixture sets, already present rows are kept
+  clean SET... remove rows of fixture sets`
+
+// Seed executes the seed subcommand described by args against the configured storage.
+func Seed(args []string, out io.Writer) error {
+	slogLogger := logger.NewSlogLogger()
+
+	if len(args) == 0 {
+		fmt.Fprintln(out, seedUsage)
+		return errors.New("seed: command is required")
+	}
+
+	cmd, names := args[0], args[1:]
+	if cmd == "list" {
+		sets, err := fixtures.Names()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, strings.Join(sets, "\n"))
+		return nil
+	}
+
+	if cmd != "load" && cmd != "clean" {
+		fmt.Fprintln(out, seedUsage)
+		return fmt.Errorf("seed: unknown command %q", cmd)
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(out, seedUsage)
+		return fmt.Errorf("seed %s: fixture set is required", cmd)
+	}
+
+	sets, err := getFixtureSets(names)
+	if err != nil {
+		slogLogger.Errorf(err, "seed %s failed", cmd)
+		return err
+	}
+
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		slogLogger.Errorf(err, "invalid configuration")
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := initStorage(ctx, cfg, slogLogger)
+	if err != nil {
+		slogLogger.Errorf(err, "unable to initialize storage")
+		return err
+	}
+	defer store.close()
+
+	for _, set := range sets {
+		if cmd == "load" {
+			err = store.seeder.Load(ctx, set)
+		} else {
+			err = store.seeder.Cleanup(ctx, set)
+		}
+		if err != nil {
+			slogLogger.Errorf(err, "seed %s %s failed", cmd, set.Name)
+			return err
+		}
+
+		slogLogger.Infof("seed %s %s: done", cmd, set.Name)
+	}
+
+	return nil
+}
+
+// loadFixtures loads the fixture sets configured with SEED_FIXTURES.
+func loadFixtures(ctx context.Context, cfg config.Config, logger logger.Logger, store *storage) error {
+	sets, err := getFixtureSets(cfg.SeedSets())
+	if err != nil {
+		return err
+	}
+
+	for _, set := range sets {
+		if err := store.seeder.Load(ctx, set); err != nil {
+			return err
+		}
+		logger.Infof("fixture set %s loaded", set.Name)
+	}
+
+	return nil
+}
+
+func getFixtureSets(names []string) ([]fixtures.Set, error) {
+	sets := make([]fixtures.Set, 0, len(names))
+	for _, name := range names {
+		set, err := fixtures.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
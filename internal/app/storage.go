@@ -0,0 +1,112 @@
+package app
+
+import (
+	"avito-internship/internal/config"
+	"avito-internship/internal/fixtures"
+	r "avito-internship/internal/repository"
+	"avito-internship/internal/repository/pgdb"
+	"avito-internship/internal/repository/sqlitedb"
+	"avito-internship/internal/usecase"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/postgres"
+	"avito-internship/pkg/sqlite"
+	"avito-internship/pkg/tracing"
+	"avito-internship/pkg/transaction"
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type storage struct {
+	userRepo     r.UserRepository
+	teamRepo     r.TeamRepository
+	prRepo       r.PullRequestRepository
+	reviewerRepo r.PrReviewerRepository
+	statusRepo   r.StatusRepository
+	outboxRepo   r.OutboxRepository
+	webhookRepo  r.WebhookRepository
+	idemRepo     r.IdempotencyRepository
+	txManager    transaction.Manager
+	migrate      func(ctx context.Context, logger logger.Logger, fn func(m *migrate.Migrate) error) error
+	seeder       *fixtures.Seeder
+	health       storageHealth
+	close        func()
+}
+
+type storageHealth interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (uint, bool, error)
+	ExpectedSchemaVersion() (uint, error)
+}
+
+// initStorage connects to the configured backend. Migrations are applied separately.
+func initStorage(ctx context.Context, cfg config.Config, logger logger.Logger) (*storage, error) {
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		var tracer pgx.QueryTracer
+		if cfg.Tracing.Enabled() {
+			tracer = postgres.NewQueryTracer(tracing.Tracer("postgres"))
+		}
+
+		db, err := postgres.Connect(ctx, cfg.Postgres, logger, tracer)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPostgresStorage(db, cfg.Review), nil
+	case config.StorageSqlite:
+		db, err := sqlite.Connect(cfg.Sqlite)
+		if err != nil {
+			return nil, err
+		}
+
+		return newSqliteStorage(db, cfg.Review), nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
+
+func newPostgresStorage(db *postgres.PgDatabase, policy usecase.ReviewPolicy) *storage {
+	sqlDb := stdlib.OpenDBFromPool(db.Pool)
+
+	return &storage{
+		userRepo:     pgdb.NewUserRepository(db.Pool),
+		teamRepo:     pgdb.NewTeamRepository(db.Pool),
+		prRepo:       pgdb.NewPullRequestsRepository(db.Pool),
+		reviewerRepo: pgdb.NewPrReviewerRepository(db.Pool),
+		statusRepo:   pgdb.NewStatusRepo(db.Pool),
+		outboxRepo:   pgdb.NewOutboxRepository(db.Pool),
+		webhookRepo:  pgdb.NewWebhookRepository(db.Pool),
+		idemRepo:     pgdb.NewIdempotencyRepository(db.Pool),
+		txManager:    transaction.NewPgxManager(db.Pool),
+		migrate:      db.Migrate,
+		seeder:       fixtures.NewSeeder(sqlDb, sq.Dollar, policy.MaxReviewers),
+		health:       db,
+		close: func() {
+			sqlDb.Close()
+			db.Close()
+		},
+	}
+}
+
+func newSqliteStorage(db *sqlite.SqliteDatabase, policy usecase.ReviewPolicy) *storage {
+	return &storage{
+		userRepo:     sqlitedb.NewUserRepository(db.DB),
+		teamRepo:     sqlitedb.NewTeamRepository(db.DB),
+		prRepo:       sqlitedb.NewPullRequestsRepository(db.DB),
+		reviewerRepo: sqlitedb.NewPrReviewerRepository(db.DB),
+		statusRepo:   sqlitedb.NewStatusRepo(db.DB),
+		outboxRepo:   sqlitedb.NewOutboxRepository(db.DB),
+		webhookRepo:  sqlitedb.NewWebhookRepository(db.DB),
+		idemRepo:     sqlitedb.NewIdempotencyRepository(db.DB),
+		txManager:    transaction.NewSqlManager(db.DB),
+		migrate:      db.Migrate,
+		seeder:       fixtures.NewSeeder(db.DB, sq.Question, policy.MaxReviewers),
+		health:       db,
+		close:        db.Close,
+	}
+}
@@ -0,0 +1,321 @@
+// Package config loads the application configuration from an optional YAML file
+// and environment variables. Environment variables take precedence over the file.
+package config
+
+import (
+	"avito-internship/internal/fixtures"
+	"avito-internship/internal/github"
+	"avito-internship/internal/health"
+	"avito-internship/internal/idempotency"
+	"avito-internship/internal/outbox"
+	"avito-internship/internal/server"
+	"avito-internship/internal/usecase"
+	"avito-internship/internal/webhook"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/postgres"
+	"avito-internship/pkg/sqlite"
+	"avito-internship/pkg/tracing"
+	"avito-internship/pkg/validator"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageSqlite   = "sqlite"
+
+	redacted = "***"
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+type Config struct {
+	Storage     string               `yaml:"storage" env:"STORAGE"`
+	AutoMigrate bool                 `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+	Seed        string               `yaml:"seed" env:"SEED_FIXTURES"`
+	Postgres    postgres.Config      `yaml:"postgres"`
+	Sqlite      sqlite.Config        `yaml:"sqlite"`
+	HTTP        server.Config        `yaml:"http"`
+	Log         logger.Config        `yaml:"log"`
+	Health      health.Config        `yaml:"health"`
+	Tracing     tracing.Config       `yaml:"tracing"`
+	Outbox      outbox.Config        `yaml:"outbox"`
+	Webhook     webhook.Config       `yaml:"webhook"`
+	GitHub      github.Config        `yaml:"github"`
+	Idempotency idempotency.Config   `yaml:"idempotency"`
+	Review      usecase.ReviewPolicy `yaml:"review"`
+	IDs         validator.Config     `yaml:"ids"`
+	AdminToken  string               `yaml:"admin_token" env:"ADMIN_TOKEN" secret:"true"`
+}
+
+func Default() Config {
+	return Config{
+		Storage:     StoragePostgres,
+		AutoMigrate: true,
+		Postgres: postgres.Config{
+			Host:              "localhost",
+			Port:              5432,
+			SSLMode:           "disable",
+			MaxConns:          10,
+			MinConns:          0,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			ConnectTimeout:    time.Minute,
+			ConnectBackoff:    500 * time.Millisecond,
+			ConnectMaxBackoff: 10 * time.Second,
+		},
+		Sqlite: sqlite.Config{
+			Path: "reviewer.db",
+		},
+		HTTP: server.Config{
+			Port:         8080,
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			KeepAlive:    60 * time.Second,
+		},
+		Log: logger.Config{
+			Format: logger.FormatText,
+			Level:  "info",
+		},
+		Health: health.Config{
+			ReadinessTimeout: 2 * time.Second,
+		},
+		Tracing: tracing.Config{
+			Exporter:     tracing.ExporterNone,
+			OTLPEndpoint: "localhost:4318",
+			OTLPInsecure: true,
+			SampleRatio:  1,
+			ServiceName:  "reviewer-service",
+		},
+		Outbox:      outbox.DefaultConfig(),
+		Webhook:     webhook.DefaultConfig(),
+		GitHub:      github.DefaultConfig(),
+		Idempotency: idempotency.DefaultConfig(),
+		Review:      usecase.DefaultReviewPolicy(),
+		IDs:         validator.DefaultConfig(),
+	}
+}
+
+// Load builds the configuration from defaults, the YAML file at path (if not empty)
+// and environment variables, and validates the result.
+func Load(path string) (Config, error) {
+	const op = "config.Load"
+
+	cfg := Default()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, e.Wrap(op, err)
+		}
+
+		if err := yaml.Unmarshal(raw, &cfg); err != nil {
+			return Config{}, e.Wrap(op, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem(), os.LookupEnv); err != nil {
+		return Config{}, e.Wrap(op, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, e.Wrap(op, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate reports every invalid value at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Storage == StoragePostgres || c.Storage == StorageSqlite,
+		"storage: must be %s or %s, got %q", StoragePostgres, StorageSqlite, c.Storage)
+
+	switch c.Storage {
+	case StoragePostgres:
+		pg := c.Postgres
+		check(pg.Host != "", "postgres.host: must not be empty")
+		check(pg.Port > 0 && pg.Port <= 65535, "postgres.port: must be between 1 and 65535, got %d", pg.Port)
+		check(pg.User != "", "postgres.user: must not be empty")
+		check(pg.Database != "", "postgres.database: must not be empty")
+		check(slices.Contains(sslModes, pg.SSLMode), "postgres.ssl_mode: must be one of %s, got %q", strings.Join(sslModes, ", "), pg.SSLMode)
+		check(pg.MaxConns > 0, "postgres.max_conns: must be positive, got %d", pg.MaxConns)
+		check(pg.MinConns >= 0 && pg.MinConns <= pg.MaxConns,
+			"postgres.min_conns: must be between 0 and max_conns (%d), got %d", pg.MaxConns, pg.MinConns)
+		check(pg.MaxConnLifetime > 0, "postgres.max_conn_lifetime: must be positive")
+		check(pg.MaxConnIdleTime > 0, "postgres.max_conn_idle_time: must be positive")
+		check(pg.HealthCheckPeriod > 0, "postgres.health_check_period: must be positive")
+		check(pg.ConnectTimeout > 0, "postgres.connect_timeout: must be positive")
+		check(pg.ConnectBackoff > 0, "postgres.connect_backoff: must be positive")
+		check(pg.ConnectMaxBackoff >= pg.ConnectBackoff, "postgres.connect_max_backoff: must not be less than connect_backoff")
+	case StorageSqlite:
+		check(c.Sqlite.Path != "", "sqlite.path: must not be empty")
+	}
+
+	check(c.HTTP.Port > 0 && c.HTTP.Port <= 65535, "http.port: must be between 1 and 65535, got %d", c.HTTP.Port)
+	check(c.HTTP.ReadTimeout > 0, "http.read_timeout: must be positive")
+	check(c.HTTP.WriteTimeout > 0, "http.write_timeout: must be positive")
+	check(c.HTTP.KeepAlive > 0, "http.keep_alive: must be positive")
+	check(c.HTTP.DrainDelay >= 0, "http.drain_delay: must not be negative")
+
+	if err := c.Log.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	check(c.Health.ReadinessTimeout > 0, "health.readiness_timeout: must be positive")
+
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Outbox.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Webhook.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.Idempotency.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.GitHub.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(c.SeedSets()) > 0 {
+		known, err := fixtures.Names()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, name := range c.SeedSets() {
+			check(slices.Contains(known, name), "seed: unknown fixture set %q, available: %s", name, strings.Join(known, ", "))
+		}
+	}
+
+	check(c.Review.MaxReviewers > 0, "review.max_reviewers: must be positive, got %d", c.Review.MaxReviewers)
+
+	if err := c.IDs.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// SeedSets returns the fixture sets listed in Seed, which is comma-separated.
+func (c Config) SeedSets() []string {
+	var sets []string
+	for _, name := range strings.Split(c.Seed, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			sets = append(sets, name)
+		}
+	}
+	return sets
+}
+
+// String renders the configuration as key=value pairs with secrets redacted.
+func (c Config) String() string {
+	var pairs []string
+	walk(reflect.ValueOf(c), "", func(key string, field reflect.StructField, v reflect.Value) {
+		value := fmt.Sprint(v.Interface())
+		if field.Tag.Get("secret") == "true" && value != "" {
+			value = redacted
+		}
+		pairs = append(pairs, key+"="+value)
+	})
+
+	return strings.Join(pairs, " ")
+}
+
+// applyEnv overrides fields tagged with env by the variables that are set and not empty.
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) error {
+	var errs []error
+	walk(v, "", func(_ string, field reflect.StructField, fv reflect.Value) {
+		name := field.Tag.Get("env")
+		if name == "" {
+			return
+		}
+
+		raw, ok := lookup(name)
+		if !ok || raw == "" {
+			return
+		}
+
+		if err := setValue(fv, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", name, raw, err))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// walk calls fn for every leaf field of the struct v, keyed by its dotted yaml path.
+func walk(v reflect.Value, prefix string, fn func(key string, field reflect.StructField, v reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key := prefix + strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		if field.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), key+".", fn)
+			continue
+		}
+
+		fn(key, field, v.Field(i))
+	}
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
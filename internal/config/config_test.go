@@ -0,0 +1,201 @@
+package config
+
+import (
+	"avito-internship/pkg/validator"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validPostgres() Config {
+	cfg := Default()
+	cfg.Postgres.User = "reviewer"
+	cfg.Postgres.Database = "reviewer"
+	return cfg
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(t *testing.T, cfg Config)
+		wantErr []string
+	}{
+		{
+			name: "overrides typed values",
+			env: map[string]string{
+				"HTTP_PORT":            "9090",
+				"HTTP_READ_TIMEOUT":    "2s",
+				"POSTGRES_MAX_CONNS":   "25",
+				"REVIEW_MAX_REVIEWERS": "3",
+			},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, 9090, cfg.HTTP.Port)
+				assert.Equal(t, 2*time.Second, cfg.HTTP.ReadTimeout)
+				assert.Equal(t, int32(25), cfg.Postgres.MaxConns)
+				assert.Equal(t, 3, cfg.Review.MaxReviewers)
+			},
+		},
+		{
+			name: "empty value keeps default",
+			env:  map[string]string{"HTTP_PORT": ""},
+			check: func(t *testing.T, cfg Config) {
+				assert.Equal(t, 8080, cfg.HTTP.Port)
+			},
+		},
+		{
+			name:    "reports every invalid value",
+			env:     map[string]string{"HTTP_PORT": "http", "KEEP_ALIVE": "60"},
+			wantErr: []string{`HTTP_PORT: invalid value "http"`, `KEEP_ALIVE: invalid value "60"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			lookup := func(name string) (string, bool) {
+				v, ok := tt.env[name]
+				return v, ok
+			}
+
+			err := applyEnv(reflect.ValueOf(&cfg).Elem(), lookup)
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+				for _, msg := range tt.wantErr {
+					assert.Contains(t, err.Error(), msg)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *Config)
+		wantErr []string
+	}{
+		{
+			name:   "valid postgres",
+			mutate: func(cfg *Config) {},
+		},
+		{
+			name: "sqlite does not need postgres credentials",
+			mutate: func(cfg *Config) {
+				cfg.Storage = StorageSqlite
+				cfg.Postgres = Default().Postgres
+			},
+		},
+		{
+			name:    "unknown storage",
+			mutate:  func(cfg *Config) { cfg.Storage = "mysql" },
+			wantErr: []string{`storage: must be postgres or sqlite, got "mysql"`},
+		},
+		{
+			name:    "unknown fixture set",
+			mutate:  func(cfg *Config) { cfg.Seed = "demo, prod" },
+			wantErr: []string{`seed: unknown fixture set "prod"`},
+		},
+		{
+			name:    "unknown outbox sink",
+			mutate:  func(cfg *Config) { cfg.Outbox.Sinks = "log,kafka" },
+			wantErr: []string{`outbox.sinks: unknown sink "kafka"`},
+		},
+		{
+			name:    "invalid id pattern",
+			mutate:  func(cfg *Config) { cfg.IDs.UserId = "u(" },
+			wantErr: []string{"ids.user_id: invalid pattern"},
+		},
+		{
+			name: "invalid id namespace",
+			mutate: func(cfg *Config) {
+				cfg.IDs.Namespaces = map[string]validator.Namespace{"Git:Hub": {PullRequestId: `.+`}}
+			},
+			wantErr: []string{`ids.namespaces: name "Git:Hub" must match`},
+		},
+		{
+			name: "collects all errors",
+			mutate: func(cfg *Config) {
+				cfg.Postgres.User = ""
+				cfg.Postgres.SSLMode = "maybe"
+				cfg.HTTP.Port = 0
+				cfg.Review.MaxReviewers = 0
+			},
+			wantErr: []string{
+				"postgres.user: must not be empty",
+				`postgres.ssl_mode: must be one of`,
+				"http.port: must be between 1 and 65535, got 0",
+				"review.max_reviewers: must be positive, got 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validPostgres()
+			tt.mutate(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, msg := range tt.wantErr {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+storage: sqlite
+sqlite:
+  path: /tmp/from-file.db
+http:
+  port: 7070
+review:
+  max_reviewers: 4
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	for _, name := range []string{"STORAGE", "SQLITE_PATH", "REVIEW_MAX_REVIEWERS", "HTTP_WRITE_TIMEOUT"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HTTP_PORT", "7171")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, StorageSqlite, cfg.Storage)
+	assert.Equal(t, "/tmp/from-file.db", cfg.Sqlite.Path)
+	assert.Equal(t, 7171, cfg.HTTP.Port)
+	assert.Equal(t, 4, cfg.Review.MaxReviewers)
+	assert.Equal(t, 10*time.Second, cfg.HTTP.WriteTimeout)
+}
+
+func TestString_RedactsSecrets(t *testing.T) {
+	cfg := validPostgres()
+	cfg.Postgres.Password = "hunter2"
+	cfg.AdminToken = "token"
+
+	s := cfg.String()
+
+	assert.NotContains(t, s, "hunter2")
+	assert.NotContains(t, s, "=token")
+	assert.Contains(t, s, "postgres.password=***")
+	assert.Contains(t, s, "admin_token=***")
+	assert.True(t, strings.Contains(s, "http.port=8080"))
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	levels     *logger.Levels
+	middleware *Middleware
+}
+
+func NewAdminHandler(levels *logger.Levels, middleware *Middleware) *AdminHandler {
+	return &AdminHandler{
+		levels:     levels,
+		middleware: middleware,
+	}
+}
+
+func (h *AdminHandler) Init(r *gin.Engine) {
+	admin := r.Group("/admin", h.middleware.AdminMiddleware())
+	{
+		admin.GET("/log/level", h.getLogLevel)
+		admin.PUT("/log/level", h.setLogLevel)
+	}
+}
+
+func (h *AdminHandler) getLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, h.logLevels())
+}
+
+func (h *AdminHandler) setLogLevel(c *gin.Context) {
+	var req SetLogLevelReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	switch {
+	case req.Package != "" && req.Level == "":
+		h.levels.Reset(req.Package)
+	case req.Level == "":
+		c.Error(e.Wrap("level is required", e.ErrInvalidRequestBody))
+		return
+	default:
+		if err := h.levels.Set(req.Package, req.Level); err != nil {
+			c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.logLevels())
+}
+
+func (h *AdminHandler) logLevels() LogLevelRes {
+	def, packages := h.levels.Snapshot()
+
+	res := LogLevelRes{
+		Level:    strings.ToLower(def.String()),
+		Packages: make(map[string]string, len(packages)),
+	}
+	for pkg, level := range packages {
+		res.Packages[pkg] = strings.ToLower(level.String())
+	}
+
+	return res
+}
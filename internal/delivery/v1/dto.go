@@ -4,8 +4,9 @@ import (
 	"avito-internship/internal/domain"
 )
 
+// TeamMemberDTO without user_id makes the server generate one for a new user.
 type TeamMemberDTO struct {
-	Id       string `json:"user_id" binding:"required,userid"`
+	Id       string `json:"user_id" binding:"omitempty,newuserid"`
 	Username string `json:"username" binding:"required"`
 	IsActive *bool  `json:"is_active" binding:"required"`
 }
@@ -16,10 +17,11 @@ type TeamDTO struct {
 }
 
 type UserDTO struct {
-	Id       string `json:"user_id" binding:"required,userid"`
-	Username string `json:"username" binding:"required"`
-	TeamName string `json:"team_name" binding:"required"`
-	IsActive bool   `json:"is_active" binding:"required"`
+	Id       string   `json:"user_id" binding:"required,userid"`
+	Username string   `json:"username" binding:"required"`
+	TeamName string   `json:"team_name" binding:"required"`
+	Teams    []string `json:"teams"`
+	IsActive bool     `json:"is_active" binding:"required"`
 }
 
 type PullRequestDTO struct {
@@ -66,10 +68,74 @@ type SetIsActiveRes struct {
 	User UserDTO `json:"user"`
 }
 
+type TransferUserReq struct {
+	UserId   string `json:"user_id" binding:"required,userid"`
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+type TransferUserRes struct {
+	User          UserDTO          `json:"user"`
+	FromTeam      string           `json:"from_team"`
+	ReassignedPrs []PullRequestDTO `json:"reassigned_prs"`
+	AuthoredPrs   []PullRequestDTO `json:"authored_prs"`
+}
+
+type AnonymizeUserReq struct {
+	UserId string `json:"user_id" binding:"required,userid"`
+}
+
+type AnonymizeUserRes struct {
+	User          UserDTO          `json:"user"`
+	ReassignedPrs []PullRequestDTO `json:"reassigned_prs"`
+}
+
+type GetUserQueryReq struct {
+	UserId string `form:"user_id" binding:"required,userid"`
+}
+
+type GetUserRes struct {
+	User        UserDTO `json:"user"`
+	OpenReviews int     `json:"open_reviews"`
+	AuthoredPrs int     `json:"authored_prs"`
+}
+
+type ListUsersQueryReq struct {
+	Query    string `form:"query"`
+	TeamName string `form:"team_name"`
+	IsActive *bool  `form:"is_active"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=500"`
+	Offset   int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type UserListItemDTO struct {
+	Id       string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name,omitempty"`
+	IsActive bool   `json:"is_active"`
+}
+
+type ListUsersRes struct {
+	Users  []UserListItemDTO `json:"users"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type JoinTeamReq struct {
+	UserId   string `json:"user_id" binding:"required,userid"`
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+type JoinTeamRes struct {
+	User UserDTO `json:"user"`
+}
+
+// CreatePullRequestReq without pull_request_id makes the server generate one.
 type CreatePullRequestReq struct {
-	Id       string `json:"pull_request_id" binding:"required,prid"`
+	Id       string `json:"pull_request_id" binding:"omitempty,newprid"`
 	Name     string `json:"pull_request_name" binding:"required"`
 	AuthorId string `json:"author_id" binding:"required,userid"`
+	TeamName string `json:"team_name"`
 }
 
 type CreatePullRequestDTO struct {
@@ -81,7 +147,15 @@ type CreatePullRequestDTO struct {
 }
 
 type CreatePullRequestRes struct {
-	PullRequest CreatePullRequestDTO `json:"pr"`
+	PullRequest     CreatePullRequestDTO `json:"pr"`
+	ReviewerSources []ReviewerSourceDTO  `json:"reviewer_sources"`
+}
+
+type ReviewerSourceDTO struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Source   string `json:"source"`
+	Level    int    `json:"level"`
 }
 
 type PullRequestMergeReq struct {
@@ -107,8 +181,9 @@ type PullRequestReassignReq struct {
 }
 
 type PullRequestReassignRes struct {
-	Pr         PullRequestDTO `json:"pr"`
-	ReplacedBy string         `json:"replaced_by"`
+	Pr               PullRequestDTO    `json:"pr"`
+	ReplacedBy       string            `json:"replaced_by"`
+	ReplacedBySource ReviewerSourceDTO `json:"replaced_by_source"`
 }
 
 type GetReviewQueryReq struct {
@@ -130,3 +205,171 @@ type DeactivateMembersRes struct {
 	DeactivatedMembers []TeamMemberDTO  `json:"deactivated_members"`
 	UpdPrs             []PullRequestDTO `json:"upd_prs"`
 }
+
+type AddMembersReq struct {
+	TeamName string          `json:"team_name" binding:"required"`
+	Members  []TeamMemberDTO `json:"members" binding:"required,dive"`
+}
+
+type AddMembersRes struct {
+	Team TeamDTO `json:"team"`
+}
+
+type SyncTeamReq struct {
+	TeamName string          `json:"team_name" binding:"required"`
+	Members  []TeamMemberDTO `json:"members" binding:"required,dive"`
+}
+
+type SyncTeamQueryReq struct {
+	DryRun bool `form:"dry_run"`
+}
+
+type RenamedMemberDTO struct {
+	Id          string `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	NewUsername string `json:"new_username"`
+}
+
+type SyncTeamRes struct {
+	TeamName    string             `json:"team_name"`
+	DryRun      bool               `json:"dry_run"`
+	Added       []TeamMemberDTO    `json:"added"`
+	Reactivated []TeamMemberDTO    `json:"reactivated"`
+	Renamed     []RenamedMemberDTO `json:"renamed"`
+	Deactivated []TeamMemberDTO    `json:"deactivated"`
+	Removed     []TeamMemberDTO    `json:"removed"`
+	UpdPrs      []PullRequestDTO   `json:"upd_prs"`
+}
+
+type RenameTeamReq struct {
+	TeamName    string `json:"team_name" binding:"required"`
+	NewTeamName string `json:"new_team_name" binding:"required"`
+}
+
+type RenameTeamRes struct {
+	Team TeamDTO `json:"team"`
+}
+
+type SetTeamParentReq struct {
+	TeamName       string `json:"team_name" binding:"required"`
+	ParentTeamName string `json:"parent_team_name"`
+	MaxDepth       *int   `json:"max_depth" binding:"required,min=0"`
+}
+
+type SetTeamParentRes struct {
+	TeamName       string `json:"team_name"`
+	ParentTeamName string `json:"parent_team_name,omitempty"`
+	MaxDepth       int    `json:"max_depth"`
+}
+
+type DeleteTeamReq struct {
+	TeamName        string `json:"team_name" binding:"required"`
+	TargetTeamName  string `json:"target_team_name"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+}
+
+type DeleteTeamRes struct {
+	TeamName       string           `json:"team_name"`
+	TargetTeamName string           `json:"target_team_name,omitempty"`
+	MovedMembers   []TeamMemberDTO  `json:"moved_members"`
+	UpdPrs         []PullRequestDTO `json:"upd_prs"`
+}
+
+type RemoveMembersReq struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	Members  []string `json:"members" binding:"required,dive"`
+}
+
+type RemoveMembersRes struct {
+	TeamName       string           `json:"team_name"`
+	RemovedMembers []TeamMemberDTO  `json:"removed_members"`
+	UpdPrs         []PullRequestDTO `json:"upd_prs"`
+}
+
+type SetLogLevelReq struct {
+	Level   string `json:"level"`
+	Package string `json:"package"`
+}
+
+type LogLevelRes struct {
+	Level    string            `json:"level"`
+	Packages map[string]string `json:"packages"`
+}
+
+type WebhookSubscriptionDTO struct {
+	Id                  int                `json:"subscription_id"`
+	URL                 string             `json:"url"`
+	Events              []domain.EventType `json:"events"`
+	IsActive            bool               `json:"is_active"`
+	ConsecutiveFailures int                `json:"consecutive_failures"`
+	CreatedAt           string             `json:"created_at"`
+	DisabledAt          *string            `json:"disabled_at,omitempty"`
+}
+
+type WebhookDeliveryDTO struct {
+	Id            int64                 `json:"delivery_id"`
+	EventId       int64                 `json:"event_id"`
+	EventType     domain.EventType      `json:"event_type"`
+	Status        domain.DeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	ResponseCode  *int                  `json:"response_code,omitempty"`
+	LastError     *string               `json:"last_error,omitempty"`
+	CreatedAt     string                `json:"created_at"`
+	NextAttemptAt *string               `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *string               `json:"delivered_at,omitempty"`
+}
+
+type CreateWebhookReq struct {
+	URL    string             `json:"url" binding:"required,url"`
+	Events []domain.EventType `json:"events" binding:"required,min=1,dive,required"`
+	Secret string             `json:"secret" binding:"omitempty,min=16"`
+}
+
+type CreateWebhookRes struct {
+	Subscription WebhookSubscriptionDTO `json:"subscription"`
+	Secret       string                 `json:"secret"`
+}
+
+type ListWebhooksRes struct {
+	Subscriptions []WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+type WebhookIdReq struct {
+	SubscriptionId int `json:"subscription_id" binding:"required,min=1"`
+}
+
+type EnableWebhookRes struct {
+	Subscription WebhookSubscriptionDTO `json:"subscription"`
+}
+
+type GetWebhookDeliveriesQueryReq struct {
+	SubscriptionId int `form:"subscription_id" binding:"required,min=1"`
+	Limit          int `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type GetWebhookDeliveriesRes struct {
+	SubscriptionId int                  `json:"subscription_id"`
+	Deliveries     []WebhookDeliveryDTO `json:"deliveries"`
+}
+
+type GitHubWebhookRes struct {
+	Event         string `json:"event"`
+	Action        string `json:"action,omitempty"`
+	PullRequestId string `json:"pull_request_id,omitempty"`
+	Result        string `json:"result"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type GitHubReconcileRes struct {
+	StartedAt string     `json:"started_at"`
+	Checked   int        `json:"checked"`
+	Drift     []DriftDTO `json:"drift"`
+}
+
+type DriftDTO struct {
+	PullRequestId string `json:"pull_request_id"`
+	Number        int    `json:"number"`
+	Kind          string `json:"kind"`
+	Fixed         bool   `json:"fixed"`
+	Error         string `json:"error,omitempty"`
+}
@@ -0,0 +1,657 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/github"
+	"avito-internship/internal/health"
+	"avito-internship/internal/idempotency"
+	"avito-internship/internal/repository/sqlitedb"
+	"avito-internship/internal/usecase"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/sqlite"
+	"avito-internship/pkg/transaction"
+	v "avito-internship/pkg/validator"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAdminToken   = "admin-secret"
+	testGitHubSecret = "github-secret"
+)
+
+type testServer struct {
+	t       *testing.T
+	srv     *httptest.Server
+	monitor *health.Monitor
+	logger  *recordingLogger
+	outbox  *sqlitedb.OutboxRepository
+	webhook *sqlitedb.WebhookRepository
+	forge   *fakeForge
+}
+
+// recordingLogger remembers the request IDs of logged errors.
+type recordingLogger struct {
+	*logger.SlogLogger
+
+	mu         sync.Mutex
+	requestIDs []string
+}
+
+func (l *recordingLogger) ErrorfContext(ctx context.Context, err error, format string, args ...any) {
+	l.mu.Lock()
+	l.requestIDs = append(l.requestIDs, logger.RequestID(ctx))
+	l.mu.Unlock()
+
+	l.SlogLogger.ErrorfContext(ctx, err, format, args...)
+}
+
+func (l *recordingLogger) errorRequestIDs() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]string(nil), l.requestIDs...)
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	slogLogger := logger.NewSlogLogger()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "e2e.db"))
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	require.NoError(t, db.RunMigrations(context.Background(), slogLogger))
+
+	userRepo := sqlitedb.NewUserRepository(db.DB)
+	reviewerRepo := sqlitedb.NewPrReviewerRepository(db.DB)
+	teamRepo := sqlitedb.NewTeamRepository(db.DB)
+	prRepo := sqlitedb.NewPullRequestsRepository(db.DB)
+	statusRepo := sqlitedb.NewStatusRepo(db.DB)
+	outboxRepo := sqlitedb.NewOutboxRepository(db.DB)
+	webhookRepo := sqlitedb.NewWebhookRepository(db.DB)
+	txManager := transaction.NewSqlManager(db.DB)
+
+	prUC := usecase.NewPullRequestUseCase(prRepo, reviewerRepo, userRepo, teamRepo, statusRepo, outboxRepo, txManager, usecase.DefaultReviewPolicy())
+	userUC := usecase.NewUserUseCase(reviewerRepo, userRepo, teamRepo, prRepo, statusRepo, outboxRepo, txManager)
+	teamUC := usecase.NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, txManager, reviewerRepo, outboxRepo)
+	webhookUC := usecase.NewWebhookUseCase(webhookRepo, txManager)
+	recorder := &recordingLogger{SlogLogger: slogLogger}
+	idempotencyService := idempotency.NewService(sqlitedb.NewIdempotencyRepository(db.DB), idempotency.DefaultConfig(), slogLogger)
+	middleware := v1.NewMiddleware(recorder, testAdminToken, idempotencyService)
+
+	expected, err := db.ExpectedSchemaVersion()
+	require.NoError(t, err)
+	monitor := health.NewMonitor(health.Config{ReadinessTimeout: time.Second})
+	monitor.Register("database", db.Ping)
+	monitor.Register("schema", health.SchemaCheck(db.SchemaVersion, expected))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, v.RegisterValidators(v.DefaultConfig()))
+	v1.NewHealthHandler(monitor).Init(r)
+	v1.NewHandler(userUC, teamUC, prUC, middleware).Init(r)
+	v1.NewAdminHandler(recorder.Levels(), middleware).Init(r)
+	v1.NewWebhookHandler(webhookUC, middleware).Init(r)
+
+	forge := newFakeForge(t)
+	githubCfg := github.Config{
+		WebhookSecret:     testGitHubSecret,
+		Repository:        "avito-tech/reviewer",
+		Logins:            "alice-dev=u1,bob=u2",
+		APIURL:            forge.srv.URL,
+		Token:             testForgeToken,
+		ReconcileLookback: 72 * time.Hour,
+	}
+	ingester, err := github.NewIngester(prUC, githubCfg)
+	require.NoError(t, err)
+	reconciler := github.NewReconciler(github.NewClient(githubCfg), ingester, prRepo, githubCfg, slogLogger)
+	v1.NewGitHubHandler(ingester, reconciler, testGitHubSecret, middleware).Init(r)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	return &testServer{t: t, srv: srv, monitor: monitor, logger: recorder, outbox: outboxRepo, webhook: webhookRepo, forge: forge}
+}
+
+// do sends body as JSON and decodes the response into out, returning the status code.
+func (s *testServer) do(method, path string, body, out any) int {
+	s.t.Helper()
+
+	return s.send(method, path, "", body, out)
+}
+
+// doAdmin is do with the admin token.
+func (s *testServer) doAdmin(method, path string, body, out any) int {
+	s.t.Helper()
+
+	return s.send(method, path, testAdminToken, body, out)
+}
+
+func (s *testServer) send(method, path, token string, body, out any) int {
+	s.t.Helper()
+
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		raw, err := json.Marshal(b)
+		require.NoError(s.t, err)
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, s.srv.URL+path, reader)
+	require.NoError(s.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.srv.Client().Do(req)
+	require.NoError(s.t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(s.t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+// expectError checks that the request fails with the given HTTP status and error code.
+func (s *testServer) expectError(method, path string, body any, status int, code string) {
+	s.t.Helper()
+
+	var res v1.ErrorResponse
+	require.Equal(s.t, status, s.do(method, path, body, &res))
+	require.Equal(s.t, code, res.Error.Code)
+}
+
+func (s *testServer) addTeam(name string, members ...member) {
+	s.t.Helper()
+
+	req := map[string]any{"team_name": name, "members": members}
+	require.Equal(s.t, http.StatusCreated, s.do(http.MethodPost, "/team/add", req, nil))
+}
+
+func (s *testServer) createPR(id, name, authorId string) v1.CreatePullRequestRes {
+	s.t.Helper()
+
+	var res v1.CreatePullRequestRes
+	req := map[string]any{"pull_request_id": id, "pull_request_name": name, "author_id": authorId}
+	require.Equal(s.t, http.StatusCreated, s.do(http.MethodPost, "/pullRequest/create", req, &res))
+
+	return res
+}
+
+type member struct {
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+func backend() []member {
+	return []member{
+		{UserId: "u1", Username: "Alice", IsActive: true},
+		{UserId: "u2", Username: "Bob", IsActive: true},
+		{UserId: "u3", Username: "Carol", IsActive: true},
+		{UserId: "u4", Username: "Dave", IsActive: true},
+	}
+}
+
+func TestE2E_RequestID(t *testing.T) {
+	s := newTestServer(t)
+
+	get := func(requestID string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, s.srv.URL+"/team/get?team_name=missing", nil)
+		require.NoError(t, err)
+		if requestID != "" {
+			req.Header.Set(v1.RequestIDHeader, requestID)
+		}
+
+		resp, err := s.srv.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		return resp
+	}
+
+	resp := get("trace-42")
+	require.Equal(t, "trace-42", resp.Header.Get(v1.RequestIDHeader))
+
+	generated := get("").Header.Get(v1.RequestIDHeader)
+	require.Len(t, generated, 36)
+
+	replaced := get("bad id; DROP TABLE").Header.Get(v1.RequestIDHeader)
+	require.NotEqual(t, "bad id; DROP TABLE", replaced)
+	require.Len(t, replaced, 36)
+
+	require.Equal(t, []string{"trace-42", generated, replaced}, s.logger.errorRequestIDs())
+}
+
+func TestE2E_AdminLogLevel(t *testing.T) {
+	s := newTestServer(t)
+
+	call := func(method, token string, body any) (int, v1.LogLevelRes) {
+		var raw []byte
+		if body != nil {
+			var err error
+			raw, err = json.Marshal(body)
+			require.NoError(t, err)
+		}
+
+		req, err := http.NewRequest(method, s.srv.URL+"/admin/log/level", bytes.NewReader(raw))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := s.srv.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var res v1.LogLevelRes
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		}
+		return resp.StatusCode, res
+	}
+
+	code, _ := call(http.MethodGet, "", nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = call(http.MethodGet, "wrong", nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code, res := call(http.MethodGet, testAdminToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, v1.LogLevelRes{Level: "debug", Packages: map[string]string{}}, res)
+
+	code, res = call(http.MethodPut, testAdminToken, map[string]string{"level": "warn", "package": "http"})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"http": "warn"}, res.Packages)
+
+	code, res = call(http.MethodPut, testAdminToken, map[string]string{"level": "error"})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "error", res.Level)
+
+	code, res = call(http.MethodPut, testAdminToken, map[string]string{"package": "http"})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Packages)
+
+	code, _ = call(http.MethodPut, testAdminToken, map[string]string{"level": "loud"})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestE2E_Health(t *testing.T) {
+	s := newTestServer(t)
+
+	var live health.Report
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/healthz", nil, &live))
+	require.Equal(t, health.StatusUp, live.Status)
+
+	var ready health.Report
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/readyz", nil, &ready))
+	require.Equal(t, health.StatusUp, ready.Status)
+	require.Equal(t, map[string]health.Component{
+		"server":   {Status: health.StatusUp},
+		"database": {Status: health.StatusUp},
+		"schema":   {Status: health.StatusUp},
+	}, ready.Components)
+
+	s.monitor.SetDraining()
+
+	require.Equal(t, http.StatusServiceUnavailable, s.do(http.MethodGet, "/readyz", nil, &ready))
+	require.Equal(t, health.StatusDraining, ready.Status)
+	require.Equal(t, health.StatusDraining, ready.Components["server"].Status)
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/healthz", nil, &live))
+}
+
+func TestE2E_TeamAdd(t *testing.T) {
+	s := newTestServer(t)
+
+	var res v1.TeamAddRes
+	req := map[string]any{"team_name": "backend", "members": backend()}
+	require.Equal(t, http.StatusCreated, s.do(http.MethodPost, "/team/add", req, &res))
+	require.Equal(t, "backend", res.Team.TeamName)
+	require.Len(t, res.Team.Members, 4)
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.Equal(t, "backend", team.TeamName)
+	require.Len(t, team.Members, 4)
+
+	s.expectError(http.MethodPost, "/team/add", req, http.StatusBadRequest, e.TEAM_EXISTS)
+	s.expectError(http.MethodPost, "/team/add", map[string]any{"team_name": "empty", "members": []member{}},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/add", map[string]any{"team_name": "bad", "members": []member{{UserId: "x1", Username: "X"}}},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/add", `{"team_name":`, http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodGet, "/team/get?team_name=unknown", nil, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodGet, "/team/get", nil, http.StatusBadRequest, e.BAD_REQUEST)
+}
+
+func TestE2E_Users(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	var res v1.SetIsActiveRes
+	req := map[string]any{"user_id": "u2", "is_active": false}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", req, &res))
+	require.Equal(t, v1.UserDTO{Id: "u2", Username: "Bob", TeamName: "backend", Teams: []string{"backend"}, IsActive: false}, res.User)
+
+	s.expectError(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": "u999", "is_active": true},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": "u1"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+
+	for _, reviewerId := range pr.PullRequest.AssignedReviewers {
+		var review v1.GetReviewRes
+		require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+reviewerId, nil, &review))
+		require.Equal(t, reviewerId, review.UserId)
+		require.Equal(t, []v1.PullRequestShort{{Id: "pr-1001", Name: "Add login", AuthorId: "u1", Status: "OPEN"}}, review.PullRequests)
+	}
+
+	var review v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id=u1", nil, &review))
+	require.Empty(t, review.PullRequests)
+
+	s.expectError(http.MethodGet, "/users/getReview?user_id=u999", nil, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodGet, "/users/getReview?user_id=bad", nil, http.StatusBadRequest, e.BAD_REQUEST)
+}
+
+func TestE2E_PullRequestCreate(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("solo", member{UserId: "u10", Username: "Solo", IsActive: true})
+
+	res := s.createPR("pr-1001", "Add login", "u1")
+	require.Equal(t, "pr-1001", res.PullRequest.Id)
+	require.Equal(t, "u1", res.PullRequest.AuthorId)
+	require.EqualValues(t, "OPEN", res.PullRequest.Status)
+	require.Len(t, res.PullRequest.AssignedReviewers, 2)
+	require.NotContains(t, res.PullRequest.AssignedReviewers, "u1")
+	for _, reviewerId := range res.PullRequest.AssignedReviewers {
+		require.Contains(t, []string{"u2", "u3", "u4"}, reviewerId)
+	}
+
+	solo := s.createPR("pr-1002", "Lonely change", "u10")
+	require.Empty(t, solo.PullRequest.AssignedReviewers)
+
+	req := map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Again", "author_id": "u1"}
+	s.expectError(http.MethodPost, "/pullRequest/create", req, http.StatusBadRequest, e.PR_EXISTS)
+
+	req = map[string]any{"pull_request_id": "pr-1003", "pull_request_name": "Ghost", "author_id": "u999"}
+	s.expectError(http.MethodPost, "/pullRequest/create", req, http.StatusNotFound, e.NOT_FOUND)
+
+	req = map[string]any{"pull_request_id": "pr-1", "pull_request_name": "Bad id", "author_id": "u1"}
+	s.expectError(http.MethodPost, "/pullRequest/create", req, http.StatusBadRequest, e.BAD_REQUEST)
+}
+
+func TestE2E_Reassign(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("trio",
+		member{UserId: "u7", Username: "Grace", IsActive: true},
+		member{UserId: "u8", Username: "Hank", IsActive: true},
+		member{UserId: "u9", Username: "Ivan", IsActive: true},
+	)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	oldReviewer := pr.PullRequest.AssignedReviewers[0]
+
+	var res v1.PullRequestReassignRes
+	req := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": oldReviewer}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/reassign", req, &res))
+	require.NotEqual(t, oldReviewer, res.ReplacedBy)
+	require.NotEqual(t, "u1", res.ReplacedBy)
+	require.NotContains(t, res.Pr.AssignedReviewers, oldReviewer)
+	require.Contains(t, res.Pr.AssignedReviewers, res.ReplacedBy)
+
+	s.expectError(http.MethodPost, "/pullRequest/reassign", req, http.StatusConflict, e.NOT_ASSIGNED)
+
+	// Both remaining members of the trio are already reviewing their teammate's PR.
+	s.createPR("pr-1002", "Tiny fix", "u7")
+	noCandidate := map[string]any{"pull_request_id": "pr-1002", "old_reviewer_id": "u8"}
+	s.expectError(http.MethodPost, "/pullRequest/reassign", noCandidate, http.StatusConflict, e.NO_CANDIDATE)
+
+	unknownPR := map[string]any{"pull_request_id": "pr-9999", "old_reviewer_id": "u2"}
+	s.expectError(http.MethodPost, "/pullRequest/reassign", unknownPR, http.StatusNotFound, e.NOT_FOUND)
+
+	unknownUser := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": "u999"}
+	s.expectError(http.MethodPost, "/pullRequest/reassign", unknownUser, http.StatusNotFound, e.NOT_FOUND)
+}
+
+func TestE2E_Merge(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+
+	var res v1.PullRequestMergeRes
+	req := map[string]any{"pull_request_id": "pr-1001"}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", req, &res))
+	require.EqualValues(t, "MERGED", res.PullRequest.Status)
+	require.NotNil(t, res.PullRequest.MergedAt)
+	require.ElementsMatch(t, pr.PullRequest.AssignedReviewers, res.PullRequest.AssignedReviewers)
+
+	var again v1.PullRequestMergeRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", req, &again))
+	require.EqualValues(t, "MERGED", again.PullRequest.Status)
+
+	reassign := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": pr.PullRequest.AssignedReviewers[0]}
+	s.expectError(http.MethodPost, "/pullRequest/reassign", reassign, http.StatusConflict, e.PR_MERGED)
+
+	s.expectError(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-9999"},
+		http.StatusNotFound, e.NOT_FOUND)
+}
+
+func TestE2E_DeactivateMembers(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", append(backend(),
+		member{UserId: "u5", Username: "Eve", IsActive: true},
+		member{UserId: "u6", Username: "Frank", IsActive: true},
+	)...)
+
+	first := s.createPR("pr-1001", "Add login", "u1")
+	s.createPR("pr-1002", "Fix bug", "u2")
+
+	leaving := first.PullRequest.AssignedReviewers
+
+	var res v1.DeactivateMembersRes
+	req := map[string]any{"team_name": "backend", "members": leaving}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/deactivate", req, &res))
+	require.Equal(t, "backend", res.TeamName)
+	require.Len(t, res.DeactivatedMembers, len(leaving))
+	for _, m := range res.DeactivatedMembers {
+		require.False(t, *m.IsActive)
+	}
+
+	for _, pr := range res.UpdPrs {
+		require.Len(t, pr.AssignedReviewers, 2)
+		require.NotContains(t, pr.AssignedReviewers, pr.AuthorId)
+		for _, id := range leaving {
+			require.NotContains(t, pr.AssignedReviewers, id)
+		}
+	}
+
+	for _, id := range leaving {
+		var review v1.GetReviewRes
+		require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+id, nil, &review))
+		require.Empty(t, review.PullRequests)
+	}
+
+	foreign := map[string]any{"team_name": "backend", "members": []string{"u999"}}
+	s.expectError(http.MethodPost, "/team/deactivate", foreign, http.StatusBadRequest, e.BAD_REQUEST)
+
+	unknownTeam := map[string]any{"team_name": "unknown", "members": []string{"u1"}}
+	s.expectError(http.MethodPost, "/team/deactivate", unknownTeam, http.StatusNotFound, e.NOT_FOUND)
+
+	everyone := map[string]any{"team_name": "backend", "members": []string{"u1", "u2", "u3", "u4", "u5", "u6"}}
+	s.expectError(http.MethodPost, "/team/deactivate", everyone, http.StatusConflict, e.NO_CANDIDATE)
+}
+
+func TestE2E_TeamMembers(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	var added v1.AddMembersRes
+	add := map[string]any{"team_name": "backend", "members": []member{{UserId: "u5", Username: "Eve", IsActive: true}}}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/add", add, &added))
+	require.Equal(t, "backend", added.Team.TeamName)
+	require.Len(t, added.Team.Members, 5)
+
+	s.expectError(http.MethodPost, "/team/members/add", map[string]any{"team_name": "unknown", "members": []member{{UserId: "u6", Username: "Frank", IsActive: true}}},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/team/members/add", map[string]any{"team_name": "backend", "members": []member{}},
+		http.StatusBadRequest, e.BAD_REQUEST)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	leaving := pr.PullRequest.AssignedReviewers[0]
+
+	var removed v1.RemoveMembersRes
+	remove := map[string]any{"team_name": "backend", "members": []string{leaving}}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/remove", remove, &removed))
+	require.Equal(t, "backend", removed.TeamName)
+	require.Len(t, removed.RemovedMembers, 1)
+	require.Equal(t, leaving, removed.RemovedMembers[0].Id)
+	require.Len(t, removed.UpdPrs, 1)
+	require.Len(t, removed.UpdPrs[0].AssignedReviewers, 2)
+	require.NotContains(t, removed.UpdPrs[0].AssignedReviewers, leaving)
+	require.NotContains(t, removed.UpdPrs[0].AssignedReviewers, "u1")
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.Len(t, team.Members, 4)
+
+	var review v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+leaving, nil, &review))
+	require.Empty(t, review.PullRequests)
+
+	// The removed user keeps their account outside any team.
+	var user v1.SetIsActiveRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": leaving, "is_active": true}, &user))
+	require.Empty(t, user.User.TeamName)
+
+	s.expectError(http.MethodPost, "/team/members/remove", remove, http.StatusBadRequest, e.BAD_REQUEST)
+
+	s.addTeam("pair", member{UserId: "u7", Username: "Grace", IsActive: true}, member{UserId: "u8", Username: "Heidi", IsActive: true})
+	s.createPR("pr-1002", "Pair work", "u7")
+	s.expectError(http.MethodPost, "/team/members/remove", map[string]any{"team_name": "pair", "members": []string{"u8"}},
+		http.StatusConflict, e.NO_CANDIDATE)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1002"}, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/remove", map[string]any{"team_name": "pair", "members": []string{"u7", "u8"}}, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=pair", nil, &team))
+	require.Empty(t, team.Members)
+}
+
+func TestE2E_UserTransfer(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("frontend", member{UserId: "u5", Username: "Eve", IsActive: true}, member{UserId: "u6", Username: "Frank", IsActive: true})
+
+	// Creating a team or adding members does not pull users out of their team.
+	s.expectError(http.MethodPost, "/team/add", map[string]any{"team_name": "mobile", "members": []member{{UserId: "u2", Username: "Bob", IsActive: true}}},
+		http.StatusConflict, e.USER_IN_TEAM)
+	s.expectError(http.MethodGet, "/team/get?team_name=mobile", nil, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/team/members/add", map[string]any{"team_name": "frontend", "members": []member{{UserId: "u2", Username: "Bob", IsActive: true}}},
+		http.StatusConflict, e.USER_IN_TEAM)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	reviewer := pr.PullRequest.AssignedReviewers[0]
+
+	var moved v1.TransferUserRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/transfer", map[string]any{"user_id": reviewer, "team_name": "frontend"}, &moved))
+	require.Equal(t, "frontend", moved.User.TeamName)
+	require.Equal(t, "backend", moved.FromTeam)
+	require.Len(t, moved.ReassignedPrs, 1)
+	require.NotContains(t, moved.ReassignedPrs[0].AssignedReviewers, reviewer)
+	require.NotContains(t, moved.ReassignedPrs[0].AssignedReviewers, "u1")
+	require.Empty(t, moved.AuthoredPrs)
+
+	var review v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+reviewer, nil, &review))
+	require.Empty(t, review.PullRequests)
+
+	// Moving the author brings reviewers of their open PRs into the new team.
+	frontend := []string{"u5", "u6", reviewer}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u1", "team_name": "frontend"}, &moved))
+	require.Empty(t, moved.ReassignedPrs)
+	require.Len(t, moved.AuthoredPrs, 1)
+	require.Len(t, moved.AuthoredPrs[0].AssignedReviewers, 2)
+	require.Subset(t, frontend, moved.AuthoredPrs[0].AssignedReviewers)
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=frontend", nil, &team))
+	require.Len(t, team.Members, 4)
+
+	// Transferring to the current team changes nothing.
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u1", "team_name": "frontend"}, &moved))
+	require.Equal(t, "frontend", moved.FromTeam)
+	require.Empty(t, moved.AuthoredPrs)
+
+	s.expectError(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u99", "team_name": "frontend"}, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u1", "team_name": "unknown"}, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u1"}, http.StatusBadRequest, e.BAD_REQUEST)
+
+	s.addTeam("pair", member{UserId: "u7", Username: "Grace", IsActive: true}, member{UserId: "u8", Username: "Heidi", IsActive: true})
+	s.createPR("pr-1002", "Pair work", "u7")
+	s.expectError(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u8", "team_name": "backend"}, http.StatusConflict, e.NO_CANDIDATE)
+}
+
+func TestToHTTPResponse(t *testing.T) {
+	tests := []struct {
+		err        error
+		statusCode int
+		code       string
+	}{
+		{e.ErrUserNotFound, http.StatusNotFound, e.NOT_FOUND},
+		{e.ErrTeamNotFound, http.StatusNotFound, e.NOT_FOUND},
+		{e.ErrUnauthorized, http.StatusNotFound, e.NOT_FOUND},
+		{e.ErrStatusNotFound, http.StatusNotFound, e.NOT_FOUND},
+		{e.ErrPRNotFound, http.StatusNotFound, e.NOT_FOUND},
+		{e.ErrTeamIsExists, http.StatusBadRequest, e.TEAM_EXISTS},
+		{e.ErrPRIsExists, http.StatusBadRequest, e.PR_EXISTS},
+		{e.ErrPrMerged, http.StatusConflict, e.PR_MERGED},
+		{e.ErrPrReviewerNotAssigned, http.StatusConflict, e.NOT_ASSIGNED},
+		{e.ErrPrNoCandidate, http.StatusConflict, e.NO_CANDIDATE},
+		{e.ErrEmptyMembers, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrMissingMemberId, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrInvalidRequestBody, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrInvalidMember, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrUserInAnotherTeam, http.StatusConflict, e.USER_IN_TEAM},
+		{e.ErrTeamNotEmpty, http.StatusConflict, e.TEAM_NOT_EMPTY},
+		{e.ErrOpenReviews, http.StatusConflict, e.OPEN_REVIEWS},
+		{e.ErrSameTeam, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrNotTeamMember, http.StatusBadRequest, e.BAD_REQUEST},
+		{e.ErrTeamCycle, http.StatusBadRequest, e.BAD_REQUEST},
+		{&e.ValidationError{Field: "author_id", Rule: "required", Message: "is required"}, http.StatusBadRequest, e.BAD_REQUEST},
+		{errors.New("boom"), http.StatusInternalServerError, e.SERVER_ERR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.err.Error(), func(t *testing.T) {
+			statusCode, code, _ := v1.ToHTTPResponse(e.Wrap("op", tt.err))
+			require.Equal(t, tt.statusCode, statusCode)
+			require.Equal(t, tt.code, code)
+		})
+	}
+}
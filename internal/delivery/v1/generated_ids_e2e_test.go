@@ -0,0 +1,65 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	generatedUserId = regexp.MustCompile(`^u-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	generatedPRId   = regexp.MustCompile(`^pr-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+)
+
+func TestE2E_GeneratedIds(t *testing.T) {
+	s := newTestServer(t)
+
+	var team v1.TeamAddRes
+	require.Equal(t, http.StatusCreated, s.do(http.MethodPost, "/team/add", map[string]any{
+		"team_name": "backend",
+		"members":   []member{{UserId: "u1", Username: "Alice", IsActive: true}, {Username: "Bob", IsActive: true}, {Username: "Carol", IsActive: true}},
+	}, &team))
+	require.Len(t, team.Team.Members, 3)
+
+	generated := make([]string, 0, 2)
+	for _, m := range team.Team.Members {
+		if m.Id != "u1" {
+			require.Regexp(t, generatedUserId, m.Id)
+			generated = append(generated, m.Id)
+		}
+	}
+	require.Len(t, generated, 2)
+	require.NotEqual(t, generated[0], generated[1])
+
+	var added v1.AddMembersRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "backend", "members": []member{{Username: "Dave", IsActive: true}}}, &added))
+	require.Len(t, added.Team.Members, 4)
+
+	// Generated ids work wherever an external one does.
+	var user v1.GetUserRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id="+generated[0], nil, &user))
+	require.Equal(t, "backend", user.User.TeamName)
+
+	pr := s.createPR("", "Add login", generated[0])
+	require.Regexp(t, generatedPRId, pr.PullRequest.Id)
+	require.Len(t, pr.PullRequest.AssignedReviewers, 2)
+
+	var merged v1.PullRequestMergeRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": pr.PullRequest.Id}, &merged))
+	require.EqualValues(t, "MERGED", merged.PullRequest.Status)
+
+	external := s.createPR("pr-1001", "Add logout", "u1")
+	require.Equal(t, "pr-1001", external.PullRequest.Id)
+
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "backend", "members": []member{{Username: "Eve", IsActive: true}}},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodGet, "/users/get?user_id=u-not-a-uuid", nil, http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-0190a1b2-c3d4-4e5f-8a6b-7c8d9e0f1a2b", "pull_request_name": "Not v7", "author_id": "u1"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+}
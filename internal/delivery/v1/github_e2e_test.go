@@ -0,0 +1,170 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/domain"
+	"avito-internship/internal/github"
+	"avito-internship/pkg/e"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sendGitHub posts a recorded payload from testdata/github as GitHub would.
+func (s *testServer) sendGitHub(event, fixture, secret string, out any) int {
+	s.t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "github", fixture))
+	require.NoError(s.t, err)
+
+	req, err := http.NewRequest(http.MethodPost, s.srv.URL+"/github/webhook", bytes.NewReader(body))
+	require.NoError(s.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(github.HeaderEvent, event)
+	req.Header.Set(github.HeaderDelivery, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	req.Header.Set(github.HeaderSignature, github.Sign(secret, body))
+
+	resp, err := s.srv.Client().Do(req)
+	require.NoError(s.t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(s.t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+func TestE2E_GitHubWebhook(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	require.Equal(t, http.StatusUnauthorized, s.sendGitHub("pull_request", "pull_request_opened.json", "wrong-secret", nil))
+
+	tests := []struct {
+		name     string
+		event    string
+		fixture  string
+		expected v1.GitHubWebhookRes
+	}{
+		{
+			name:     "ping",
+			event:    "ping",
+			fixture:  "ping.json",
+			expected: v1.GitHubWebhookRes{Event: "ping", Result: "pong"},
+		},
+		{
+			name:    "draft is skipped",
+			event:   "pull_request",
+			fixture: "pull_request_opened_draft.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "opened", Result: "ignored",
+				Reason: "draft pull request"},
+		},
+		{
+			name:    "ready for review creates the PR",
+			event:   "pull_request",
+			fixture: "pull_request_ready_for_review.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "ready_for_review", PullRequestId: "gh-1347",
+				Result: "created"},
+		},
+		{
+			name:    "reopened known PR",
+			event:   "pull_request",
+			fixture: "pull_request_reopened.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "reopened", PullRequestId: "gh-1347",
+				Result: "unchanged"},
+		},
+		{
+			name:    "opened maps the login case-insensitively",
+			event:   "pull_request",
+			fixture: "pull_request_opened.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "opened", PullRequestId: "gh-1348",
+				Result: "created"},
+		},
+		{
+			name:    "redelivered opened",
+			event:   "pull_request",
+			fixture: "pull_request_opened.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "opened", PullRequestId: "gh-1348",
+				Result: "unchanged"},
+		},
+		{
+			name:    "merged",
+			event:   "pull_request",
+			fixture: "pull_request_closed_merged.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "closed", PullRequestId: "gh-1348",
+				Result: "merged"},
+		},
+		{
+			name:    "redelivered merged",
+			event:   "pull_request",
+			fixture: "pull_request_closed_merged.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "closed", PullRequestId: "gh-1348",
+				Result: "merged"},
+		},
+		{
+			name:    "merge of a PR opened before the webhook",
+			event:   "pull_request",
+			fixture: "pull_request_closed_merged_unseen.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "closed", PullRequestId: "gh-1350",
+				Result: "merged"},
+		},
+		{
+			name:    "closed without merge",
+			event:   "pull_request",
+			fixture: "pull_request_closed_unmerged.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "closed", Result: "ignored",
+				Reason: "closed without merge"},
+		},
+		{
+			name:    "other repository",
+			event:   "pull_request",
+			fixture: "pull_request_opened_other_repo.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "opened", Result: "ignored",
+				Reason: `repository "avito-tech/other" is not tracked`},
+		},
+		{
+			name:    "unhandled action",
+			event:   "pull_request",
+			fixture: "pull_request_labeled.json",
+			expected: v1.GitHubWebhookRes{Event: "pull_request", Action: "labeled", Result: "ignored",
+				Reason: `action "labeled" is not handled`},
+		},
+		{
+			name:     "unhandled event",
+			event:    "push",
+			fixture:  "ping.json",
+			expected: v1.GitHubWebhookRes{Event: "push", Result: "ignored", Reason: "event is not handled"},
+		},
+	}
+
+	for _, tt := range tests {
+		var res v1.GitHubWebhookRes
+		require.Equal(t, http.StatusOK, s.sendGitHub(tt.event, tt.fixture, testGitHubSecret, &res), tt.name)
+		require.Equal(t, tt.expected, res, tt.name)
+	}
+
+	var errRes v1.ErrorResponse
+	require.Equal(t, http.StatusUnprocessableEntity,
+		s.sendGitHub("pull_request", "pull_request_opened_unknown_author.json", testGitHubSecret, &errRes))
+	require.Equal(t, e.UNKNOWN_LOGIN, errRes.Error.Code)
+
+	merge := func(id string) v1.PullRequestMergeRes {
+		var res v1.PullRequestMergeRes
+		require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": id}, &res))
+		return res
+	}
+	require.Equal(t, "u1", merge("gh-1350").PullRequest.AuthorId)
+	merged := merge("gh-1348")
+	require.Equal(t, domain.MERGED, merged.PullRequest.Status)
+	require.Equal(t, "u2", merged.PullRequest.AuthorId)
+
+	require.Equal(t, "u1", merge("gh-1347").PullRequest.AuthorId)
+	require.Equal(t, http.StatusNotFound, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "gh-1351"}, nil),
+		"PRs of unmapped authors are not created")
+}
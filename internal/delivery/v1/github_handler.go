@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"avito-internship/internal/github"
+	"avito-internship/pkg/e"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxGitHubPayload caps the request body; GitHub itself caps payloads at 25 MB,
+// but pull_request payloads stay far below this.
+const maxGitHubPayload = 5 << 20
+
+type GitHubHandler struct {
+	ingester   *github.Ingester
+	reconciler *github.Reconciler
+	secret     string
+	middleware *Middleware
+}
+
+// NewGitHubHandler builds the handler; reconciler is nil when reconciliation
+// is not configured, and its endpoint is then not registered.
+func NewGitHubHandler(ingester *github.Ingester, reconciler *github.Reconciler, secret string, middleware *Middleware) *GitHubHandler {
+	return &GitHubHandler{
+		ingester:   ingester,
+		reconciler: reconciler,
+		secret:     secret,
+		middleware: middleware,
+	}
+}
+
+func (h *GitHubHandler) Init(r *gin.Engine) {
+	r.POST("/github/webhook", h.webhook)
+
+	if h.reconciler != nil {
+		admin := r.Group("/admin/github", h.middleware.AdminMiddleware(), h.middleware.Idempotency())
+		admin.POST("/reconcile", h.reconcile)
+	}
+}
+
+func (h *GitHubHandler) reconcile(c *gin.Context) {
+	report, err := h.reconciler.Reconcile(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryGitHubReconcileRes(report))
+}
+
+func (h *GitHubHandler) webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxGitHubPayload))
+	if err != nil {
+		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		return
+	}
+
+	// The signature covers the raw body, so it is checked before parsing.
+	if !github.Verify(h.secret, body, c.GetHeader(github.HeaderSignature)) {
+		h.middleware.logger.ErrorfContext(c.Request.Context(), e.ErrUnauthorized, "github webhook delivery=%s: invalid signature",
+			c.GetHeader(github.HeaderDelivery))
+		abortUnauthorized(c)
+		return
+	}
+
+	event := c.GetHeader(github.HeaderEvent)
+	switch event {
+	case github.EventPing:
+		c.JSON(http.StatusOK, GitHubWebhookRes{Event: event, Result: "pong"})
+		return
+	case github.EventPullRequest:
+	default:
+		c.JSON(http.StatusOK, GitHubWebhookRes{Event: event, Result: string(github.ResultIgnored), Reason: "event is not handled"})
+		return
+	}
+
+	var payload github.PullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		return
+	}
+
+	outcome, err := h.ingester.HandlePullRequest(c.Request.Context(), payload)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, GitHubWebhookRes{
+		Event:         event,
+		Action:        payload.Action,
+		PullRequestId: outcome.PullRequestId,
+		Result:        string(outcome.Result),
+		Reason:        outcome.Reason,
+	})
+}
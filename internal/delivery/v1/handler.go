@@ -23,26 +23,41 @@ func NewHandler(userUc usecase.UserUC, teamUc usecase.TeamUC, prUC usecase.PullR
 }
 
 func (h *Handler) Init(r *gin.Engine) {
-	r.Use(h.middleware.ErrorMiddleware())
+	r.Use(h.middleware.RequestID(), h.middleware.AccessLog(), h.middleware.ErrorMiddleware())
 
-	team := r.Group("/team")
+	team := r.Group("/team", h.middleware.Idempotency())
 	{
 		team.POST("/add", h.addTeam)
 		team.GET("/get", h.getTeam)
 		team.POST("/deactivate", h.deactivateMembers)
+		team.POST("/members/add", h.addMembers)
+		team.POST("/members/remove", h.removeMembers)
+		team.PUT("/sync", h.syncTeam)
+		team.POST("/rename", h.renameTeam)
+		team.POST("/setParent", h.setTeamParent)
+		team.POST("/delete", h.deleteTeam)
 	}
 
-	users := r.Group("/users")
+	users := r.Group("/users", h.middleware.Idempotency())
 	{
 		users.POST("/setIsActive", h.setIsActive)
 		users.GET("/getReview", h.getReview)
+		users.POST("/transfer", h.transferUser)
+		users.POST("/joinTeam", h.joinTeam)
+		users.GET("/get", h.getUser)
+		users.GET("/list", h.listUsers)
 	}
 
-	pullRequest := r.Group("/pullRequest")
+	pullRequest := r.Group("/pullRequest", h.middleware.Idempotency())
 	{
 		pullRequest.POST("/create", h.pullRequestCreate)
 		pullRequest.POST("/merge", h.pullRequestMerge)
 		pullRequest.POST("/reassign", h.reviewerReassign)
 	}
 
+	admin := r.Group("/admin/users", h.middleware.AdminMiddleware(), h.middleware.Idempotency())
+	{
+		admin.POST("/anonymize", h.anonymizeUser)
+	}
+
 }
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"avito-internship/internal/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	monitor *health.Monitor
+}
+
+func NewHealthHandler(monitor *health.Monitor) *HealthHandler {
+	return &HealthHandler{monitor: monitor}
+}
+
+func (h *HealthHandler) Init(r *gin.Engine) {
+	r.GET("/healthz", h.healthz)
+	r.GET("/readyz", h.readyz)
+}
+
+func (h *HealthHandler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, h.monitor.Liveness())
+}
+
+func (h *HealthHandler) readyz(c *gin.Context) {
+	report := h.monitor.Readiness(c.Request.Context())
+
+	code := http.StatusOK
+	if report.Status != health.StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, report)
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"avito-internship/pkg/e"
+	"avito-internship/pkg/validator"
 	"errors"
 	"net/http"
 )
@@ -24,16 +25,44 @@ func NewErrorResponse(code, message string) *ErrorResponse {
 	}
 }
 
+// invalidRequest wraps a binding error. Validation failures keep the failed
+// field and rule so the client sees which check rejected the request.
+func invalidRequest(err error) error {
+	if vErr := validator.Describe(err); vErr != nil {
+		return vErr
+	}
+
+	return e.Wrap(err.Error(), e.ErrInvalidRequestBody)
+}
+
 func ToHTTPResponse(err error) (int, string, string) {
+	var vErr *e.ValidationError
+	if errors.As(err, &vErr) {
+		return http.StatusBadRequest, e.BAD_REQUEST, vErr.Error()
+	}
+
 	switch {
 	case errors.Is(err, e.ErrUserNotFound),
 		errors.Is(err, e.ErrTeamNotFound),
 		errors.Is(err, e.ErrUnauthorized),
 		errors.Is(err, e.ErrStatusNotFound),
-		errors.Is(err, e.ErrPRNotFound):
+		errors.Is(err, e.ErrPRNotFound),
+		errors.Is(err, e.ErrWebhookNotFound):
 		return http.StatusNotFound, e.NOT_FOUND, e.ErrResourceNotFound.Error()
 	case errors.Is(err, e.ErrTeamIsExists):
 		return http.StatusBadRequest, e.TEAM_EXISTS, e.ErrTeamIsExists.Error()
+	case errors.Is(err, e.ErrTeamNotEmpty):
+		return http.StatusConflict, e.TEAM_NOT_EMPTY, e.ErrTeamNotEmpty.Error()
+	case errors.Is(err, e.ErrOpenReviews):
+		return http.StatusConflict, e.OPEN_REVIEWS, e.ErrOpenReviews.Error()
+	case errors.Is(err, e.ErrSameTeam):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrSameTeam.Error()
+	case errors.Is(err, e.ErrNotTeamMember):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrNotTeamMember.Error()
+	case errors.Is(err, e.ErrTeamCycle):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrTeamCycle.Error()
+	case errors.Is(err, e.ErrUserInAnotherTeam):
+		return http.StatusConflict, e.USER_IN_TEAM, e.ErrUserInAnotherTeam.Error()
 	case errors.Is(err, e.ErrPRIsExists):
 		return http.StatusBadRequest, e.PR_EXISTS, e.ErrPRIsExists.Error()
 	case errors.Is(err, e.ErrPrMerged):
@@ -44,6 +73,22 @@ func ToHTTPResponse(err error) (int, string, string) {
 		return http.StatusConflict, e.NO_CANDIDATE, e.ErrPrNoCandidate.Error()
 	case errors.Is(err, e.ErrEmptyMembers):
 		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrEmptyMembers.Error()
+	case errors.Is(err, e.ErrDuplicateMembers):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrDuplicateMembers.Error()
+	case errors.Is(err, e.ErrMissingMemberId):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrMissingMemberId.Error()
+	case errors.Is(err, e.ErrInvalidWebhookURL):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrInvalidWebhookURL.Error()
+	case errors.Is(err, e.ErrUnknownEventType):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrUnknownEventType.Error()
+	case errors.Is(err, e.ErrUnknownLogin):
+		return http.StatusUnprocessableEntity, e.UNKNOWN_LOGIN, e.ErrUnknownLogin.Error()
+	case errors.Is(err, e.ErrInvalidIdempotencyKey):
+		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrInvalidIdempotencyKey.Error()
+	case errors.Is(err, e.ErrIdempotencyKeyMismatch):
+		return http.StatusUnprocessableEntity, e.IDEMPOTENCY_KEY_REUSED, e.ErrIdempotencyKeyMismatch.Error()
+	case errors.Is(err, e.ErrIdempotencyKeyInUse):
+		return http.StatusConflict, e.IDEMPOTENCY_KEY_IN_USE, e.ErrIdempotencyKeyInUse.Error()
 	case errors.Is(err, e.ErrInvalidRequestBody):
 		return http.StatusBadRequest, e.BAD_REQUEST, e.ErrInvalidRequestBody.Error()
 	case errors.Is(err, e.ErrInvalidMember):
@@ -0,0 +1,89 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	v "avito-internship/pkg/validator"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *testServer) expectValidationError(method, path string, body any, message string) {
+	s.t.Helper()
+
+	var res v1.ErrorResponse
+	require.Equal(s.t, http.StatusBadRequest, s.do(method, path, body, &res))
+	require.Equal(s.t, e.BAD_REQUEST, res.Error.Code)
+	require.Contains(s.t, res.Error.Message, message)
+}
+
+func TestE2E_ValidationErrorsNameTheRule(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	s.expectValidationError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Add login"},
+		`author_id: rule "required" failed: is required`)
+	s.expectValidationError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1", "pull_request_name": "Add login", "author_id": "u1"},
+		`pull_request_id: rule "newprid" failed: must match`)
+	s.expectValidationError(http.MethodPost, "/pullRequest/merge",
+		map[string]any{"pull_request_id": "pr-1"},
+		`pull_request_id: rule "prid" failed: must match pr-(100[1-9]|10[1-9][0-9]|1[1-9][0-9]{2}|[2-9][0-9]{3}) or be a generated pr-<UUIDv7>, or be an ingested gh-<number>, and be at most 50 characters long`)
+	s.expectValidationError(http.MethodPost, "/team/add",
+		map[string]any{"team_name": "frontend", "members": []member{{UserId: "u1000", Username: "Eve", IsActive: true}}},
+		`members[0].user_id: rule "newuserid" failed`)
+	s.expectValidationError(http.MethodGet, "/users/get?user_id=u1000", nil,
+		`user_id: rule "userid" failed: must match`)
+	s.expectValidationError(http.MethodGet, "/users/list?limit=501", nil,
+		`limit: rule "max" failed: must be at most 500`)
+}
+
+func TestE2E_ConfiguredIdFormats(t *testing.T) {
+	s := newTestServer(t)
+
+	cfg := v.DefaultConfig()
+	cfg.UserId = `emp-[0-9]{4}`
+	cfg.Namespaces = map[string]v.Namespace{
+		"github": {UserId: `[A-Za-z0-9-]+`, PullRequestId: `[\w.-]+/[\w.-]+#[1-9][0-9]*`},
+	}
+	require.NoError(t, v.RegisterValidators(cfg))
+	t.Cleanup(func() { require.NoError(t, v.RegisterValidators(v.DefaultConfig())) })
+
+	s.addTeam("backend",
+		member{UserId: "emp-0001", Username: "Alice", IsActive: true},
+		member{UserId: "github:octocat", Username: "Octocat", IsActive: true},
+		member{UserId: "github:hubot", Username: "Hubot", IsActive: true},
+	)
+
+	pr := s.createPR("github:owner/repo#123", "Add login", "github:octocat")
+	require.Equal(t, "github:owner/repo#123", pr.PullRequest.Id)
+	require.ElementsMatch(t, []string{"emp-0001", "github:hubot"}, pr.PullRequest.AssignedReviewers)
+
+	var user v1.GetUserRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id="+url.QueryEscape("github:octocat"), nil, &user))
+	require.Equal(t, 1, user.AuthoredPrs)
+
+	// The default user format is replaced, generated ids are still accepted.
+	s.expectValidationError(http.MethodPost, "/team/add",
+		map[string]any{"team_name": "frontend", "members": []member{{UserId: "u1", Username: "Bob", IsActive: true}}},
+		`members[0].user_id: rule "newuserid" failed: must match emp-[0-9]{4} or be a generated u-<UUIDv7>, or be one of github:<id>`)
+	s.createPR("", "Add logout", "emp-0001")
+
+	// Ids longer than the id columns are rejected before they reach the database.
+	s.expectValidationError(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "backend", "members": []member{{UserId: "github:" + strings.Repeat("a", 44), Username: "Bob", IsActive: true}}},
+		`members[0].user_id: rule "newuserid" failed: must match emp-[0-9]{4} or be a generated u-<UUIDv7>, or be one of github:<id>, not start with tombstone-, and be at most 50 characters long`)
+
+	// Unknown namespaces and ids that break the namespace format are rejected.
+	s.expectValidationError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "gitlab:owner/repo#1", "pull_request_name": "Fix", "author_id": "emp-0001"},
+		`pull_request_id: rule "newprid" failed`)
+	s.expectValidationError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "github:owner/repo#0", "pull_request_name": "Fix", "author_id": "emp-0001"},
+		`pull_request_id: rule "newprid" failed`)
+}
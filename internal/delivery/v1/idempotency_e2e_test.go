@@ -0,0 +1,110 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type idempotentResponse struct {
+	status   int
+	replayed bool
+	body     []byte
+}
+
+func (r idempotentResponse) errorCode(t *testing.T) string {
+	t.Helper()
+
+	var res v1.ErrorResponse
+	require.NoError(t, json.Unmarshal(r.body, &res))
+	return res.Error.Code
+}
+
+// doIdempotent sends a POST with an Idempotency-Key header.
+func (s *testServer) doIdempotent(path, key, token string, body any) idempotentResponse {
+	s.t.Helper()
+
+	raw, err := json.Marshal(body)
+	require.NoError(s.t, err)
+
+	req, err := http.NewRequest(http.MethodPost, s.srv.URL+path, bytes.NewReader(raw))
+	require.NoError(s.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(v1.IdempotencyKeyHeader, key)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.srv.Client().Do(req)
+	require.NoError(s.t, err)
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	require.NoError(s.t, err)
+
+	return idempotentResponse{
+		status:   resp.StatusCode,
+		replayed: resp.Header.Get(v1.IdempotentReplayedHeader) == "true",
+		body:     resBody,
+	}
+}
+
+func TestE2E_Idempotency(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	create := map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Add login", "author_id": "u1"}
+
+	first := s.doIdempotent("/pullRequest/create", "ci-run-1", "", create)
+	require.Equal(t, http.StatusCreated, first.status)
+	require.False(t, first.replayed)
+
+	retry := s.doIdempotent("/pullRequest/create", "ci-run-1", "", create)
+	require.Equal(t, http.StatusCreated, retry.status)
+	require.True(t, retry.replayed)
+	require.Equal(t, first.body, retry.body, "the retry gets the original reviewers")
+
+	s.expectError(http.MethodPost, "/pullRequest/create", create, http.StatusBadRequest, e.PR_EXISTS)
+
+	changed := map[string]any{"pull_request_id": "pr-1002", "pull_request_name": "Add login", "author_id": "u1"}
+	res := s.doIdempotent("/pullRequest/create", "ci-run-1", "", changed)
+	require.Equal(t, http.StatusUnprocessableEntity, res.status)
+	require.Equal(t, e.IDEMPOTENCY_KEY_REUSED, res.errorCode(t))
+
+	res = s.doIdempotent("/pullRequest/merge", "ci-run-1", "", map[string]any{"pull_request_id": "pr-1001"})
+	require.Equal(t, http.StatusUnprocessableEntity, res.status, "a key is bound to its endpoint")
+
+	// Client errors are answered the same way on retry, even once the state changes.
+	reassign := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": "u1"}
+	res = s.doIdempotent("/pullRequest/reassign", "ci-run-2", "", reassign)
+	require.Equal(t, http.StatusConflict, res.status)
+	require.Equal(t, e.NOT_ASSIGNED, res.errorCode(t))
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1001"}, nil))
+
+	res = s.doIdempotent("/pullRequest/reassign", "ci-run-2", "", reassign)
+	require.Equal(t, http.StatusConflict, res.status)
+	require.True(t, res.replayed)
+	require.Equal(t, e.NOT_ASSIGNED, res.errorCode(t))
+
+	res = s.doIdempotent("/pullRequest/create", strings.Repeat("k", 256), "", changed)
+	require.Equal(t, http.StatusBadRequest, res.status)
+	require.Equal(t, e.BAD_REQUEST, res.errorCode(t))
+
+	// Admin endpoints authenticate before replaying.
+	webhook := map[string]any{"url": "http://example.com/hook", "events": []string{"pr.created"}}
+	res = s.doIdempotent("/webhooks/create", "admin-1", testAdminToken, webhook)
+	require.Equal(t, http.StatusCreated, res.status)
+	require.Equal(t, http.StatusUnauthorized, s.doIdempotent("/webhooks/create", "admin-1", "", webhook).status)
+
+	replayed := s.doIdempotent("/webhooks/create", "admin-1", testAdminToken, webhook)
+	require.True(t, replayed.replayed)
+	require.Equal(t, res.body, replayed.body)
+}
@@ -1,6 +1,10 @@
 package v1
 
-import "avito-internship/internal/usecase"
+import (
+	"avito-internship/internal/github"
+	"avito-internship/internal/usecase"
+	"time"
+)
 
 func toUseCaseSetIsActiveReq(req SetIsActiveReq) usecase.SetIsActiveReq {
 	return usecase.SetIsActiveReq{
@@ -20,10 +24,90 @@ func toDeliveryUserDTO(u usecase.UserDTO) UserDTO {
 		Id:       u.Id,
 		Username: u.Username,
 		TeamName: u.TeamName,
+		Teams:    u.Teams,
 		IsActive: u.IsActive,
 	}
 }
 
+func toUseCaseTransferUserReq(req TransferUserReq) usecase.TransferUserReq {
+	return usecase.TransferUserReq{
+		UserId:   req.UserId,
+		TeamName: req.TeamName,
+	}
+}
+
+func toDeliveryTransferUserRes(res usecase.TransferUserRes) TransferUserRes {
+	return TransferUserRes{
+		User:          toDeliveryUserDTO(res.User),
+		FromTeam:      res.FromTeam,
+		ReassignedPrs: toArrDeliveryPullRequestDTO(res.ReassignedPrs),
+		AuthoredPrs:   toArrDeliveryPullRequestDTO(res.AuthoredPrs),
+	}
+}
+
+func toUseCaseAnonymizeUserReq(req AnonymizeUserReq) usecase.AnonymizeUserReq {
+	return usecase.AnonymizeUserReq{
+		UserId: req.UserId,
+	}
+}
+
+func toDeliveryAnonymizeUserRes(res usecase.AnonymizeUserRes) AnonymizeUserRes {
+	return AnonymizeUserRes{
+		User:          toDeliveryUserDTO(res.User),
+		ReassignedPrs: toArrDeliveryPullRequestDTO(res.ReassignedPrs),
+	}
+}
+
+func toDeliveryGetUserRes(res usecase.GetUserRes) GetUserRes {
+	return GetUserRes{
+		User:        toDeliveryUserDTO(res.User),
+		OpenReviews: res.OpenReviews,
+		AuthoredPrs: res.AuthoredPrs,
+	}
+}
+
+func toUseCaseListUsersReq(req ListUsersQueryReq) usecase.ListUsersReq {
+	return usecase.ListUsersReq{
+		Query:    req.Query,
+		TeamName: req.TeamName,
+		IsActive: req.IsActive,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+}
+
+func toDeliveryListUsersRes(res usecase.ListUsersRes) ListUsersRes {
+	users := make([]UserListItemDTO, 0, len(res.Users))
+	for _, user := range res.Users {
+		users = append(users, UserListItemDTO{
+			Id:       user.Id,
+			Username: user.Username,
+			TeamName: user.TeamName,
+			IsActive: user.IsActive,
+		})
+	}
+
+	return ListUsersRes{
+		Users:  users,
+		Total:  res.Total,
+		Limit:  res.Limit,
+		Offset: res.Offset,
+	}
+}
+
+func toUseCaseJoinTeamReq(req JoinTeamReq) usecase.JoinTeamReq {
+	return usecase.JoinTeamReq{
+		UserId:   req.UserId,
+		TeamName: req.TeamName,
+	}
+}
+
+func toDeliveryJoinTeamRes(res usecase.JoinTeamRes) JoinTeamRes {
+	return JoinTeamRes{
+		User: toDeliveryUserDTO(res.User),
+	}
+}
+
 func toDeliveryGetReviewRes(req usecase.GetReviewRes) GetReviewRes {
 	return GetReviewRes{
 		UserId:       req.UserId,
@@ -114,12 +198,28 @@ func toUseCaseCreatePullRequestReq(req CreatePullRequestReq) usecase.CreatePullR
 		Id:       req.Id,
 		Name:     req.Name,
 		AuthorId: req.AuthorId,
+		TeamName: req.TeamName,
 	}
 }
 
 func toDeliveryCreatePullRequestRes(res usecase.CreatePullRequestRes) CreatePullRequestRes {
+	sources := make([]ReviewerSourceDTO, 0, len(res.ReviewerSources))
+	for _, source := range res.ReviewerSources {
+		sources = append(sources, toDeliveryReviewerSourceDTO(source))
+	}
+
 	return CreatePullRequestRes{
-		PullRequest: toCreatePullRequestDTO(res.PullRequest),
+		PullRequest:     toCreatePullRequestDTO(res.PullRequest),
+		ReviewerSources: sources,
+	}
+}
+
+func toDeliveryReviewerSourceDTO(source usecase.ReviewerSourceDTO) ReviewerSourceDTO {
+	return ReviewerSourceDTO{
+		UserId:   source.UserId,
+		TeamName: source.TeamName,
+		Source:   string(source.Source),
+		Level:    source.Level,
 	}
 }
 
@@ -177,8 +277,9 @@ func toUseCasePullRequestReassignReq(req PullRequestReassignReq) usecase.PullReq
 
 func toDeliveryPullRequestReassignRes(res usecase.PullRequestReassignRes) PullRequestReassignRes {
 	return PullRequestReassignRes{
-		Pr:         toDeliveryPullRequestDTO(res.Pr),
-		ReplacedBy: res.ReplacedBy,
+		Pr:               toDeliveryPullRequestDTO(res.Pr),
+		ReplacedBy:       res.ReplacedBy,
+		ReplacedBySource: toDeliveryReviewerSourceDTO(res.ReplacedBySource),
 	}
 }
 
@@ -197,6 +298,110 @@ func toDeliveryDeactivateMembers(res usecase.DeactivateMembersRes) DeactivateMem
 	}
 }
 
+func toUseCaseAddMembersReq(req AddMembersReq) usecase.AddMembersReq {
+	return usecase.AddMembersReq{
+		TeamName: req.TeamName,
+		Members:  toArrUseCaseTeamMemberDTO(req.Members),
+	}
+}
+
+func toDeliveryAddMembersRes(res usecase.AddMembersRes) AddMembersRes {
+	return AddMembersRes{
+		Team: toDeliveryTeamDTO(res.Team),
+	}
+}
+
+func toUseCaseSyncTeamReq(req SyncTeamReq, query SyncTeamQueryReq) usecase.SyncTeamReq {
+	return usecase.SyncTeamReq{
+		TeamName: req.TeamName,
+		Members:  toArrUseCaseTeamMemberDTO(req.Members),
+		DryRun:   query.DryRun,
+	}
+}
+
+func toDeliverySyncTeamRes(res usecase.SyncTeamRes) SyncTeamRes {
+	renamed := make([]RenamedMemberDTO, 0, len(res.Renamed))
+	for _, m := range res.Renamed {
+		renamed = append(renamed, RenamedMemberDTO{
+			Id:          m.Id,
+			OldUsername: m.OldUsername,
+			NewUsername: m.NewUsername,
+		})
+	}
+
+	return SyncTeamRes{
+		TeamName:    res.TeamName,
+		DryRun:      res.DryRun,
+		Added:       toArrTeamMemberDTO(res.Added),
+		Reactivated: toArrTeamMemberDTO(res.Reactivated),
+		Renamed:     renamed,
+		Deactivated: toArrTeamMemberDTO(res.Deactivated),
+		Removed:     toArrTeamMemberDTO(res.Removed),
+		UpdPrs:      toArrDeliveryPullRequestDTO(res.UpdPrs),
+	}
+}
+
+func toUseCaseRenameTeamReq(req RenameTeamReq) usecase.RenameTeamReq {
+	return usecase.RenameTeamReq{
+		TeamName:    req.TeamName,
+		NewTeamName: req.NewTeamName,
+	}
+}
+
+func toDeliveryRenameTeamRes(res usecase.RenameTeamRes) RenameTeamRes {
+	return RenameTeamRes{
+		Team: toDeliveryTeamDTO(res.Team),
+	}
+}
+
+func toUseCaseSetTeamParentReq(req SetTeamParentReq) usecase.SetTeamParentReq {
+	return usecase.SetTeamParentReq{
+		TeamName:       req.TeamName,
+		ParentTeamName: req.ParentTeamName,
+		MaxDepth:       *req.MaxDepth,
+	}
+}
+
+func toDeliverySetTeamParentRes(res usecase.SetTeamParentRes) SetTeamParentRes {
+	return SetTeamParentRes{
+		TeamName:       res.TeamName,
+		ParentTeamName: res.ParentTeamName,
+		MaxDepth:       res.MaxDepth,
+	}
+}
+
+func toUseCaseDeleteTeamReq(req DeleteTeamReq) usecase.DeleteTeamReq {
+	return usecase.DeleteTeamReq{
+		TeamName:        req.TeamName,
+		TargetTeamName:  req.TargetTeamName,
+		ReassignReviews: req.ReassignReviews,
+	}
+}
+
+func toDeliveryDeleteTeamRes(res usecase.DeleteTeamRes) DeleteTeamRes {
+	return DeleteTeamRes{
+		TeamName:       res.TeamName,
+		TargetTeamName: res.TargetTeamName,
+		MovedMembers:   toArrTeamMemberDTO(res.MovedMembers),
+		UpdPrs:         toArrDeliveryPullRequestDTO(res.UpdPrs),
+	}
+}
+
+func toUseCaseRemoveMembersReq(req RemoveMembersReq) usecase.RemoveMembersReq {
+	return usecase.RemoveMembersReq{
+		TeamName: req.TeamName,
+		Members:  req.Members,
+	}
+}
+
+func toDeliveryRemoveMembersRes(res usecase.RemoveMembersRes) RemoveMembersRes {
+	return RemoveMembersRes{
+		TeamName:       res.TeamName,
+		RemovedMembers: toArrTeamMemberDTO(res.RemovedMembers),
+		UpdPrs:         toArrDeliveryPullRequestDTO(res.UpdPrs),
+	}
+}
+
 func toArrDeliveryPullRequestDTO(pr []usecase.PullRequestDTO) []PullRequestDTO {
 	result := make([]PullRequestDTO, 0, len(pr))
 	for _, pr := range pr {
@@ -204,3 +409,94 @@ func toArrDeliveryPullRequestDTO(pr []usecase.PullRequestDTO) []PullRequestDTO {
 	}
 	return result
 }
+
+func toUseCaseCreateWebhookReq(req CreateWebhookReq) usecase.CreateWebhookReq {
+	return usecase.CreateWebhookReq{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+	}
+}
+
+func toDeliveryCreateWebhookRes(res usecase.CreateWebhookRes) CreateWebhookRes {
+	return CreateWebhookRes{
+		Subscription: toDeliveryWebhookSubscriptionDTO(res.Subscription),
+		Secret:       res.Secret,
+	}
+}
+
+func toDeliveryListWebhooksRes(res usecase.ListWebhooksRes) ListWebhooksRes {
+	subs := make([]WebhookSubscriptionDTO, 0, len(res.Subscriptions))
+	for _, sub := range res.Subscriptions {
+		subs = append(subs, toDeliveryWebhookSubscriptionDTO(sub))
+	}
+
+	return ListWebhooksRes{Subscriptions: subs}
+}
+
+func toDeliveryEnableWebhookRes(res usecase.EnableWebhookRes) EnableWebhookRes {
+	return EnableWebhookRes{
+		Subscription: toDeliveryWebhookSubscriptionDTO(res.Subscription),
+	}
+}
+
+func toUseCaseGetWebhookDeliveriesReq(req GetWebhookDeliveriesQueryReq) usecase.GetWebhookDeliveriesReq {
+	return usecase.GetWebhookDeliveriesReq{
+		SubscriptionId: req.SubscriptionId,
+		Limit:          req.Limit,
+	}
+}
+
+func toDeliveryGetWebhookDeliveriesRes(res usecase.GetWebhookDeliveriesRes) GetWebhookDeliveriesRes {
+	deliveries := make([]WebhookDeliveryDTO, 0, len(res.Deliveries))
+	for _, d := range res.Deliveries {
+		deliveries = append(deliveries, WebhookDeliveryDTO{
+			Id:            d.Id,
+			EventId:       d.EventId,
+			EventType:     d.EventType,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			ResponseCode:  d.ResponseCode,
+			LastError:     d.LastError,
+			CreatedAt:     d.CreatedAt,
+			NextAttemptAt: d.NextAttemptAt,
+			DeliveredAt:   d.DeliveredAt,
+		})
+	}
+
+	return GetWebhookDeliveriesRes{
+		SubscriptionId: res.SubscriptionId,
+		Deliveries:     deliveries,
+	}
+}
+
+func toDeliveryWebhookSubscriptionDTO(sub usecase.WebhookSubscriptionDTO) WebhookSubscriptionDTO {
+	return WebhookSubscriptionDTO{
+		Id:                  sub.Id,
+		URL:                 sub.URL,
+		Events:              sub.Events,
+		IsActive:            sub.IsActive,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		CreatedAt:           sub.CreatedAt,
+		DisabledAt:          sub.DisabledAt,
+	}
+}
+
+func toDeliveryGitHubReconcileRes(report github.Report) GitHubReconcileRes {
+	drift := make([]DriftDTO, 0, len(report.Drift))
+	for _, d := range report.Drift {
+		drift = append(drift, DriftDTO{
+			PullRequestId: d.PullRequestId,
+			Number:        d.Number,
+			Kind:          string(d.Kind),
+			Fixed:         d.Fixed,
+			Error:         d.Error,
+		})
+	}
+
+	return GitHubReconcileRes{
+		StartedAt: report.StartedAt.Format(time.RFC3339),
+		Checked:   report.Checked,
+		Drift:     drift,
+	}
+}
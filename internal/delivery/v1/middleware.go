@@ -1,38 +1,103 @@
 package v1
 
 import (
+	"avito-internship/internal/idempotency"
+	"avito-internship/pkg/e"
 	"avito-internship/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader          = "X-Request-ID"
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxRequestIDLength      = 128
+	maxIdempotencyKeyLength = 255
+	errorCodeKey            = "error_code"
 )
 
 type Middleware struct {
-	logger     logger.Logger
-	adminToken string
+	logger      logger.Logger
+	adminToken  string
+	idempotency *idempotency.Service
 }
 
-func NewMiddleware(logger logger.Logger, adminToken string) *Middleware {
+func NewMiddleware(logger logger.Logger, adminToken string, idempotency *idempotency.Service) *Middleware {
 	return &Middleware{
-		logger:     logger,
-		adminToken: adminToken,
-	}
-}
-
-//func (m *Middleware) AdminMiddleware() gin.HandlerFunc {
-//	return func(c *gin.Context) {
-//		const prefix = "Bearer "
-//		authHeader := c.GetHeader("Authorization")
-//		token := strings.TrimPrefix(authHeader, prefix)
-//
-//		if !strings.HasPrefix(authHeader, prefix) || token != m.adminToken {
-//			m.logger.Errorf(e.ErrUnauthorized, "method=%s path=%s", c.Request.Method, c.Request.URL.Path)
-//			abortUnauthorized(c)
-//			return
-//		}
-//
-//		c.Next()
-//	}
-//}
+		logger:      logger,
+		adminToken:  adminToken,
+		idempotency: idempotency,
+	}
+}
+
+// AdminMiddleware requires "Authorization: Bearer <ADMIN_TOKEN>". Admin endpoints
+// are closed when no token is configured.
+func (m *Middleware) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		const prefix = "Bearer "
+		authHeader := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(authHeader, prefix)
+
+		if m.adminToken == "" || !strings.HasPrefix(authHeader, prefix) ||
+			subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+			m.logger.ErrorfContext(c.Request.Context(), e.ErrUnauthorized, "method=%s path=%s", c.Request.Method, c.Request.URL.Path)
+			abortUnauthorized(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequestID propagates a valid incoming X-Request-ID or assigns a new one, echoes it
+// in the response and stores it in the request context for the layers below.
+func (m *Middleware) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// AccessLog writes one line per request once the response is ready.
+func (m *Middleware) AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.logger.With(
+			"method", c.Request.Method,
+			"route", route,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"client", c.ClientIP(),
+			"error_code", c.GetString(errorCodeKey),
+		).InfofContext(c.Request.Context(), "access")
+	}
+}
 
 func (m *Middleware) ErrorMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -41,9 +106,10 @@ func (m *Middleware) ErrorMiddleware() gin.HandlerFunc {
 		for _, ginErr := range c.Errors {
 			err := ginErr.Err
 
-			m.logger.Errorf(err, "method=%s path=%s", c.Request.Method, c.Request.URL.Path)
+			m.logger.ErrorfContext(c.Request.Context(), err, "method=%s path=%s", c.Request.Method, c.Request.URL.Path)
 
 			codeInt, codeString, msg := ToHTTPResponse(err)
+			c.Set(errorCodeKey, codeString)
 			response := NewErrorResponse(codeString, msg)
 			c.JSON(codeInt, response)
 			return
@@ -51,8 +117,120 @@ func (m *Middleware) ErrorMiddleware() gin.HandlerFunc {
 	}
 }
 
-//func abortUnauthorized(c *gin.Context) {
-//	response := NewErrorResponse(e.NOT_FOUND, e.ErrResourceNotFound.Error())
-//	c.JSON(http.StatusUnauthorized, response)
-//	c.Abort()
-//}
+// Idempotency replays the stored response to a POST request repeated with the
+// same Idempotency-Key. Server errors are not stored, so their retries run
+// again. It goes after authentication, so replays are not served to anyone else.
+func (m *Middleware) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if m.idempotency == nil || c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if !validIdempotencyKey(key) {
+			c.Error(e.ErrInvalidIdempotencyKey)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := idempotency.RequestHash(c.Request.Method, c.Request.URL.RequestURI(), body)
+		stored, err := m.idempotency.Begin(c.Request.Context(), key, hash)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// The client may be gone by now, but its retry still needs the outcome.
+		ctx := context.WithoutCancel(c.Request.Context())
+		res := idempotency.Response{StatusCode: c.Writer.Status(), Body: recorder.body.Bytes()}
+		if len(c.Errors) > 0 {
+			// ErrorMiddleware writes the error response only after this returns.
+			code, codeString, msg := ToHTTPResponse(c.Errors[0].Err)
+			res.StatusCode = code
+			res.Body, _ = json.Marshal(NewErrorResponse(codeString, msg))
+		}
+
+		if res.StatusCode >= http.StatusInternalServerError {
+			err = m.idempotency.Release(ctx, key)
+		} else {
+			err = m.idempotency.Complete(ctx, key, res)
+		}
+		if err != nil {
+			m.logger.ErrorfContext(ctx, err, "idempotency key=%q", key)
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+
+	for _, r := range key {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func abortUnauthorized(c *gin.Context) {
+	response := NewErrorResponse(e.NOT_FOUND, e.ErrResourceNotFound.Error())
+	c.JSON(http.StatusUnauthorized, response)
+	c.Abort()
+}
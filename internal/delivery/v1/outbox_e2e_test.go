@@ -0,0 +1,106 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/domain"
+	"avito-internship/internal/outbox"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	err    error
+	events []domain.Event
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Deliver(_ context.Context, event domain.Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *recordingSink) types() []domain.EventType {
+	types := make([]domain.EventType, 0, len(s.events))
+	for _, event := range s.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestE2E_Outbox(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	oldReviewer := pr.PullRequest.AssignedReviewers[0]
+
+	var reassigned v1.PullRequestReassignRes
+	reassign := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": oldReviewer}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/reassign", reassign, &reassigned))
+
+	// Repeated calls that change nothing emit nothing.
+	merge := map[string]any{"pull_request_id": "pr-1001"}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", merge, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", merge, nil))
+
+	deactivate := map[string]any{"user_id": "u4", "is_active": false}
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", deactivate, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", deactivate, nil))
+
+	// A failed request rolls its events back together with the change.
+	duplicate := map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Again", "author_id": "u1"}
+	s.expectError(http.MethodPost, "/pullRequest/create", duplicate, http.StatusBadRequest, e.PR_EXISTS)
+
+	sink := &recordingSink{}
+	dispatcher := outbox.NewDispatcher(s.outbox, outbox.DefaultConfig(), logger.NewSlogLogger(), sink)
+
+	n, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	require.Equal(t, []domain.EventType{
+		domain.EventPRCreated,
+		domain.EventReviewerAssigned,
+		domain.EventReviewerAssigned,
+		domain.EventReviewerReassigned,
+		domain.EventPRMerged,
+		domain.EventUserDeactivated,
+	}, sink.types())
+
+	var payload domain.ReviewerReassignedPayload
+	require.NoError(t, json.Unmarshal(sink.events[3].Payload, &payload))
+	require.Equal(t, domain.ReviewerReassignedPayload{
+		PullRequestId: "pr-1001",
+		OldReviewerId: oldReviewer,
+		NewReviewerId: reassigned.ReplacedBy,
+	}, payload)
+
+	n, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n, "delivered events are not dispatched again")
+
+	s.createPR("pr-1002", "Fix bug", "u2")
+
+	cfg := outbox.DefaultConfig()
+	cfg.RetryBackoff = time.Hour
+	failing := &recordingSink{err: errors.New("sink is down")}
+	dispatcher = outbox.NewDispatcher(s.outbox, cfg, logger.NewSlogLogger(), failing)
+
+	n, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n, "failed events wait for their backoff")
+}
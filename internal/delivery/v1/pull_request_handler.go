@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"avito-internship/pkg/e"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +9,7 @@ import (
 func (h *Handler) pullRequestCreate(c *gin.Context) {
 	var req CreatePullRequestReq
 	if err := c.ShouldBind(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -26,7 +25,7 @@ func (h *Handler) pullRequestCreate(c *gin.Context) {
 func (h *Handler) pullRequestMerge(c *gin.Context) {
 	var req PullRequestMergeReq
 	if err := c.ShouldBind(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -42,7 +41,7 @@ func (h *Handler) pullRequestMerge(c *gin.Context) {
 func (h *Handler) reviewerReassign(c *gin.Context) {
 	var req PullRequestReassignReq
 	if err := c.ShouldBind(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
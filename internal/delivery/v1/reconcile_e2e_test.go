@@ -0,0 +1,163 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/domain"
+	"avito-internship/internal/github"
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testForgeToken = "forge-token"
+
+// fakeForge serves the pull request list of the GitHub REST API.
+type fakeForge struct {
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	prs   []github.PullRequest
+	auths []string
+}
+
+func newFakeForge(t *testing.T) *fakeForge {
+	t.Helper()
+
+	f := &fakeForge{}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.list))
+	t.Cleanup(f.srv.Close)
+
+	return f
+}
+
+func (f *fakeForge) set(prs ...github.PullRequest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prs = prs
+}
+
+func (f *fakeForge) list(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.URL.Path != "/repos/avito-tech/reviewer/pulls" {
+		http.NotFound(w, req)
+		return
+	}
+	f.auths = append(f.auths, req.Header.Get("Authorization"))
+
+	query := req.URL.Query()
+	var prs []github.PullRequest
+	for _, pr := range f.prs {
+		if pr.State == query.Get("state") {
+			// The list endpoint does not report merged.
+			pr.Merged = false
+			prs = append(prs, pr)
+		}
+	}
+	slices.SortFunc(prs, func(a, b github.PullRequest) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	start := min((page-1)*perPage, len(prs))
+	end := min(start+perPage, len(prs))
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(prs[start:end])
+}
+
+func TestE2E_GitHubReconcile(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	now := time.Now().UTC()
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	mergedAt := ago(time.Hour)
+	longAgo := ago(10 * 24 * time.Hour)
+
+	reconcile := func() v1.GitHubReconcileRes {
+		var res v1.GitHubReconcileRes
+		require.Equal(t, http.StatusOK, s.doAdmin(http.MethodPost, "/admin/github/reconcile", nil, &res))
+		slices.SortFunc(res.Drift, func(a, b v1.DriftDTO) int { return cmp.Compare(a.Number, b.Number) })
+		return res
+	}
+
+	// The first run ingests the PRs that later drift. A PR created by hand
+	// with the same number keeps its own id.
+	s.createPR("pr-1001", "Manual", "u2")
+	s.forge.set(
+		github.PullRequest{Number: 1001, Title: "Merged upstream", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "alice-dev"}},
+		github.PullRequest{Number: 1002, Title: "Reopened upstream", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "bob"}},
+		github.PullRequest{Number: 1003, Title: "Closed upstream", State: "open", UpdatedAt: ago(2 * time.Hour), User: github.Account{Login: "alice-dev"}},
+		github.PullRequest{Number: 1009, Title: "In sync", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "alice-dev"}},
+	)
+	require.Len(t, reconcile().Drift, 4)
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "gh-1002"}, nil))
+
+	s.forge.set(
+		github.PullRequest{Number: 1001, Title: "Merged upstream", State: "closed", MergedAt: &mergedAt, UpdatedAt: ago(time.Hour), User: github.Account{Login: "alice-dev"}},
+		github.PullRequest{Number: 1002, Title: "Reopened upstream", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "bob"}},
+		github.PullRequest{Number: 1003, Title: "Closed upstream", State: "closed", UpdatedAt: ago(2 * time.Hour), User: github.Account{Login: "alice-dev"}},
+		github.PullRequest{Number: 1004, Title: "Missed webhook", State: "open", UpdatedAt: ago(3 * time.Hour), User: github.Account{Login: "Alice-Dev"}},
+		github.PullRequest{Number: 1005, Title: "Missed merge", State: "closed", MergedAt: &mergedAt, UpdatedAt: ago(time.Hour), User: github.Account{Login: "bob"}},
+		github.PullRequest{Number: 1006, Title: "Stranger", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "stranger"}},
+		github.PullRequest{Number: 1007, Title: "Draft", State: "open", Draft: true, UpdatedAt: ago(time.Hour), User: github.Account{Login: "bob"}},
+		github.PullRequest{Number: 1008, Title: "Beyond lookback", State: "closed", MergedAt: &longAgo, UpdatedAt: longAgo, User: github.Account{Login: "bob"}},
+		github.PullRequest{Number: 1009, Title: "In sync", State: "open", UpdatedAt: ago(time.Hour), User: github.Account{Login: "alice-dev"}},
+	)
+
+	require.Equal(t, http.StatusUnauthorized, s.do(http.MethodPost, "/admin/github/reconcile", nil, nil))
+
+	res := reconcile()
+	require.Equal(t, 8, res.Checked)
+	require.Len(t, res.Drift, 6)
+
+	unknown := res.Drift[5]
+	require.Contains(t, unknown.Error, "stranger")
+	unknown.Error = ""
+
+	require.Equal(t, []v1.DriftDTO{
+		{PullRequestId: "gh-1001", Number: 1001, Kind: "not_merged", Fixed: true},
+		{PullRequestId: "gh-1002", Number: 1002, Kind: "reopened_upstream"},
+		{PullRequestId: "gh-1003", Number: 1003, Kind: "closed_upstream"},
+		{PullRequestId: "gh-1004", Number: 1004, Kind: "missing", Fixed: true},
+		{PullRequestId: "gh-1005", Number: 1005, Kind: "missing_merged", Fixed: true},
+		{PullRequestId: "gh-1006", Number: 1006, Kind: "missing"},
+	}, append(res.Drift[:5], unknown))
+
+	for _, auth := range s.forge.auths {
+		require.Equal(t, "Bearer "+testForgeToken, auth)
+	}
+
+	// Fixed drift does not come back.
+	res = reconcile()
+	kinds := make([]string, 0, len(res.Drift))
+	for _, d := range res.Drift {
+		kinds = append(kinds, d.Kind)
+	}
+	require.Equal(t, []string{"reopened_upstream", "closed_upstream", "missing"}, kinds)
+
+	// Ids of ingested PRs cannot be taken by hand.
+	s.expectValidationError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "gh-1004", "pull_request_name": "Again", "author_id": "u1"},
+		`pull_request_id: rule "newprid" failed`)
+
+	var merged v1.PullRequestMergeRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "gh-1005"}, &merged))
+	require.Equal(t, domain.MERGED, merged.PullRequest.Status)
+	require.Equal(t, "u2", merged.PullRequest.AuthorId)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1001"}, &merged))
+	require.Equal(t, "Manual", merged.PullRequest.Name)
+}
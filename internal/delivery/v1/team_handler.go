@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"avito-internship/pkg/e"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +9,7 @@ import (
 func (h *Handler) addTeam(c *gin.Context) {
 	var team TeamAddReq
 	if err := c.ShouldBindJSON(&team); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -26,7 +25,7 @@ func (h *Handler) addTeam(c *gin.Context) {
 func (h *Handler) getTeam(c *gin.Context) {
 	var req GetTeamQueryReq
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -42,7 +41,7 @@ func (h *Handler) getTeam(c *gin.Context) {
 func (h *Handler) deactivateMembers(c *gin.Context) {
 	var req DeactivateMembersReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -54,3 +53,105 @@ func (h *Handler) deactivateMembers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, toDeliveryDeactivateMembers(res))
 }
+
+func (h *Handler) addMembers(c *gin.Context) {
+	var req AddMembersReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.AddMembers(c.Request.Context(), toUseCaseAddMembersReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryAddMembersRes(res))
+}
+
+func (h *Handler) removeMembers(c *gin.Context) {
+	var req RemoveMembersReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.RemoveMembers(c.Request.Context(), toUseCaseRemoveMembersReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryRemoveMembersRes(res))
+}
+
+func (h *Handler) syncTeam(c *gin.Context) {
+	var query SyncTeamQueryReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	var req SyncTeamReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.SyncTeam(c.Request.Context(), toUseCaseSyncTeamReq(req, query))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliverySyncTeamRes(res))
+}
+
+func (h *Handler) renameTeam(c *gin.Context) {
+	var req RenameTeamReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.RenameTeam(c.Request.Context(), toUseCaseRenameTeamReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryRenameTeamRes(res))
+}
+
+func (h *Handler) setTeamParent(c *gin.Context) {
+	var req SetTeamParentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.SetParent(c.Request.Context(), toUseCaseSetTeamParentReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliverySetTeamParentRes(res))
+}
+
+func (h *Handler) deleteTeam(c *gin.Context) {
+	var req DeleteTeamReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.teamUC.DeleteTeam(c.Request.Context(), toUseCaseDeleteTeamReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryDeleteTeamRes(res))
+}
@@ -0,0 +1,65 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2E_TeamHierarchy(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("mobile", member{UserId: "u1", Username: "Alice", IsActive: true}, member{UserId: "u2", Username: "Bob", IsActive: true})
+	s.addTeam("android", member{UserId: "u3", Username: "Carol", IsActive: true})
+	s.addTeam("ios", member{UserId: "u4", Username: "Dave", IsActive: true})
+
+	var parent v1.SetTeamParentRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/setParent",
+		map[string]any{"team_name": "android", "parent_team_name": "mobile", "max_depth": 1}, &parent))
+	require.Equal(t, v1.SetTeamParentRes{TeamName: "android", ParentTeamName: "mobile", MaxDepth: 1}, parent)
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/setParent",
+		map[string]any{"team_name": "ios", "parent_team_name": "mobile", "max_depth": 0}, nil))
+
+	s.expectError(http.MethodPost, "/team/setParent", map[string]any{"team_name": "mobile", "parent_team_name": "android", "max_depth": 1},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/setParent", map[string]any{"team_name": "mobile", "parent_team_name": "mobile", "max_depth": 1},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/setParent", map[string]any{"team_name": "android", "parent_team_name": "unknown", "max_depth": 1},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/team/setParent", map[string]any{"team_name": "android", "parent_team_name": "mobile"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/setParent", map[string]any{"team_name": "android", "max_depth": -1},
+		http.StatusBadRequest, e.BAD_REQUEST)
+
+	// android has nobody but the author, so reviewers come from ios and then mobile.
+	var created v1.CreatePullRequestRes
+	require.Equal(t, http.StatusCreated, s.do(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Camera", "author_id": "u3"}, &created))
+	require.Len(t, created.PullRequest.AssignedReviewers, 2)
+	require.Len(t, created.ReviewerSources, 2)
+	require.Equal(t, v1.ReviewerSourceDTO{UserId: "u4", TeamName: "ios", Source: "sibling", Level: 1}, created.ReviewerSources[0])
+	require.Equal(t, "mobile", created.ReviewerSources[1].TeamName)
+	require.Equal(t, "parent", created.ReviewerSources[1].Source)
+	fromParent := created.ReviewerSources[1].UserId
+
+	// ios does not widen its search.
+	require.Equal(t, http.StatusCreated, s.do(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1002", "pull_request_name": "Widgets", "author_id": "u4"}, &created))
+	require.Empty(t, created.PullRequest.AssignedReviewers)
+	require.Empty(t, created.ReviewerSources)
+
+	var reassigned v1.PullRequestReassignRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/reassign",
+		map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": "u4"}, &reassigned))
+	require.NotEqual(t, fromParent, reassigned.ReplacedBy)
+	require.Equal(t, v1.ReviewerSourceDTO{UserId: reassigned.ReplacedBy, TeamName: "mobile", Source: "parent", Level: 1}, reassigned.ReplacedBySource)
+
+	// A top-level team has nowhere to widen to.
+	var detached v1.SetTeamParentRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/setParent", map[string]any{"team_name": "android", "max_depth": 1}, &detached))
+	require.Empty(t, detached.ParentTeamName)
+	s.expectError(http.MethodPost, "/pullRequest/reassign", map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": fromParent},
+		http.StatusConflict, e.NO_CANDIDATE)
+}
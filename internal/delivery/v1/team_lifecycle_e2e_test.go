@@ -0,0 +1,89 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2E_TeamRename(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("frontend", member{UserId: "u5", Username: "Eve", IsActive: true})
+
+	var renamed v1.RenameTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/rename", map[string]any{"team_name": "backend", "new_team_name": "platform"}, &renamed))
+	require.Equal(t, "platform", renamed.Team.TeamName)
+	require.Len(t, renamed.Team.Members, 4)
+
+	s.expectError(http.MethodGet, "/team/get?team_name=backend", nil, http.StatusNotFound, e.NOT_FOUND)
+
+	var user v1.SetIsActiveRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": "u1", "is_active": true}, &user))
+	require.Equal(t, "platform", user.User.TeamName)
+
+	s.expectError(http.MethodPost, "/team/rename", map[string]any{"team_name": "platform", "new_team_name": "frontend"},
+		http.StatusBadRequest, e.TEAM_EXISTS)
+	s.expectError(http.MethodPost, "/team/rename", map[string]any{"team_name": "backend", "new_team_name": "core"},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/team/rename", map[string]any{"team_name": "platform"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+}
+
+func TestE2E_TeamDelete(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("frontend", member{UserId: "u5", Username: "Eve", IsActive: true}, member{UserId: "u6", Username: "Frank", IsActive: true})
+
+	// An emptied team is deleted right away.
+	s.addTeam("mobile", member{UserId: "u7", Username: "Grace", IsActive: true})
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/remove", map[string]any{"team_name": "mobile", "members": []string{"u7"}}, nil))
+
+	var deleted v1.DeleteTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/delete", map[string]any{"team_name": "mobile"}, &deleted))
+	require.Empty(t, deleted.MovedMembers)
+	s.expectError(http.MethodGet, "/team/get?team_name=mobile", nil, http.StatusNotFound, e.NOT_FOUND)
+
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "backend"}, http.StatusConflict, e.TEAM_NOT_EMPTY)
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "backend", "target_team_name": "backend"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "backend", "target_team_name": "unknown"},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "unknown"}, http.StatusNotFound, e.NOT_FOUND)
+
+	// u8 keeps reviewing pr-1001 after its author moves to a team with nobody to take over.
+	s.addTeam("ops", member{UserId: "u8", Username: "Heidi", IsActive: true}, member{UserId: "u9", Username: "Ivan", IsActive: true})
+	s.addTeam("solo", member{UserId: "u10", Username: "Judy", IsActive: false})
+	s.createPR("pr-1001", "Deploy", "u9")
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/transfer", map[string]any{"user_id": "u9", "team_name": "solo"}, nil))
+
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "ops", "target_team_name": "backend"},
+		http.StatusConflict, e.OPEN_REVIEWS)
+	s.expectError(http.MethodPost, "/team/delete", map[string]any{"team_name": "ops", "target_team_name": "backend", "reassign_reviews": true},
+		http.StatusConflict, e.NO_CANDIDATE)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": "u10", "is_active": true}, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/delete",
+		map[string]any{"team_name": "ops", "target_team_name": "backend", "reassign_reviews": true}, &deleted))
+	require.Equal(t, "backend", deleted.TargetTeamName)
+	require.Len(t, deleted.MovedMembers, 1)
+	require.Len(t, deleted.UpdPrs, 1)
+	require.Equal(t, []string{"u10"}, deleted.UpdPrs[0].AssignedReviewers)
+
+	// Members keep reviewing their teammates' PRs in the target team.
+	pr := s.createPR("pr-1002", "Add login", "u1")
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/delete", map[string]any{"team_name": "backend", "target_team_name": "frontend"}, &deleted))
+	require.Len(t, deleted.MovedMembers, 5)
+	require.Empty(t, deleted.UpdPrs)
+
+	var review v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+pr.PullRequest.AssignedReviewers[0], nil, &review))
+	require.Len(t, review.PullRequests, 1)
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=frontend", nil, &team))
+	require.Len(t, team.Members, 7)
+}
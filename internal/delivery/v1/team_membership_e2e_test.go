@@ -0,0 +1,67 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2E_TeamMemberships(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("platform", member{UserId: "u5", Username: "Eve", IsActive: true}, member{UserId: "u6", Username: "Frank", IsActive: true})
+
+	var joined v1.JoinTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/joinTeam", map[string]any{"user_id": "u1", "team_name": "platform"}, &joined))
+	require.Equal(t, "backend", joined.User.TeamName)
+	require.Equal(t, []string{"backend", "platform"}, joined.User.Teams)
+
+	s.expectError(http.MethodPost, "/users/joinTeam", map[string]any{"user_id": "u99", "team_name": "platform"}, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPost, "/users/joinTeam", map[string]any{"user_id": "u1", "team_name": "unknown"}, http.StatusNotFound, e.NOT_FOUND)
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=platform", nil, &team))
+	require.Len(t, team.Members, 3)
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.Len(t, team.Members, 4)
+
+	// Reviewers come from the PR team, or from the author's primary team.
+	var owned v1.CreatePullRequestRes
+	require.Equal(t, http.StatusCreated, s.do(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1001", "pull_request_name": "Runners", "author_id": "u1", "team_name": "platform"}, &owned))
+	require.ElementsMatch(t, []string{"u5", "u6"}, owned.PullRequest.AssignedReviewers)
+
+	pr := s.createPR("pr-1002", "Add login", "u1")
+	require.Subset(t, []string{"u2", "u3", "u4"}, pr.PullRequest.AssignedReviewers)
+
+	s.expectError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1003", "pull_request_name": "Foreign", "author_id": "u2", "team_name": "platform"},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPost, "/pullRequest/create",
+		map[string]any{"pull_request_id": "pr-1003", "pull_request_name": "Foreign", "author_id": "u2", "team_name": "unknown"},
+		http.StatusNotFound, e.NOT_FOUND)
+
+	reassign := map[string]any{"pull_request_id": "pr-1001", "old_reviewer_id": "u5"}
+	s.expectError(http.MethodPost, "/pullRequest/reassign", reassign, http.StatusConflict, e.NO_CANDIDATE)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "platform", "members": []member{{UserId: "u7", Username: "Grace", IsActive: true}}}, nil))
+	var reassigned v1.PullRequestReassignRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/reassign", reassign, &reassigned))
+	require.Equal(t, "u7", reassigned.ReplacedBy)
+
+	// Leaving the primary team falls back to the remaining membership.
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/remove", map[string]any{"team_name": "backend", "members": []string{"u1"}}, nil))
+
+	var user v1.SetIsActiveRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/setIsActive", map[string]any{"user_id": "u1", "is_active": true}, &user))
+	require.Equal(t, "platform", user.User.TeamName)
+	require.Equal(t, []string{"platform"}, user.User.Teams)
+
+	s.expectError(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "backend", "members": []member{{UserId: "u1", Username: "Alice", IsActive: true}}},
+		http.StatusConflict, e.USER_IN_TEAM)
+}
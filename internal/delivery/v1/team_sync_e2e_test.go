@@ -0,0 +1,109 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2E_TeamSync(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	removed, deactivated := pr.PullRequest.AssignedReviewers[0], pr.PullRequest.AssignedReviewers[1]
+	kept := ""
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if !slices.Contains(pr.PullRequest.AssignedReviewers, id) {
+			kept = id
+		}
+	}
+
+	names := map[string]string{"u2": "Bob", "u3": "Carol", "u4": "Dave"}
+	roster := []member{
+		{UserId: "u1", Username: "Alicia", IsActive: true},
+		{UserId: deactivated, Username: names[deactivated], IsActive: false},
+		{UserId: kept, Username: names[kept], IsActive: true},
+		{UserId: "u5", Username: "Eve", IsActive: true},
+	}
+	sync := map[string]any{"team_name": "backend", "members": roster}
+
+	check := func(res v1.SyncTeamRes, updPrs int) {
+		t.Helper()
+
+		require.Equal(t, "backend", res.TeamName)
+		require.Len(t, res.Added, 1)
+		require.Equal(t, "u5", res.Added[0].Id)
+		require.Equal(t, []v1.RenamedMemberDTO{{Id: "u1", OldUsername: "Alice", NewUsername: "Alicia"}}, res.Renamed)
+		require.Len(t, res.Deactivated, 1)
+		require.Equal(t, deactivated, res.Deactivated[0].Id)
+		require.Len(t, res.Removed, 1)
+		require.Equal(t, removed, res.Removed[0].Id)
+		require.Empty(t, res.Reactivated)
+		require.Len(t, res.UpdPrs, updPrs)
+		for _, pr := range res.UpdPrs {
+			require.ElementsMatch(t, []string{kept, "u5"}, pr.AssignedReviewers)
+		}
+	}
+
+	var res v1.SyncTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodPut, "/team/sync?dry_run=true", sync, &res))
+	require.True(t, res.DryRun)
+	check(res, 0)
+
+	// A dry run changes nothing.
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.ElementsMatch(t, backend(), toMembers(team.Members))
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPut, "/team/sync", sync, &res))
+	require.False(t, res.DryRun)
+	check(res, 1)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.ElementsMatch(t, roster, toMembers(team.Members))
+
+	var review v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+removed, nil, &review))
+	require.Empty(t, review.PullRequests)
+
+	// Syncing the same roster again is a no-op; flipping a flag back reactivates.
+	require.Equal(t, http.StatusOK, s.do(http.MethodPut, "/team/sync", sync, &res))
+	require.Empty(t, res.Added)
+	require.Empty(t, res.Renamed)
+	require.Empty(t, res.Deactivated)
+	require.Empty(t, res.Removed)
+
+	roster[1].IsActive = true
+	require.Equal(t, http.StatusOK, s.do(http.MethodPut, "/team/sync", sync, &res))
+	require.Len(t, res.Reactivated, 1)
+	require.Equal(t, deactivated, res.Reactivated[0].Id)
+
+	s.addTeam("frontend", member{UserId: "u6", Username: "Frank", IsActive: true})
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "frontend", "members": []member{{UserId: "u1", Username: "Alicia", IsActive: true}}},
+		http.StatusConflict, e.USER_IN_TEAM)
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "unknown", "members": roster},
+		http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "backend", "members": []member{}},
+		http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "backend", "members": append(roster, roster[0])},
+		http.StatusBadRequest, e.BAD_REQUEST)
+
+	// Leaving nobody to review pr-1001 fails the whole sync.
+	s.expectError(http.MethodPut, "/team/sync", map[string]any{"team_name": "backend", "members": []member{{UserId: "u1", Username: "Alicia", IsActive: true}}},
+		http.StatusConflict, e.NO_CANDIDATE)
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	require.Len(t, team.Members, 4)
+}
+
+func toMembers(dtos []v1.TeamMemberDTO) []member {
+	members := make([]member, 0, len(dtos))
+	for _, m := range dtos {
+		members = append(members, member{UserId: m.Id, Username: m.Username, IsActive: *m.IsActive})
+	}
+	return members
+}
@@ -0,0 +1,174 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/domain"
+	"avito-internship/internal/outbox"
+	"avito-internship/internal/webhook"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	v "avito-internship/pkg/validator"
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var tombstoneId = regexp.MustCompile(`^tombstone-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestE2E_AnonymizeUser(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()[:3]...)
+
+	rec, url := newReceiver(t)
+	require.Equal(t, http.StatusCreated, s.doAdmin(http.MethodPost, "/webhooks/create",
+		map[string]any{"url": url, "events": []string{"pr.created"}}, nil))
+
+	s.createPR("pr-1001", "Add login", "u2")
+	// Two PRs are named after Alice's id. Their names must survive the rewrite
+	// of stored events and deliveries.
+	s.createPR("pr-1002", "u1", "u1")
+	s.createPR("pr-1003", "Fix typo", "u3")
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1003"}, nil))
+
+	// The pr.created deliveries are queued before the user is anonymized.
+	dispatcher := outbox.NewDispatcher(s.outbox, outbox.DefaultConfig(), logger.NewSlogLogger(), webhook.NewSink(s.webhook))
+	_, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "backend", "members": []member{backend()[3]}}, nil))
+	s.addTeam("ops", member{UserId: "u5", Username: "Eve", IsActive: true})
+	join := map[string]any{"user_id": "u1", "team_name": "ops"}
+	require.Equal(t, http.StatusOK, s.doIdempotent("/users/joinTeam", "join-1", "", join).status)
+	s.createPR("pr-1004", "u1", "u5")
+
+	require.Equal(t, http.StatusUnauthorized, s.do(http.MethodPost, "/admin/users/anonymize", map[string]any{"user_id": "u1"}, nil))
+
+	var res v1.AnonymizeUserRes
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodPost, "/admin/users/anonymize", map[string]any{"user_id": "u1"}, &res))
+	tombstone := res.User.Id
+	require.Regexp(t, tombstoneId, tombstone)
+	require.Equal(t, "Former user "+tombstone[len(tombstone)-12:], res.User.Username)
+	require.False(t, res.User.IsActive)
+	require.Equal(t, "backend", res.User.TeamName)
+
+	// Carol already reviews pr-1001 and Bob wrote it, so Dave takes over. Nobody
+	// but the author is left in ops, so the review of pr-1004 is dropped.
+	reassigned := make(map[string][]string)
+	for _, pr := range res.ReassignedPrs {
+		reassigned[pr.Id] = pr.AssignedReviewers
+	}
+	require.Len(t, reassigned, 2)
+	require.ElementsMatch(t, []string{"u3", "u4"}, reassigned["pr-1001"])
+	require.Empty(t, reassigned["pr-1004"])
+
+	s.expectError(http.MethodGet, "/users/get?user_id=u1", nil, http.StatusNotFound, e.NOT_FOUND)
+
+	// Authored PRs and past reviews stay with the tombstone.
+	var user v1.GetUserRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id="+tombstone, nil, &user))
+	require.Equal(t, 0, user.OpenReviews)
+	require.Equal(t, 1, user.AuthoredPrs)
+
+	var reviews v1.GetReviewRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/getReview?user_id="+tombstone, nil, &reviews))
+	require.Len(t, reviews.PullRequests, 1)
+	require.Equal(t, "pr-1003", reviews.PullRequests[0].Id)
+
+	var team v1.GetTeamRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/team/get?team_name=backend", nil, &team))
+	for _, m := range team.Members {
+		require.NotEqual(t, "u1", m.Id)
+		require.NotEqual(t, "Alice", m.Username)
+	}
+
+	// Anonymizing a tombstone changes nothing.
+	var again v1.AnonymizeUserRes
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodPost, "/admin/users/anonymize", map[string]any{"user_id": tombstone}, &again))
+	require.Equal(t, res.User, again.User)
+	require.Empty(t, again.ReassignedPrs)
+
+	// Only anonymization mints tombstone ids.
+	s.expectValidationError(http.MethodPost, "/team/members/add",
+		map[string]any{"team_name": "backend", "members": []member{{UserId: domain.NewTombstoneId(), Username: "Eve", IsActive: true}}},
+		`members[0].user_id: rule "newuserid" failed`)
+
+	s.expectAdminError(http.MethodPost, "/admin/users/anonymize", map[string]any{"user_id": "u1"}, http.StatusNotFound, e.NOT_FOUND)
+
+	// The stored response naming Alice is gone, so the retry runs again.
+	retry := s.doIdempotent("/users/joinTeam", "join-1", "", join)
+	require.False(t, retry.replayed)
+	require.Equal(t, http.StatusNotFound, retry.status)
+
+	// Queued deliveries and pending events name the tombstone instead.
+	worker := webhook.NewWorker(s.webhook, webhook.DefaultConfig(), logger.NewSlogLogger())
+	n, err := worker.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	for _, body := range rec.bodies {
+		require.NotContains(t, strings.ReplaceAll(string(body), `"pull_request_name":"u1"`, ""), `"u1"`)
+	}
+	require.Contains(t, string(rec.bodies[1]), `"pull_request_name":"u1","author_id":"`+tombstone+`"`)
+
+	events, err := s.outbox.Claim(context.Background(), 100, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	var created, reassignedTo, unassigned, anonymized []string
+	for _, event := range events {
+		require.NotContains(t, strings.ReplaceAll(string(event.Payload), `"pull_request_name":"u1"`, ""), `"u1"`)
+
+		switch event.Type {
+		case domain.EventPRCreated:
+			var payload domain.PRCreatedPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			created = append(created, payload.PullRequestId+" "+payload.Name+" "+strings.Join(payload.Reviewers, ","))
+		case domain.EventReviewerReassigned:
+			var payload domain.ReviewerReassignedPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			if payload.OldReviewerId == tombstone {
+				reassignedTo = append(reassignedTo, payload.PullRequestId+" "+payload.NewReviewerId)
+			}
+		case domain.EventReviewerUnassigned:
+			var payload domain.ReviewerUnassignedPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			unassigned = append(unassigned, payload.PullRequestId+" "+payload.ReviewerId)
+		case domain.EventUserAnonymized:
+			var payload domain.UserAnonymizedPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			anonymized = append(anonymized, payload.TombstoneId)
+		}
+	}
+	require.Equal(t, []string{"pr-1004 u1 " + tombstone}, created)
+	require.Equal(t, []string{"pr-1001 u4"}, reassignedTo)
+	require.Equal(t, []string{"pr-1004 " + tombstone}, unassigned)
+	require.Equal(t, []string{tombstone}, anonymized)
+}
+
+func TestE2E_TombstonePrefixIsReserved(t *testing.T) {
+	s := newTestServer(t)
+
+	cfg := v.DefaultConfig()
+	cfg.UserId = `[a-z0-9-]+`
+	require.NoError(t, v.RegisterValidators(cfg))
+	t.Cleanup(func() { require.NoError(t, v.RegisterValidators(v.DefaultConfig())) })
+
+	// The configured format matches the id, the reserved prefix still wins.
+	s.expectValidationError(http.MethodPost, "/team/add",
+		map[string]any{"team_name": "backend", "members": []member{{UserId: "tombstone-1", Username: "Eve", IsActive: true}}},
+		`members[0].user_id: rule "newuserid" failed: must match [a-z0-9-]+ or be a generated u-<UUIDv7>, not start with tombstone-, and be at most 50 characters long`)
+	s.addTeam("backend", member{UserId: "tomb-1", Username: "Eve", IsActive: true})
+}
+
+func (s *testServer) expectAdminError(method, path string, body any, status int, code string) {
+	s.t.Helper()
+
+	var res v1.ErrorResponse
+	require.Equal(s.t, status, s.doAdmin(method, path, body, &res))
+	require.Equal(s.t, code, res.Error.Code)
+}
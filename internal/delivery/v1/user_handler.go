@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"avito-internship/pkg/e"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +9,7 @@ import (
 func (h *Handler) setIsActive(c *gin.Context) {
 	var req SetIsActiveReq
 	if err := c.ShouldBind(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -26,7 +25,7 @@ func (h *Handler) setIsActive(c *gin.Context) {
 func (h *Handler) getReview(c *gin.Context) {
 	var req GetReviewQueryReq
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.Error(e.Wrap(err.Error(), e.ErrInvalidRequestBody))
+		c.Error(invalidRequest(err))
 		return
 	}
 
@@ -38,3 +37,83 @@ func (h *Handler) getReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, toDeliveryGetReviewRes(res))
 }
+
+func (h *Handler) transferUser(c *gin.Context) {
+	var req TransferUserReq
+	if err := c.ShouldBind(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.userUC.Transfer(c.Request.Context(), toUseCaseTransferUserReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryTransferUserRes(res))
+}
+
+func (h *Handler) joinTeam(c *gin.Context) {
+	var req JoinTeamReq
+	if err := c.ShouldBind(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.userUC.JoinTeam(c.Request.Context(), toUseCaseJoinTeamReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryJoinTeamRes(res))
+}
+
+func (h *Handler) getUser(c *gin.Context) {
+	var req GetUserQueryReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.userUC.GetUser(c.Request.Context(), req.UserId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryGetUserRes(res))
+}
+
+func (h *Handler) listUsers(c *gin.Context) {
+	var req ListUsersQueryReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.userUC.ListUsers(c.Request.Context(), toUseCaseListUsersReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryListUsersRes(res))
+}
+
+func (h *Handler) anonymizeUser(c *gin.Context) {
+	var req AnonymizeUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.userUC.Anonymize(c.Request.Context(), toUseCaseAnonymizeUserReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryAnonymizeUserRes(res))
+}
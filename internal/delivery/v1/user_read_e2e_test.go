@@ -0,0 +1,96 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/pkg/e"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestE2E_GetUser(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("platform", member{UserId: "u5", Username: "Eve", IsActive: true})
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/joinTeam", map[string]any{"user_id": "u1", "team_name": "platform"}, nil))
+
+	pr := s.createPR("pr-1001", "Add login", "u1")
+	s.createPR("pr-1002", "Add logout", "u1")
+
+	var user v1.GetUserRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id=u1", nil, &user))
+	require.Equal(t, v1.GetUserRes{
+		User:        v1.UserDTO{Id: "u1", Username: "Alice", TeamName: "backend", Teams: []string{"backend", "platform"}, IsActive: true},
+		OpenReviews: 0,
+		AuthoredPrs: 2,
+	}, user)
+
+	reviewerId := pr.PullRequest.AssignedReviewers[0]
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id="+reviewerId, nil, &user))
+	require.GreaterOrEqual(t, user.OpenReviews, 1)
+	require.Zero(t, user.AuthoredPrs)
+	openReviews := user.OpenReviews
+
+	// Merged PRs do not count as open reviews.
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1001"}, nil))
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/get?user_id="+reviewerId, nil, &user))
+	require.Equal(t, openReviews-1, user.OpenReviews)
+
+	s.expectError(http.MethodGet, "/users/get?user_id=u999", nil, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodGet, "/users/get?user_id=bad", nil, http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodGet, "/users/get", nil, http.StatusBadRequest, e.BAD_REQUEST)
+}
+
+func TestE2E_ListUsers(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+	s.addTeam("platform", member{UserId: "u5", Username: "Eve", IsActive: true}, member{UserId: "u50", Username: "Carla", IsActive: false})
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/users/joinTeam", map[string]any{"user_id": "u1", "team_name": "platform"}, nil))
+
+	ids := func(res v1.ListUsersRes) []string {
+		result := make([]string, 0, len(res.Users))
+		for _, user := range res.Users {
+			result = append(result, user.Id)
+		}
+		return result
+	}
+
+	var res v1.ListUsersRes
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list", nil, &res))
+	require.Equal(t, []string{"u1", "u2", "u3", "u4", "u5", "u50"}, ids(res))
+	require.Equal(t, 6, res.Total)
+	require.Equal(t, 50, res.Limit)
+	require.Equal(t, v1.UserListItemDTO{Id: "u1", Username: "Alice", TeamName: "backend", IsActive: true}, res.Users[0])
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?limit=2&offset=1", nil, &res))
+	require.Equal(t, []string{"u2", "u3"}, ids(res))
+	require.Equal(t, 6, res.Total)
+	require.Equal(t, 1, res.Offset)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?query=u5", nil, &res))
+	require.Equal(t, []string{"u5", "u50"}, ids(res))
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?query=car", nil, &res))
+	require.Equal(t, []string{"u3", "u50"}, ids(res))
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?query=%25", nil, &res))
+	require.Empty(t, res.Users)
+	require.Zero(t, res.Total)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?team_name=platform", nil, &res))
+	require.Equal(t, []string{"u1", "u5", "u50"}, ids(res))
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?team_name=platform&is_active=false", nil, &res))
+	require.Equal(t, []string{"u50"}, ids(res))
+	require.Equal(t, "platform", res.Users[0].TeamName)
+
+	require.Equal(t, http.StatusOK, s.do(http.MethodGet, "/users/list?offset=10", nil, &res))
+	require.Empty(t, res.Users)
+	require.Equal(t, 6, res.Total)
+
+	s.expectError(http.MethodGet, "/users/list?team_name=unknown", nil, http.StatusNotFound, e.NOT_FOUND)
+	s.expectError(http.MethodGet, "/users/list?limit=501", nil, http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodGet, "/users/list?offset=-1", nil, http.StatusBadRequest, e.BAD_REQUEST)
+	s.expectError(http.MethodGet, "/users/list?is_active=maybe", nil, http.StatusBadRequest, e.BAD_REQUEST)
+}
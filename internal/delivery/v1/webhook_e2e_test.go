@@ -0,0 +1,244 @@
+package v1_test
+
+import (
+	v1 "avito-internship/internal/delivery/v1"
+	"avito-internship/internal/domain"
+	"avito-internship/internal/outbox"
+	"avito-internship/internal/webhook"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook endpoint that answers with status and records what it got.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T) (*receiver, string) {
+	rec := &receiver{status: http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		w.WriteHeader(rec.status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return rec, srv.URL
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func TestE2E_WebhookSubscriptions(t *testing.T) {
+	s := newTestServer(t)
+
+	create := map[string]any{"url": "https://example.com/hook", "events": []string{"pr.merged", "pr.created", "pr.merged"}}
+	require.Equal(t, http.StatusUnauthorized, s.do(http.MethodPost, "/webhooks/create", create, nil))
+
+	var created v1.CreateWebhookRes
+	require.Equal(t, http.StatusCreated, s.doAdmin(http.MethodPost, "/webhooks/create", create, &created))
+	require.Equal(t, []domain.EventType{domain.EventPRCreated, domain.EventPRMerged}, created.Subscription.Events)
+	require.True(t, created.Subscription.IsActive)
+	require.Len(t, created.Secret, 64, "a secret is generated when none is given")
+
+	var list v1.ListWebhooksRes
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodGet, "/webhooks/list", nil, &list))
+	require.Equal(t, []v1.WebhookSubscriptionDTO{created.Subscription}, list.Subscriptions)
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{name: "unknown event", body: map[string]any{"url": "https://example.com", "events": []string{"pr.closed"}}},
+		{name: "not http", body: map[string]any{"url": "ftp://example.com", "events": []string{"pr.merged"}}},
+		{name: "no events", body: map[string]any{"url": "https://example.com", "events": []string{}}},
+		{name: "short secret", body: map[string]any{"url": "https://example.com", "events": []string{"pr.merged"}, "secret": "abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res v1.ErrorResponse
+			require.Equal(t, http.StatusBadRequest, s.doAdmin(http.MethodPost, "/webhooks/create", tt.body, &res))
+			require.Equal(t, e.BAD_REQUEST, res.Error.Code)
+		})
+	}
+
+	id := map[string]any{"subscription_id": created.Subscription.Id}
+	require.Equal(t, http.StatusNoContent, s.doAdmin(http.MethodPost, "/webhooks/delete", id, nil))
+
+	var res v1.ErrorResponse
+	require.Equal(t, http.StatusNotFound, s.doAdmin(http.MethodPost, "/webhooks/delete", id, &res))
+	require.Equal(t, e.NOT_FOUND, res.Error.Code)
+	path := "/webhooks/deliveries?subscription_id=" + strconv.Itoa(created.Subscription.Id)
+	require.Equal(t, http.StatusNotFound, s.doAdmin(http.MethodGet, path, nil, &res))
+}
+
+func TestE2E_WebhookDelivery(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	rec, url := newReceiver(t)
+	const secret = "0123456789abcdef0123456789abcdef"
+
+	var merged, all v1.CreateWebhookRes
+	require.Equal(t, http.StatusCreated, s.doAdmin(http.MethodPost, "/webhooks/create",
+		map[string]any{"url": url + "/merged", "events": []string{"pr.merged"}, "secret": secret}, &merged))
+	require.Equal(t, secret, merged.Secret)
+	require.Equal(t, http.StatusCreated, s.doAdmin(http.MethodPost, "/webhooks/create",
+		map[string]any{"url": url + "/all", "events": []string{"pr.created", "pr.merged"}}, &all))
+
+	s.createPR("pr-1001", "Add login", "u1")
+	require.Equal(t, http.StatusOK, s.do(http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1001"}, nil))
+
+	dispatcher := outbox.NewDispatcher(s.outbox, outbox.DefaultConfig(), logger.NewSlogLogger(), webhook.NewSink(s.webhook))
+	_, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	worker := webhook.NewWorker(s.webhook, webhook.DefaultConfig(), logger.NewSlogLogger())
+	n, err := worker.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, 3, rec.count())
+
+	var paths []string
+	for i, req := range rec.requests {
+		paths = append(paths, req.URL.Path)
+
+		key := all.Secret
+		if req.URL.Path == "/merged" {
+			key = secret
+		}
+		require.True(t, webhook.Verify(key, rec.bodies[i], req.Header.Get(webhook.HeaderSignature)))
+		require.NotEmpty(t, req.Header.Get(webhook.HeaderDelivery))
+
+		var envelope webhook.Envelope
+		require.NoError(t, json.Unmarshal(rec.bodies[i], &envelope))
+		require.Equal(t, string(envelope.Type), req.Header.Get(webhook.HeaderEvent))
+	}
+	require.Equal(t, []string{"/all", "/merged", "/all"}, paths)
+
+	var payload domain.PRMergedPayload
+	var envelope webhook.Envelope
+	require.NoError(t, json.Unmarshal(rec.bodies[1], &envelope))
+	require.Equal(t, domain.EventPRMerged, envelope.Type)
+	require.NoError(t, json.Unmarshal(envelope.Data, &payload))
+	require.Equal(t, "pr-1001", payload.PullRequestId)
+
+	var log v1.GetWebhookDeliveriesRes
+	path := "/webhooks/deliveries?subscription_id=" + strconv.Itoa(all.Subscription.Id)
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodGet, path, nil, &log))
+	require.Len(t, log.Deliveries, 2)
+	require.Equal(t, domain.EventPRMerged, log.Deliveries[0].EventType, "newest first")
+	for _, d := range log.Deliveries {
+		require.Equal(t, domain.DeliveryDelivered, d.Status)
+		require.Equal(t, 1, d.Attempts)
+		require.Equal(t, http.StatusOK, *d.ResponseCode)
+		require.NotNil(t, d.DeliveredAt)
+	}
+
+	// Enqueuing an event again, as the outbox may after a crash, sends nothing new.
+	var event domain.Event
+	event.Id, event.Type = log.Deliveries[0].EventId, domain.EventPRMerged
+	require.NoError(t, webhook.NewSink(s.webhook).Deliver(context.Background(), event))
+	n, err = worker.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestE2E_WebhookRetryAndDisable(t *testing.T) {
+	s := newTestServer(t)
+	s.addTeam("backend", backend()...)
+
+	rec, url := newReceiver(t)
+	rec.setStatus(http.StatusInternalServerError)
+
+	var created v1.CreateWebhookRes
+	require.Equal(t, http.StatusCreated, s.doAdmin(http.MethodPost, "/webhooks/create",
+		map[string]any{"url": url, "events": []string{"pr.created"}}, &created))
+
+	s.createPR("pr-1001", "Add login", "u1")
+	s.createPR("pr-1002", "Fix bug", "u2")
+
+	dispatcher := outbox.NewDispatcher(s.outbox, outbox.DefaultConfig(), logger.NewSlogLogger(), webhook.NewSink(s.webhook))
+	_, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+
+	cfg := webhook.DefaultConfig()
+	cfg.BatchSize = 1
+	cfg.MaxAttempts = 2
+	cfg.RetryBackoff = time.Millisecond
+	cfg.RetryMaxBackoff = time.Millisecond
+	cfg.DisableAfter = 3
+	worker := webhook.NewWorker(s.webhook, cfg, logger.NewSlogLogger())
+
+	dispatch := func() int {
+		time.Sleep(5 * time.Millisecond)
+		n, err := worker.Dispatch(context.Background())
+		require.NoError(t, err)
+		return n
+	}
+
+	require.Equal(t, 1, dispatch())
+	require.Equal(t, 1, dispatch())
+	require.Equal(t, 1, dispatch(), "the third failure in a row disables the subscription")
+	require.Zero(t, dispatch(), "deliveries of a disabled subscription are held back")
+	require.Equal(t, 3, rec.count())
+
+	var list v1.ListWebhooksRes
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodGet, "/webhooks/list", nil, &list))
+	require.False(t, list.Subscriptions[0].IsActive)
+	require.Equal(t, 3, list.Subscriptions[0].ConsecutiveFailures)
+	require.NotNil(t, list.Subscriptions[0].DisabledAt)
+
+	var log v1.GetWebhookDeliveriesRes
+	path := "/webhooks/deliveries?subscription_id=" + strconv.Itoa(created.Subscription.Id)
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodGet, path, nil, &log))
+	require.Len(t, log.Deliveries, 2)
+	first, second := log.Deliveries[1], log.Deliveries[0]
+	require.Equal(t, domain.DeliveryFailed, first.Status, "gives up after max attempts")
+	require.Equal(t, 2, first.Attempts)
+	require.Equal(t, http.StatusInternalServerError, *first.ResponseCode)
+	require.Equal(t, "unexpected status 500", *first.LastError)
+	require.Equal(t, domain.DeliveryPending, second.Status)
+	require.Equal(t, 1, second.Attempts)
+	require.NotNil(t, second.NextAttemptAt)
+
+	rec.setStatus(http.StatusNoContent)
+	var enabled v1.EnableWebhookRes
+	id := map[string]any{"subscription_id": created.Subscription.Id}
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodPost, "/webhooks/enable", id, &enabled))
+	require.True(t, enabled.Subscription.IsActive)
+	require.Zero(t, enabled.Subscription.ConsecutiveFailures)
+
+	require.Equal(t, 1, dispatch(), "pending deliveries resume once enabled")
+	require.Equal(t, http.StatusOK, s.doAdmin(http.MethodGet, path, nil, &log))
+	require.Equal(t, domain.DeliveryDelivered, log.Deliveries[0].Status)
+	require.Equal(t, http.StatusNoContent, *log.Deliveries[0].ResponseCode)
+}
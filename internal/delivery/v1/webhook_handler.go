@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"avito-internship/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookUC  usecase.WebhookUC
+	middleware *Middleware
+}
+
+func NewWebhookHandler(webhookUC usecase.WebhookUC, middleware *Middleware) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUC:  webhookUC,
+		middleware: middleware,
+	}
+}
+
+func (h *WebhookHandler) Init(r *gin.Engine) {
+	webhooks := r.Group("/webhooks", h.middleware.AdminMiddleware(), h.middleware.Idempotency())
+	{
+		webhooks.POST("/create", h.createWebhook)
+		webhooks.GET("/list", h.listWebhooks)
+		webhooks.POST("/delete", h.deleteWebhook)
+		webhooks.POST("/enable", h.enableWebhook)
+		webhooks.GET("/deliveries", h.getDeliveries)
+	}
+}
+
+func (h *WebhookHandler) createWebhook(c *gin.Context) {
+	var req CreateWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.webhookUC.CreateWebhook(c.Request.Context(), toUseCaseCreateWebhookReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toDeliveryCreateWebhookRes(res))
+}
+
+func (h *WebhookHandler) listWebhooks(c *gin.Context) {
+	res, err := h.webhookUC.ListWebhooks(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryListWebhooksRes(res))
+}
+
+func (h *WebhookHandler) deleteWebhook(c *gin.Context) {
+	var req WebhookIdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := h.webhookUC.DeleteWebhook(c.Request.Context(), req.SubscriptionId); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) enableWebhook(c *gin.Context) {
+	var req WebhookIdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.webhookUC.EnableWebhook(c.Request.Context(), req.SubscriptionId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryEnableWebhookRes(res))
+}
+
+func (h *WebhookHandler) getDeliveries(c *gin.Context) {
+	var req GetWebhookDeliveriesQueryReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(invalidRequest(err))
+		return
+	}
+
+	res, err := h.webhookUC.GetWebhookDeliveries(c.Request.Context(), toUseCaseGetWebhookDeliveriesReq(req))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryGetWebhookDeliveriesRes(res))
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventReviewerUnassigned EventType = "reviewer.unassigned"
+	EventPRMerged           EventType = "pr.merged"
+	EventUserDeactivated    EventType = "user.deactivated"
+	EventUserTransferred    EventType = "user.transferred"
+	EventUserAnonymized     EventType = "user.anonymized"
+)
+
+var EventTypes = []EventType{
+	EventPRCreated,
+	EventReviewerAssigned,
+	EventReviewerReassigned,
+	EventReviewerUnassigned,
+	EventPRMerged,
+	EventUserDeactivated,
+	EventUserTransferred,
+	EventUserAnonymized,
+}
+
+func (t EventType) Known() bool {
+	return slices.Contains(EventTypes, t)
+}
+
+// Event is a domain event stored in the outbox. Payload holds one of the
+// *Payload types below encoded as JSON.
+type Event struct {
+	Id        int64
+	Type      EventType
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+func NewEvent(eventType EventType, payload []byte) Event {
+	return Event{
+		Type:    eventType,
+		Payload: payload,
+	}
+}
+
+type PRCreatedPayload struct {
+	PullRequestId string    `json:"pull_request_id"`
+	Name          string    `json:"pull_request_name"`
+	AuthorId      string    `json:"author_id"`
+	Reviewers     []string  `json:"assigned_reviewers"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReviewerAssignedPayload struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+}
+
+type ReviewerReassignedPayload struct {
+	PullRequestId string `json:"pull_request_id"`
+	OldReviewerId string `json:"old_reviewer_id"`
+	NewReviewerId string `json:"new_reviewer_id"`
+}
+
+// ReviewerUnassignedPayload reports a reviewer removed with nobody to take over.
+type ReviewerUnassignedPayload struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+}
+
+type PRMergedPayload struct {
+	PullRequestId string    `json:"pull_request_id"`
+	MergedAt      time.Time `json:"merged_at"`
+}
+
+type UserDeactivatedPayload struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type UserTransferredPayload struct {
+	UserId   string `json:"user_id"`
+	FromTeam string `json:"from_team"`
+	ToTeam   string `json:"to_team"`
+}
+
+// UserAnonymizedPayload names the new tombstone only. Stored events are
+// rewritten to the tombstone id, so the erased id is not published again.
+type UserAnonymizedPayload struct {
+	TombstoneId string `json:"tombstone_id"`
+}
+
+// userIdFields are the payload fields above that hold user ids.
+var userIdFields = map[string]bool{
+	"author_id":          true,
+	"assigned_reviewers": true,
+	"reviewer_id":        true,
+	"old_reviewer_id":    true,
+	"new_reviewer_id":    true,
+	"user_id":            true,
+}
+
+// ReplaceUserId renames the user in a stored payload or in a webhook body
+// wrapping one. Only the user id fields change, so other strings equal to the
+// id, e.g. a PR name, stay as they are. Object keys keep their order. It
+// reports whether anything was replaced.
+func ReplaceUserId(doc []byte, oldId, newId string) ([]byte, bool, error) {
+	return replaceUserId(doc, false, oldId, newId)
+}
+
+func replaceUserId(raw json.RawMessage, isUserId bool, oldId, newId string) (json.RawMessage, bool, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return raw, false, nil
+	}
+
+	switch raw[0] {
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if _, err := dec.Token(); err != nil {
+			return nil, false, err
+		}
+
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		changed := false
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, false, err
+			}
+			key, _ := token.(string)
+
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, false, err
+			}
+			value, replaced, err := replaceUserId(value, userIdFields[key], oldId, newId)
+			if err != nil {
+				return nil, false, err
+			}
+			changed = changed || replaced
+
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			name, _ := json.Marshal(key)
+			buf.Write(name)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		if !changed {
+			return raw, false, nil
+		}
+		buf.WriteByte('}')
+
+		return buf.Bytes(), true, nil
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, false, err
+		}
+
+		changed := false
+		for i, item := range items {
+			item, replaced, err := replaceUserId(item, isUserId, oldId, newId)
+			if err != nil {
+				return nil, false, err
+			}
+			items[i] = item
+			changed = changed || replaced
+		}
+		if !changed {
+			return raw, false, nil
+		}
+
+		res, err := json.Marshal(items)
+		return res, true, err
+	case '"':
+		var id string
+		if !isUserId || json.Unmarshal(raw, &id) != nil || id != oldId {
+			return raw, false, nil
+		}
+
+		res, err := json.Marshal(newId)
+		return res, true, err
+	default:
+		return raw, false, nil
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Prefixes of server-generated ids. The rest is a UUIDv7, so generated ids
+// sort by creation time and never collide with the numeric external ones.
+const (
+	UserIdPrefix        = "u-"
+	PullRequestIdPrefix = "pr-"
+)
+
+func NewUserId() string {
+	return UserIdPrefix + uuid.Must(uuid.NewV7()).String()
+}
+
+func NewPullRequestId() string {
+	return PullRequestIdPrefix + uuid.Must(uuid.NewV7()).String()
+}
+
+// TombstoneIdPrefix starts the ids of anonymized users. The tombstone keeps the
+// authored PRs and reviews of the user without their original id and name.
+const TombstoneIdPrefix = "tombstone-"
+
+func NewTombstoneId() string {
+	return TombstoneIdPrefix + uuid.Must(uuid.NewV7()).String()
+}
+
+// Pseudonym derives the name of a tombstone from the random tail of its id.
+func Pseudonym(tombstoneId string) string {
+	return "Former user " + tombstoneId[max(len(tombstoneId)-12, 0):]
+}
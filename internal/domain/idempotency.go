@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// IdempotencyKey remembers the response to a request sent with an
+// Idempotency-Key header. StatusCode is zero while the request is in progress.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
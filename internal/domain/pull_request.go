@@ -10,6 +10,8 @@ type PullRequest struct {
 	NeedMoreReviewers bool
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	// TeamId is the team reviewers come from; zero means the author's primary team.
+	TeamId int
 }
 
 func NewPoolRequest(id, name, authorId string, statusId int, needMoreReviewers bool, createdAt time.Time) *PullRequest {
@@ -3,6 +3,10 @@ package domain
 type Team struct {
 	Id   int
 	Name string
+	// ParentId is 0 for a top-level team.
+	ParentId int
+	// MaxDepth limits how many levels up reviewer search may widen.
+	MaxDepth int
 }
 
 func NewTeam(name string) Team {
@@ -10,3 +14,12 @@ func NewTeam(name string) Team {
 		Name: name,
 	}
 }
+
+// ReviewerSource tells where a reviewer was found relative to the PR team.
+type ReviewerSource string
+
+const (
+	SourceTeam    ReviewerSource = "team"
+	SourceSibling ReviewerSource = "sibling"
+	SourceParent  ReviewerSource = "parent"
+)
@@ -1,5 +1,6 @@
 package domain
 
+// User is a team member. TeamId is zero once the user has been removed from their team.
 type User struct {
 	Id       string
 	Name     string
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryFailed    DeliveryStatus = "FAILED"
+)
+
+type WebhookSubscription struct {
+	Id                  int
+	URL                 string
+	Events              []EventType
+	Secret              string
+	IsActive            bool
+	ConsecutiveFailures int
+	CreatedAt           time.Time
+	DisabledAt          *time.Time
+}
+
+func NewWebhookSubscription(url string, events []EventType, secret string) WebhookSubscription {
+	return WebhookSubscription{
+		URL:      url,
+		Events:   events,
+		Secret:   secret,
+		IsActive: true,
+	}
+}
+
+// WebhookDelivery is one event queued for one subscription. Body is the signed
+// request body, kept as is so that retries send identical bytes.
+type WebhookDelivery struct {
+	Id             int64
+	SubscriptionId int
+	EventId        int64
+	EventType      EventType
+	Body           []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseCode   *int
+	LastError      *string
+	CreatedAt      time.Time
+	NextAttemptAt  time.Time
+	DeliveredAt    *time.Time
+}
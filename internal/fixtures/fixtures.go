@@ -0,0 +1,120 @@
+// Package fixtures loads named sets of demo data into the storage and removes them.
+// Sets are YAML files embedded from db/fixtures; loading is idempotent.
+package fixtures
+
+import (
+	migrations "avito-internship/db"
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dir = "fixtures"
+
+var ErrUnknownSet = errors.New("unknown fixture set")
+
+type Set struct {
+	Name         string        `yaml:"-"`
+	Teams        []Team        `yaml:"teams"`
+	PullRequests []PullRequest `yaml:"pull_requests"`
+}
+
+type Team struct {
+	Name    string   `yaml:"name"`
+	Members []Member `yaml:"members"`
+}
+
+type Member struct {
+	Id       string `yaml:"id"`
+	Name     string `yaml:"name"`
+	Inactive bool   `yaml:"inactive"`
+}
+
+type PullRequest struct {
+	Id        string          `yaml:"id"`
+	Name      string          `yaml:"name"`
+	AuthorId  string          `yaml:"author"`
+	Status    domain.PRStatus `yaml:"status"`
+	Age       time.Duration   `yaml:"age"`
+	Reviewers []string        `yaml:"reviewers"`
+}
+
+// Names lists the embedded fixture sets.
+func Names() ([]string, error) {
+	const op = "fixtures.Names"
+
+	entries, err := fs.ReadDir(migrations.Fixtures, dir)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".yaml"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Get parses the embedded fixture set with the given name.
+func Get(name string) (Set, error) {
+	const op = "fixtures.Get"
+
+	raw, err := fs.ReadFile(migrations.Fixtures, path.Join(dir, name+".yaml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Set{}, e.Wrap(op, fmt.Errorf("%w: %q", ErrUnknownSet, name))
+	}
+	if err != nil {
+		return Set{}, e.Wrap(op, err)
+	}
+
+	set := Set{Name: name}
+	if err := yaml.Unmarshal(raw, &set); err != nil {
+		return Set{}, e.Wrap(op, fmt.Errorf("%s: %w", name, err))
+	}
+
+	for i := range set.PullRequests {
+		if set.PullRequests[i].Status == "" {
+			set.PullRequests[i].Status = domain.OPEN
+		}
+	}
+
+	return set, nil
+}
+
+func (s Set) userIds() []string {
+	var ids []string
+	for _, team := range s.Teams {
+		for _, member := range team.Members {
+			ids = append(ids, member.Id)
+		}
+	}
+	return ids
+}
+
+func (s Set) prIds() []string {
+	ids := make([]string, 0, len(s.PullRequests))
+	for _, pr := range s.PullRequests {
+		ids = append(ids, pr.Id)
+	}
+	return ids
+}
+
+func (s Set) teamNames() []string {
+	names := make([]string, 0, len(s.Teams))
+	for _, team := range s.Teams {
+		names = append(names, team.Name)
+	}
+	return names
+}
@@ -0,0 +1,87 @@
+package fixtures_test
+
+import (
+	"avito-internship/internal/fixtures"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/sqlite"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSeeder(t *testing.T) (*fixtures.Seeder, *sql.DB) {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "fixtures.db"))
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+	require.NoError(t, db.RunMigrations(context.Background(), logger.NewSlogLogger()))
+
+	return fixtures.NewSeeder(db.DB, sq.Question, 2), db.DB
+}
+
+func count(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+	return n
+}
+
+func TestGet(t *testing.T) {
+	names, err := fixtures.Names()
+	require.NoError(t, err)
+	assert.Contains(t, names, "demo")
+
+	set, err := fixtures.Get("demo")
+	require.NoError(t, err)
+	assert.Len(t, set.Teams, 1)
+	assert.Len(t, set.PullRequests, 6)
+
+	_, err = fixtures.Get("missing")
+	assert.ErrorIs(t, err, fixtures.ErrUnknownSet)
+}
+
+func TestSeeder_LoadIsIdempotent(t *testing.T) {
+	seeder, db := newSeeder(t)
+	set, err := fixtures.Get("demo")
+	require.NoError(t, err)
+
+	for range 2 {
+		require.NoError(t, seeder.Load(context.Background(), set))
+
+		assert.Equal(t, 1, count(t, db, "teams"))
+		assert.Equal(t, 8, count(t, db, "users"))
+		assert.Equal(t, 6, count(t, db, "pull_requests"))
+		assert.Equal(t, 12, count(t, db, "pr_reviewers"))
+	}
+}
+
+func TestSeeder_Cleanup(t *testing.T) {
+	seeder, db := newSeeder(t)
+	set, err := fixtures.Get("demo")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, seeder.Load(ctx, set))
+	_, err = db.Exec(`INSERT INTO pull_requests(id, name, author_id, status_id, created_at) VALUES ('pr-2001', 'Real work', 'u1', 1, CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+
+	require.NoError(t, seeder.Cleanup(ctx, set))
+
+	assert.Equal(t, 1, count(t, db, "pull_requests"), "only the non-fixture PR remains")
+	assert.Equal(t, 1, count(t, db, "users"), "the author of a non-fixture PR is kept")
+	assert.Equal(t, 1, count(t, db, "teams"), "a team with remaining members is kept")
+
+	_, err = db.Exec(`DELETE FROM pull_requests WHERE id = 'pr-2001'`)
+	require.NoError(t, err)
+	require.NoError(t, seeder.Cleanup(ctx, set))
+
+	assert.Equal(t, 0, count(t, db, "users"))
+	assert.Equal(t, 0, count(t, db, "teams"))
+}
@@ -0,0 +1,170 @@
+package fixtures
+
+import (
+	"avito-internship/pkg/e"
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Seeder writes fixture sets through database/sql, so the same statements serve
+// PostgreSQL and SQLite; only the placeholder format differs.
+type Seeder struct {
+	db           *sql.DB
+	builder      sq.StatementBuilderType
+	maxReviewers int
+}
+
+func NewSeeder(db *sql.DB, placeholder sq.PlaceholderFormat, maxReviewers int) *Seeder {
+	return &Seeder{
+		db:           db,
+		builder:      sq.StatementBuilder.PlaceholderFormat(placeholder),
+		maxReviewers: maxReviewers,
+	}
+}
+
+// Load inserts the set in one transaction. Rows that already exist are left as they are,
+// so loading a set again is a no-op.
+func (s *Seeder) Load(ctx context.Context, set Set) error {
+	const op = "Seeder.Load"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer tx.Rollback()
+
+	for _, team := range set.Teams {
+		if err := s.exec(ctx, tx, s.builder.Insert("teams").
+			Columns("name").
+			Values(team.Name).
+			Suffix("ON CONFLICT (name) DO NOTHING")); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		var teamId int
+		query, args, err := s.builder.Select("id").From("teams").Where(sq.Eq{"name": team.Name}).ToSql()
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&teamId); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		if len(team.Members) == 0 {
+			continue
+		}
+
+		users := s.builder.Insert("users").
+			Columns("id", "name", "is_active", "team_id").
+			Suffix("ON CONFLICT (id) DO NOTHING")
+		memberships := s.builder.Insert("team_members").
+			Columns("team_id", "user_id").
+			Suffix("ON CONFLICT DO NOTHING")
+		for _, member := range team.Members {
+			users = users.Values(member.Id, member.Name, !member.Inactive, teamId)
+			memberships = memberships.Values(teamId, member.Id)
+		}
+		if err := s.exec(ctx, tx, users); err != nil {
+			return e.Wrap(op, err)
+		}
+		if err := s.exec(ctx, tx, memberships); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	now := time.Now()
+	for _, pr := range set.PullRequests {
+		var statusId int
+		query, args, err := s.builder.Select("id").From("statuses").Where(sq.Eq{"name": pr.Status}).ToSql()
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&statusId); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		if err := s.exec(ctx, tx, s.builder.Insert("pull_requests").
+			Columns("id", "name", "author_id", "status_id", "need_more_reviewers", "created_at").
+			Values(pr.Id, pr.Name, pr.AuthorId, statusId, len(pr.Reviewers) < s.maxReviewers, now.Add(-pr.Age)).
+			Suffix("ON CONFLICT (id) DO NOTHING")); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		if len(pr.Reviewers) == 0 {
+			continue
+		}
+
+		reviewers := s.builder.Insert("pr_reviewers").
+			Columns("reviewer_id", "pr_id").
+			Suffix("ON CONFLICT DO NOTHING")
+		for _, reviewerId := range pr.Reviewers {
+			reviewers = reviewers.Values(reviewerId, pr.Id)
+		}
+		if err := s.exec(ctx, tx, reviewers); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Cleanup removes the rows of the set in one transaction. Users and teams that
+// other data still references are kept.
+func (s *Seeder) Cleanup(ctx context.Context, set Set) error {
+	const op = "Seeder.Cleanup"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer tx.Rollback()
+
+	if prIds := set.prIds(); len(prIds) > 0 {
+		if err := s.exec(ctx, tx, s.builder.Delete("pr_reviewers").Where(sq.Eq{"pr_id": prIds})); err != nil {
+			return e.Wrap(op, err)
+		}
+		if err := s.exec(ctx, tx, s.builder.Delete("pull_requests").Where(sq.Eq{"id": prIds})); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	if userIds := set.userIds(); len(userIds) > 0 {
+		if err := s.exec(ctx, tx, s.builder.Delete("users").
+			Where(sq.Eq{"id": userIds}).
+			Where("NOT EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = users.id)").
+			Where("NOT EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.reviewer_id = users.id)")); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	if teamNames := set.teamNames(); len(teamNames) > 0 {
+		if err := s.exec(ctx, tx, s.builder.Delete("teams").
+			Where(sq.Eq{"name": teamNames}).
+			Where("NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = teams.id)")); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *Seeder) exec(ctx context.Context, tx *sql.Tx, builder sq.Sqlizer) error {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"avito-internship/pkg/e"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	perPage        = 100
+	maxPages       = 50
+	apiVersion     = "2022-11-28"
+	requestTimeout = 30 * time.Second
+)
+
+// Forge lists the pull requests of the tracked repository.
+type Forge interface {
+	// ListPullRequests returns the open pull requests and those closed since closedSince.
+	ListPullRequests(ctx context.Context, closedSince time.Time) ([]PullRequest, error)
+}
+
+// Client reads pull requests through the GitHub REST API.
+type Client struct {
+	baseURL    string
+	repository string
+	token      string
+	http       *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.APIURL, "/"),
+		repository: cfg.Repository,
+		token:      cfg.Token,
+		http:       &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (c *Client) ListPullRequests(ctx context.Context, closedSince time.Time) ([]PullRequest, error) {
+	const op = "Client.ListPullRequests"
+
+	open, err := c.list(ctx, StateOpen, time.Time{})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	closed, err := c.list(ctx, StateClosed, closedSince)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return append(open, closed...), nil
+}
+
+// list pages through pull requests in the given state, most recently updated
+// first, and stops at the first one updated before since.
+func (c *Client) list(ctx context.Context, state string, since time.Time) ([]PullRequest, error) {
+	var result []PullRequest
+	for page := 1; page <= maxPages; page++ {
+		query := url.Values{
+			"state":     {state},
+			"sort":      {"updated"},
+			"direction": {"desc"},
+			"per_page":  {strconv.Itoa(perPage)},
+			"page":      {strconv.Itoa(page)},
+		}
+
+		var prs []PullRequest
+		if err := c.get(ctx, "/repos/"+c.repository+"/pulls?"+query.Encode(), &prs); err != nil {
+			return nil, err
+		}
+
+		for _, pr := range prs {
+			if pr.UpdatedAt.Before(since) {
+				return result, nil
+			}
+			pr.Merged = pr.MergedAt != nil
+			result = append(result, pr)
+		}
+
+		if len(prs) < perPage {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("GET %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,74 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListPullRequests(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mergedAt := now.Add(-time.Hour)
+
+	// 150 open pull requests need two pages; closed ones are newest first and
+	// the last is older than the lookback.
+	pages := map[string][][]PullRequest{StateOpen: {{}, {}}}
+	for i := range 150 {
+		page := i / perPage
+		pages[StateOpen][page] = append(pages[StateOpen][page], PullRequest{Number: i + 1, State: StateOpen, UpdatedAt: now})
+	}
+	pages[StateClosed] = [][]PullRequest{{
+		{Number: 200, State: StateClosed, MergedAt: &mergedAt, UpdatedAt: now.Add(-time.Hour)},
+		{Number: 201, State: StateClosed, UpdatedAt: now.Add(-2 * time.Hour)},
+		{Number: 202, State: StateClosed, UpdatedAt: now.Add(-48 * time.Hour)},
+	}}
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/repos/avito-tech/reviewer/pulls", req.URL.Path)
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		require.Equal(t, "application/vnd.github+json", req.Header.Get("Accept"))
+
+		state := req.URL.Query().Get("state")
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		requests = append(requests, state+"/"+strconv.Itoa(page))
+
+		prs := []PullRequest{}
+		if page <= len(pages[state]) {
+			prs = pages[state][page-1]
+		}
+		_ = json.NewEncoder(w).Encode(prs)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{APIURL: srv.URL + "/", Repository: "avito-tech/reviewer", Token: "token"})
+
+	prs, err := client.ListPullRequests(context.Background(), now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"open/1", "open/2", "closed/1"}, requests)
+	require.Len(t, prs, 152)
+
+	closed := prs[150:]
+	require.Equal(t, 200, closed[0].Number)
+	require.True(t, closed[0].Merged, "merged is derived from merged_at")
+	require.Equal(t, 201, closed[1].Number)
+	require.False(t, closed[1].Merged)
+}
+
+func TestClient_ListPullRequests_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{APIURL: srv.URL, Repository: "avito-tech/reviewer"})
+
+	_, err := client.ListPullRequests(context.Background(), time.Now())
+	require.ErrorContains(t, err, "unexpected status 403")
+}
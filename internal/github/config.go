@@ -0,0 +1,90 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// WebhookSecret verifies X-Hub-Signature-256. The endpoint rejects every
+	// request while it is empty.
+	WebhookSecret string `yaml:"webhook_secret" env:"GITHUB_WEBHOOK_SECRET" secret:"true"`
+	// Repository limits ingestion to one repository, e.g. "avito/reviewer".
+	// Pull request numbers are only unique within a repository.
+	Repository string `yaml:"repository" env:"GITHUB_REPOSITORY"`
+	// Logins maps forge logins to user ids, e.g. "octocat=u1,hubot=u2".
+	Logins string `yaml:"logins" env:"GITHUB_LOGINS"`
+
+	// APIURL is the REST API root, e.g. https://ghe.example.com/api/v3 for GitHub Enterprise.
+	APIURL string `yaml:"api_url" env:"GITHUB_API_URL"`
+	Token  string `yaml:"token" env:"GITHUB_TOKEN" secret:"true"`
+	// ReconcileInterval is how often pull requests are compared with the API;
+	// zero disables the periodic run. Reconciliation requires Repository.
+	ReconcileInterval time.Duration `yaml:"reconcile_interval" env:"GITHUB_RECONCILE_INTERVAL"`
+	// ReconcileLookback is how far back closed pull requests are checked.
+	ReconcileLookback time.Duration `yaml:"reconcile_lookback" env:"GITHUB_RECONCILE_LOOKBACK"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		APIURL:            "https://api.github.com",
+		ReconcileLookback: 72 * time.Hour,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, err := c.LoginMap(); err != nil {
+		errs = append(errs, err)
+	}
+
+	u, err := url.Parse(c.APIURL)
+	check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+		"github.api_url: must be an absolute http or https url, got %q", c.APIURL)
+	check(c.ReconcileInterval >= 0, "github.reconcile_interval: must not be negative")
+	check(c.ReconcileInterval == 0 || c.Repository != "", "github.reconcile_interval: requires github.repository")
+	check(c.ReconcileLookback > 0, "github.reconcile_lookback: must be positive")
+	check(c.Repository == "" || strings.Count(c.Repository, "/") == 1, "github.repository: must be owner/name, got %q", c.Repository)
+
+	return errors.Join(errs...)
+}
+
+// ReconcileEnabled reports whether pull requests can be reconciled with the API.
+func (c Config) ReconcileEnabled() bool {
+	return c.Repository != ""
+}
+
+// LoginMap parses Logins. Keys are lower-cased: logins are case-insensitive.
+func (c Config) LoginMap() (map[string]string, error) {
+	logins := make(map[string]string)
+	var errs []error
+	for _, pair := range strings.Split(c.Logins, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		login, userId, ok := strings.Cut(pair, "=")
+		login, userId = strings.ToLower(strings.TrimSpace(login)), strings.TrimSpace(userId)
+		if !ok || login == "" || userId == "" {
+			errs = append(errs, fmt.Errorf("github.logins: invalid pair %q, want login=user_id", pair))
+			continue
+		}
+		if _, ok := logins[login]; ok {
+			errs = append(errs, fmt.Errorf("github.logins: duplicate login %q", login))
+			continue
+		}
+
+		logins[login] = userId
+	}
+
+	return logins, errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_LoginMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		logins      string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name:     "empty",
+			expected: map[string]string{},
+		},
+		{
+			name:     "pairs are trimmed and logins lower-cased",
+			logins:   " Octocat=u1, hubot = u2 ,",
+			expected: map[string]string{"octocat": "u1", "hubot": "u2"},
+		},
+		{
+			name:        "missing user id",
+			logins:      "octocat=",
+			expectedErr: `invalid pair "octocat="`,
+		},
+		{
+			name:        "duplicate login",
+			logins:      "octocat=u1,OctoCat=u2",
+			expectedErr: `duplicate login "octocat"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins, err := Config{Logins: tt.logins}.LoginMap()
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, logins)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"action":"opened"}`)
+
+	require.True(t, Verify("secret", body, Sign("secret", body)))
+	require.False(t, Verify("other", body, Sign("secret", body)))
+	require.False(t, Verify("", body, Sign("", body)), "an empty secret accepts nothing")
+}
@@ -0,0 +1,134 @@
+// Package github turns GitHub pull_request webhooks into calls of the
+// pull request usecases.
+package github
+
+import (
+	"avito-internship/internal/usecase"
+	"avito-internship/pkg/e"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Result string
+
+const (
+	ResultCreated   Result = "created"
+	ResultMerged    Result = "merged"
+	ResultUnchanged Result = "unchanged"
+	ResultIgnored   Result = "ignored"
+)
+
+type Outcome struct {
+	PullRequestId string
+	Result        Result
+	// Reason explains an ignored event.
+	Reason string
+}
+
+// Ingester applies pull_request events. Every action is idempotent, so
+// redelivered webhooks leave the state as it is.
+type Ingester struct {
+	prUC       usecase.PullRequestUC
+	repository string
+	logins     map[string]string
+}
+
+func NewIngester(prUC usecase.PullRequestUC, cfg Config) (*Ingester, error) {
+	const op = "github.NewIngester"
+
+	logins, err := cfg.LoginMap()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &Ingester{
+		prUC:       prUC,
+		repository: cfg.Repository,
+		logins:     logins,
+	}, nil
+}
+
+// PullRequestId is the id of a GitHub pull request in the service. The gh-
+// prefix is reserved, so it never matches a pull request created by hand.
+func PullRequestId(number int) string {
+	return fmt.Sprintf("gh-%d", number)
+}
+
+func (i *Ingester) HandlePullRequest(ctx context.Context, event PullRequestEvent) (Outcome, error) {
+	const op = "Ingester.HandlePullRequest"
+
+	if i.repository != "" && !strings.EqualFold(event.Repository.FullName, i.repository) {
+		return ignored("repository %q is not tracked", event.Repository.FullName), nil
+	}
+
+	pr := event.PullRequest
+	var outcome Outcome
+	var err error
+	switch event.Action {
+	case ActionOpened, ActionReopened, ActionReadyForReview:
+		if pr.Draft {
+			return ignored("draft pull request"), nil
+		}
+		outcome, err = i.ensureCreated(ctx, pr)
+	case ActionClosed:
+		if !pr.Merged {
+			return ignored("closed without merge"), nil
+		}
+		outcome, err = i.merge(ctx, pr)
+	default:
+		return ignored("action %q is not handled", event.Action), nil
+	}
+	if err != nil {
+		return Outcome{}, e.Wrap(op, err)
+	}
+
+	return outcome, nil
+}
+
+func (i *Ingester) ensureCreated(ctx context.Context, pr PullRequest) (Outcome, error) {
+	id := PullRequestId(pr.Number)
+
+	authorId, ok := i.logins[strings.ToLower(pr.User.Login)]
+	if !ok {
+		return Outcome{}, e.Wrap(pr.User.Login, e.ErrUnknownLogin)
+	}
+
+	_, err := i.prUC.PullRequestCreate(ctx, usecase.CreatePullRequestReq{
+		Id:       id,
+		Name:     pr.Title,
+		AuthorId: authorId,
+	})
+	if errors.Is(err, e.ErrPRIsExists) {
+		return Outcome{PullRequestId: id, Result: ResultUnchanged}, nil
+	}
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	return Outcome{PullRequestId: id, Result: ResultCreated}, nil
+}
+
+// merge creates pull requests opened before the webhook was set up, so that
+// their merge is not lost.
+func (i *Ingester) merge(ctx context.Context, pr PullRequest) (Outcome, error) {
+	id := PullRequestId(pr.Number)
+
+	_, err := i.prUC.PullRequestMerge(ctx, usecase.PullRequestMergeReq{Id: id})
+	if errors.Is(err, e.ErrPRNotFound) {
+		if _, err := i.ensureCreated(ctx, pr); err != nil {
+			return Outcome{}, err
+		}
+		_, err = i.prUC.PullRequestMerge(ctx, usecase.PullRequestMergeReq{Id: id})
+	}
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	return Outcome{PullRequestId: id, Result: ResultMerged}, nil
+}
+
+func ignored(format string, args ...any) Outcome {
+	return Outcome{Result: ResultIgnored, Reason: fmt.Sprintf(format, args...)}
+}
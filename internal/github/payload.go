@@ -0,0 +1,45 @@
+package github
+
+import "time"
+
+const (
+	ActionOpened         = "opened"
+	ActionClosed         = "closed"
+	ActionReopened       = "reopened"
+	ActionReadyForReview = "ready_for_review"
+)
+
+// PullRequestEvent is the part of the GitHub pull_request webhook payload the
+// service reads.
+type PullRequestEvent struct {
+	Action      string      `json:"action"`
+	Number      int         `json:"number"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  Repository  `json:"repository"`
+}
+
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+// PullRequest is shared by webhook payloads and the REST API. The API does not
+// return Merged in lists, only MergedAt.
+type PullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	Draft     bool       `json:"draft"`
+	Merged    bool       `json:"merged"`
+	MergedAt  *time.Time `json:"merged_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	User      Account    `json:"user"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+type Account struct {
+	Login string `json:"login"`
+}
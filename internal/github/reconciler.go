@@ -0,0 +1,179 @@
+package github
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+type DriftKind string
+
+const (
+	// DriftMissing is an open pull request that is not in the service; it is created.
+	DriftMissing DriftKind = "missing"
+	// DriftMissingMerged is a merged pull request that is not in the service; it is created and merged.
+	DriftMissingMerged DriftKind = "missing_merged"
+	// DriftNotMerged is merged upstream but open here; it is merged.
+	DriftNotMerged DriftKind = "not_merged"
+	// DriftClosedUpstream is closed without merge upstream but open here. The
+	// service has no closed status, so it is only reported.
+	DriftClosedUpstream DriftKind = "closed_upstream"
+	// DriftReopenedUpstream is open upstream but merged here; merges are final, so it is only reported.
+	DriftReopenedUpstream DriftKind = "reopened_upstream"
+)
+
+type Drift struct {
+	PullRequestId string
+	Number        int
+	Kind          DriftKind
+	Fixed         bool
+	Error         string
+}
+
+type Report struct {
+	StartedAt time.Time
+	// Checked is the number of pull requests read from the API.
+	Checked int
+	Drift   []Drift
+}
+
+// Reconciler catches up on lost webhooks by comparing the pull requests of the
+// API with the service and applying the difference through the Ingester.
+type Reconciler struct {
+	forge      Forge
+	ingester   *Ingester
+	prRepo     r.PullRequestRepository
+	repository string
+	interval   time.Duration
+	lookback   time.Duration
+	logger     logger.Logger
+
+	mu sync.Mutex
+}
+
+func NewReconciler(forge Forge, ingester *Ingester, prRepo r.PullRequestRepository, cfg Config, logger logger.Logger) *Reconciler {
+	return &Reconciler{
+		forge:      forge,
+		ingester:   ingester,
+		prRepo:     prRepo,
+		repository: cfg.Repository,
+		interval:   cfg.ReconcileInterval,
+		lookback:   cfg.ReconcileLookback,
+		logger:     logger,
+	}
+}
+
+// Run reconciles right away and then every interval until ctx is done.
+func (rc *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rc.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rc.Reconcile(ctx); err != nil && ctx.Err() == nil {
+			rc.logger.Errorf(err, "github reconciliation failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile runs one pass. Failures to fix a single pull request are recorded
+// in its Drift and do not stop the pass.
+func (rc *Reconciler) Reconcile(ctx context.Context) (Report, error) {
+	const op = "Reconciler.Reconcile"
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	report := Report{StartedAt: time.Now()}
+
+	listed, err := rc.forge.ListPullRequests(ctx, report.StartedAt.Add(-rc.lookback))
+	if err != nil {
+		return Report{}, e.Wrap(op, err)
+	}
+
+	// A pull request closed between the two list requests shows up twice; the
+	// closed copy is newer.
+	byNumber := make(map[int]PullRequest, len(listed))
+	for _, pr := range listed {
+		byNumber[pr.Number] = pr
+	}
+
+	upstream := make([]PullRequest, 0, len(byNumber))
+	ids := make([]string, 0, len(byNumber))
+	for _, pr := range byNumber {
+		upstream = append(upstream, pr)
+		ids = append(ids, PullRequestId(pr.Number))
+	}
+	slices.SortFunc(upstream, func(a, b PullRequest) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+	report.Checked = len(upstream)
+
+	local, err := rc.prRepo.GetStatuses(ctx, ids)
+	if err != nil {
+		return Report{}, e.Wrap(op, err)
+	}
+
+	for _, pr := range upstream {
+		if err := ctx.Err(); err != nil {
+			return Report{}, e.Wrap(op, err)
+		}
+
+		status, exists := local[PullRequestId(pr.Number)]
+		switch {
+		case pr.State == StateOpen && !pr.Draft && !exists:
+			report.Drift = append(report.Drift, rc.fix(ctx, pr, DriftMissing, ActionOpened))
+		case pr.State == StateOpen && exists && status == domain.MERGED:
+			report.Drift = append(report.Drift, drift(pr, DriftReopenedUpstream))
+		case pr.Merged && !exists:
+			report.Drift = append(report.Drift, rc.fix(ctx, pr, DriftMissingMerged, ActionClosed))
+		case pr.Merged && status != domain.MERGED:
+			report.Drift = append(report.Drift, rc.fix(ctx, pr, DriftNotMerged, ActionClosed))
+		case pr.State == StateClosed && !pr.Merged && exists && status == domain.OPEN:
+			report.Drift = append(report.Drift, drift(pr, DriftClosedUpstream))
+		}
+	}
+
+	for _, d := range report.Drift {
+		rc.logger.WarnfContext(ctx, "github drift pull_request=%s kind=%s fixed=%t error=%q", d.PullRequestId, d.Kind, d.Fixed, d.Error)
+	}
+	rc.logger.InfofContext(ctx, "github reconciliation checked %d pull requests, found %d drifted", report.Checked, len(report.Drift))
+
+	return report, nil
+}
+
+func (rc *Reconciler) fix(ctx context.Context, pr PullRequest, kind DriftKind, action string) Drift {
+	d := drift(pr, kind)
+
+	outcome, err := rc.ingester.HandlePullRequest(ctx, PullRequestEvent{
+		Action:      action,
+		Number:      pr.Number,
+		PullRequest: pr,
+		Repository:  Repository{FullName: rc.repository},
+	})
+	switch {
+	case err != nil:
+		d.Error = err.Error()
+	case outcome.Result == ResultIgnored:
+		d.Error = outcome.Reason
+	default:
+		d.Fixed = true
+	}
+
+	return d
+}
+
+func drift(pr PullRequest, kind DriftKind) Drift {
+	return Drift{PullRequestId: PullRequestId(pr.Number), Number: pr.Number, Kind: kind}
+}
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderDelivery  = "X-GitHub-Delivery"
+	HeaderSignature = "X-Hub-Signature-256"
+
+	EventPullRequest = "pull_request"
+	EventPing        = "ping"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the X-Hub-Signature-256 value GitHub sends for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body. An empty secret matches nothing.
+func Verify(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, body)))
+}
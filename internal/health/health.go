@@ -0,0 +1,148 @@
+// Package health tracks liveness and readiness of the service for orchestrators.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDraining Status = "draining"
+)
+
+type Config struct {
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" env:"HEALTH_READINESS_TIMEOUT"`
+}
+
+// Check reports a component as down by returning an error.
+type Check func(ctx context.Context) error
+
+type Component struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     Status               `json:"status"`
+	Components map[string]Component `json:"components"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type Monitor struct {
+	timeout  time.Duration
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewMonitor(cfg Config) *Monitor {
+	return &Monitor{
+		timeout: cfg.ReadinessTimeout,
+	}
+}
+
+// Register adds a component to the readiness report. It must be called before serving.
+func (m *Monitor) Register(name string, check Check) {
+	m.checks = append(m.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining makes readiness fail so that no new traffic is routed to the instance.
+func (m *Monitor) SetDraining() {
+	m.draining.Store(true)
+}
+
+// Liveness reports that the process is up and able to serve requests.
+func (m *Monitor) Liveness() Report {
+	return Report{
+		Status: StatusUp,
+		Components: map[string]Component{
+			"process": {Status: StatusUp},
+		},
+	}
+}
+
+// Readiness runs all checks concurrently. A check that does not finish within
+// the readiness timeout is reported as down.
+func (m *Monitor) Readiness(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]Component, len(m.checks)+1),
+	}
+
+	server := Component{Status: StatusUp}
+	if m.draining.Load() {
+		server.Status = StatusDraining
+		report.Status = StatusDraining
+	}
+	report.Components["server"] = server
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range m.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			component := Component{Status: StatusUp}
+			if err := run(ctx, c.check); err != nil {
+				component = Component{Status: StatusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[c.name] = component
+			if component.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// SchemaCheck fails when the applied schema version differs from expected or is dirty.
+func SchemaCheck(version func(ctx context.Context) (uint, bool, error), expected uint) Check {
+	return func(ctx context.Context) error {
+		current, dirty, err := version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("schema version %d is dirty", current)
+		}
+		if current != expected {
+			return fmt.Errorf("schema version %d, expected %d", current, expected)
+		}
+		return nil
+	}
+}
+
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
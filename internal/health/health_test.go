@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitor_Readiness(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-make(chan struct{})
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		draining   bool
+		wantStatus Status
+		want       map[string]Component
+	}{
+		{
+			name:       "all up",
+			checks:     map[string]Check{"database": ok},
+			wantStatus: StatusUp,
+			want: map[string]Component{
+				"server":   {Status: StatusUp},
+				"database": {Status: StatusUp},
+			},
+		},
+		{
+			name:       "failing check",
+			checks:     map[string]Check{"database": failing, "schema": ok},
+			wantStatus: StatusDown,
+			want: map[string]Component{
+				"server":   {Status: StatusUp},
+				"database": {Status: StatusDown, Error: "connection refused"},
+				"schema":   {Status: StatusUp},
+			},
+		},
+		{
+			name:       "check exceeds deadline",
+			checks:     map[string]Check{"database": hanging},
+			wantStatus: StatusDown,
+			want: map[string]Component{
+				"server":   {Status: StatusUp},
+				"database": {Status: StatusDown, Error: context.DeadlineExceeded.Error()},
+			},
+		},
+		{
+			name:       "draining",
+			checks:     map[string]Check{"database": ok},
+			draining:   true,
+			wantStatus: StatusDraining,
+			want: map[string]Component{
+				"server":   {Status: StatusDraining},
+				"database": {Status: StatusUp},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMonitor(Config{ReadinessTimeout: 50 * time.Millisecond})
+			for name, check := range tt.checks {
+				m.Register(name, check)
+			}
+			if tt.draining {
+				m.SetDraining()
+			}
+
+			report := m.Readiness(context.Background())
+
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.want, report.Components)
+		})
+	}
+}
+
+func TestSchemaCheck(t *testing.T) {
+	version := func(v uint, dirty bool) func(context.Context) (uint, bool, error) {
+		return func(context.Context) (uint, bool, error) { return v, dirty, nil }
+	}
+
+	assert.NoError(t, SchemaCheck(version(4, false), 4)(context.Background()))
+	assert.EqualError(t, SchemaCheck(version(3, false), 4)(context.Background()), "schema version 3, expected 4")
+	assert.EqualError(t, SchemaCheck(version(4, true), 4)(context.Background()), "schema version 4 is dirty")
+}
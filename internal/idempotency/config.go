@@ -0,0 +1,40 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	// TTL is how long a response is kept for replay.
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+	// LockTimeout is how long a key stays locked by a request that never
+	// finished, e.g. because the instance crashed.
+	LockTimeout     time.Duration `yaml:"lock_timeout" env:"IDEMPOTENCY_LOCK_TIMEOUT"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TTL:             24 * time.Hour,
+		LockTimeout:     time.Minute,
+		CleanupInterval: time.Hour,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.TTL > 0, "idempotency.ttl: must be positive")
+	check(c.LockTimeout > 0, "idempotency.lock_timeout: must be positive")
+	check(c.LockTimeout < c.TTL, "idempotency.lock_timeout: must be less than ttl")
+	check(c.CleanupInterval > 0, "idempotency.cleanup_interval: must be positive")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+// Package idempotency stores the responses to requests sent with an
+// Idempotency-Key header so that retries get the original response.
+package idempotency
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+type Service struct {
+	repo   r.IdempotencyRepository
+	cfg    Config
+	logger logger.Logger
+	now    func() time.Time
+}
+
+func NewService(repo r.IdempotencyRepository, cfg Config, logger logger.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// RequestHash fingerprints a request; a key may only be reused with the same one.
+func RequestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin locks key for the request. It returns the stored response when the
+// request has already been answered, and nil when the caller should handle it
+// and then call Complete or Release.
+func (s *Service) Begin(ctx context.Context, key, requestHash string) (*Response, error) {
+	const op = "Service.Begin"
+
+	now := s.now()
+	stored, reserved, err := s.repo.Reserve(ctx, domain.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.cfg.TTL),
+	}, now.Add(-s.cfg.LockTimeout))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	switch {
+	case reserved:
+		return nil, nil
+	case stored.RequestHash != requestHash:
+		return nil, e.Wrap(op, e.ErrIdempotencyKeyMismatch)
+	case !stored.Completed():
+		return nil, e.Wrap(op, e.ErrIdempotencyKeyInUse)
+	default:
+		return &Response{StatusCode: stored.StatusCode, Body: stored.Body}, nil
+	}
+}
+
+func (s *Service) Complete(ctx context.Context, key string, res Response) error {
+	const op = "Service.Complete"
+
+	if err := s.repo.Complete(ctx, key, res.StatusCode, res.Body); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Release unlocks key without storing a response, so a retry runs the request again.
+func (s *Service) Release(ctx context.Context, key string) error {
+	const op = "Service.Release"
+
+	if err := s.repo.Release(ctx, key); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Run deletes expired keys every CleanupInterval until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := s.repo.DeleteExpired(ctx, s.now())
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Errorf(err, "idempotency cleanup failed")
+			}
+			continue
+		}
+		if n > 0 {
+			s.logger.Debugf("deleted %d expired idempotency keys", n)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/repository/mocks"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_Begin(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	hash := RequestHash("POST", "/pullRequest/create", []byte(`{"pull_request_id":"pr-1001"}`))
+
+	tests := []struct {
+		name        string
+		stored      domain.IdempotencyKey
+		reserved    bool
+		expected    *Response
+		expectedErr error
+	}{
+		{
+			name:     "new key",
+			reserved: true,
+		},
+		{
+			name:     "completed request is replayed",
+			stored:   domain.IdempotencyKey{Key: "k", RequestHash: hash, StatusCode: 201, Body: []byte(`{}`)},
+			expected: &Response{StatusCode: 201, Body: []byte(`{}`)},
+		},
+		{
+			name:        "different request",
+			stored:      domain.IdempotencyKey{Key: "k", RequestHash: "other", StatusCode: 201},
+			expectedErr: e.ErrIdempotencyKeyMismatch,
+		},
+		{
+			name:        "request in progress",
+			stored:      domain.IdempotencyKey{Key: "k", RequestHash: hash},
+			expectedErr: e.ErrIdempotencyKeyInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockIdempotencyRepository(ctrl)
+
+			cfg := DefaultConfig()
+			repo.EXPECT().Reserve(gomock.Any(), domain.IdempotencyKey{
+				Key:         "k",
+				RequestHash: hash,
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(cfg.TTL),
+			}, now.Add(-cfg.LockTimeout)).Return(tt.stored, tt.reserved, nil)
+
+			service := NewService(repo, cfg, logger.NewSlogLogger())
+			service.now = func() time.Time { return now }
+
+			res, err := service.Begin(context.Background(), "k", hash)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestRequestHash(t *testing.T) {
+	body := []byte(`{"pull_request_id":"pr-1001"}`)
+
+	require.Equal(t, RequestHash("POST", "/pullRequest/create", body), RequestHash("POST", "/pullRequest/create", body))
+	require.NotEqual(t, RequestHash("POST", "/pullRequest/create", body), RequestHash("POST", "/pullRequest/merge", body))
+	require.NotEqual(t, RequestHash("POST", "/pullRequest/create", body), RequestHash("POST", "/pullRequest/create", []byte(`{}`)))
+}
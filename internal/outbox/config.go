@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+type Config struct {
+	// Enabled starts the dispatcher. Events are written to the outbox either way.
+	Enabled         bool          `yaml:"enabled" env:"OUTBOX_ENABLED"`
+	PollInterval    time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize       int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" env:"OUTBOX_RETRY_MAX_BACKOFF"`
+	// LeaseTimeout is how long a claimed batch is hidden from other dispatchers.
+	// It must cover the delivery of a whole batch.
+	LeaseTimeout time.Duration `yaml:"lease_timeout" env:"OUTBOX_LEASE_TIMEOUT"`
+	// Sinks is a comma-separated list of sinks, e.g. "log,webhook".
+	Sinks string `yaml:"sinks" env:"OUTBOX_SINKS"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled:         true,
+		PollInterval:    time.Second,
+		BatchSize:       100,
+		MaxAttempts:     10,
+		RetryBackoff:    time.Second,
+		RetryMaxBackoff: 10 * time.Minute,
+		LeaseTimeout:    time.Minute,
+		Sinks:           SinkLog + "," + SinkWebhook,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.PollInterval > 0, "outbox.poll_interval: must be positive")
+	check(c.BatchSize > 0, "outbox.batch_size: must be positive, got %d", c.BatchSize)
+	check(c.MaxAttempts > 0, "outbox.max_attempts: must be positive, got %d", c.MaxAttempts)
+	check(c.RetryBackoff > 0, "outbox.retry_backoff: must be positive")
+	check(c.RetryMaxBackoff >= c.RetryBackoff, "outbox.retry_max_backoff: must not be less than retry_backoff")
+	check(c.LeaseTimeout > 0, "outbox.lease_timeout: must be positive")
+	for _, name := range c.SinkNames() {
+		check(slices.Contains(knownSinks, name), "outbox.sinks: unknown sink %q, available: %s", name, strings.Join(knownSinks, ", "))
+	}
+
+	return errors.Join(errs...)
+}
+
+var knownSinks = []string{SinkLog, SinkWebhook}
+
+// SinkNames returns the sinks listed in Sinks.
+func (c Config) SinkNames() []string {
+	var names []string
+	for _, name := range strings.Split(c.Sinks, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,107 @@
+// Package outbox delivers domain events that usecases write to the outbox table
+// in the same transaction as the change they describe.
+package outbox
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/retry"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Dispatcher struct {
+	repo    r.OutboxRepository
+	sinks   []Sink
+	cfg     Config
+	backoff retry.Backoff
+	logger  logger.Logger
+}
+
+func NewDispatcher(repo r.OutboxRepository, cfg Config, logger logger.Logger, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		repo:    repo,
+		sinks:   sinks,
+		cfg:     cfg,
+		backoff: retry.Backoff{Initial: cfg.RetryBackoff, Max: cfg.RetryMaxBackoff},
+		logger:  logger,
+	}
+}
+
+// Run dispatches events until ctx is done. Full batches are followed by the next
+// one right away; otherwise the dispatcher waits for PollInterval.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.Dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.logger.Errorf(err, "outbox dispatch failed")
+			}
+			if err != nil || n < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch claims one batch of due events and delivers it to every sink. It
+// returns the number of claimed events.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	const op = "Dispatcher.Dispatch"
+
+	events, err := d.repo.Claim(ctx, d.cfg.BatchSize, time.Now().Add(d.cfg.LeaseTimeout))
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	for _, event := range events {
+		// Events left after shutdown are claimed again when their lease expires.
+		if err := ctx.Err(); err != nil {
+			return len(events), e.Wrap(op, err)
+		}
+
+		if err := d.deliver(ctx, event); err != nil {
+			return len(events), e.Wrap(op, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	deliveryErr := errors.Join(errs...)
+	if deliveryErr == nil {
+		return d.repo.MarkDelivered(ctx, event.Id)
+	}
+
+	if event.Attempts >= d.cfg.MaxAttempts {
+		d.logger.ErrorfContext(ctx, deliveryErr, "outbox event id=%d type=%s failed after %d attempts, giving up",
+			event.Id, event.Type, event.Attempts)
+		return d.repo.MarkFailed(ctx, event.Id, deliveryErr.Error())
+	}
+
+	delay := d.backoff.Delay(event.Attempts)
+	d.logger.WarnfContext(ctx, "outbox event id=%d type=%s attempt %d failed, retrying in %s: %v",
+		event.Id, event.Type, event.Attempts, delay, deliveryErr)
+	return d.repo.Reschedule(ctx, event.Id, time.Now().Add(delay), deliveryErr.Error())
+}
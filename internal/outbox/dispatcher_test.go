@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/repository/mocks"
+	"avito-internship/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type stubSink struct {
+	name string
+	err  error
+}
+
+func (s stubSink) Name() string {
+	return s.name
+}
+
+func (s stubSink) Deliver(context.Context, domain.Event) error {
+	return s.err
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 3
+	cfg.RetryBackoff = time.Minute
+	cfg.RetryMaxBackoff = time.Hour
+
+	errDown := errors.New("down")
+
+	tests := []struct {
+		name     string
+		attempts int
+		sinks    []Sink
+		setup    func(repo *mocks.MockOutboxRepository)
+	}{
+		{
+			name:     "delivered to every sink",
+			attempts: 1,
+			sinks:    []Sink{stubSink{name: "a"}, stubSink{name: "b"}},
+			setup: func(repo *mocks.MockOutboxRepository) {
+				repo.EXPECT().MarkDelivered(gomock.Any(), int64(7)).Return(nil)
+			},
+		},
+		{
+			name:     "failed sink reschedules with backoff",
+			attempts: 2,
+			sinks:    []Sink{stubSink{name: "a"}, stubSink{name: "b", err: errDown}},
+			setup: func(repo *mocks.MockOutboxRepository) {
+				repo.EXPECT().Reschedule(gomock.Any(), int64(7), gomock.Any(), "b: down").
+					DoAndReturn(func(_ context.Context, _ int64, next time.Time, _ string) error {
+						require.WithinDuration(t, time.Now().Add(2*time.Minute), next, 5*time.Second)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "gives up after max attempts",
+			attempts: 3,
+			sinks:    []Sink{stubSink{name: "a", err: errDown}},
+			setup: func(repo *mocks.MockOutboxRepository) {
+				repo.EXPECT().MarkFailed(gomock.Any(), int64(7), "a: down").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockOutboxRepository(ctrl)
+
+			event := domain.Event{Id: 7, Type: domain.EventPRCreated, Payload: []byte(`{}`), Attempts: tt.attempts}
+			repo.EXPECT().Claim(gomock.Any(), cfg.BatchSize, gomock.Any()).Return([]domain.Event{event}, nil)
+			tt.setup(repo)
+
+			dispatcher := NewDispatcher(repo, cfg, logger.NewSlogLogger(), tt.sinks...)
+
+			n, err := dispatcher.Dispatch(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/logger"
+	"context"
+)
+
+// Sink receives events from the dispatcher. Delivery is at least once: when any
+// sink fails, the event is retried for every sink, so Deliver must tolerate
+// repeats, e.g. by deduplicating on the event id.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event domain.Event) error
+}
+
+// LogSink writes every event to the log at debug level.
+type LogSink struct {
+	logger logger.Logger
+}
+
+func NewLogSink(logger logger.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return SinkLog
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event domain.Event) error {
+	s.logger.DebugfContext(ctx, "event id=%d type=%s payload=%s", event.Id, event.Type, event.Payload)
+	return nil
+}
@@ -1,6 +1,9 @@
 package repository
 
-import "avito-internship/internal/domain"
+import (
+	"avito-internship/internal/domain"
+	"time"
+)
 
 type SetMergedStatusDTO struct {
 	Pr           domain.PullRequest
@@ -16,12 +19,16 @@ type GetOpenPRsByReviewerIDsDTO struct {
 	Pr           domain.PullRequest
 	ReviewersIds []string
 	StatusName   string
+	// ReviewTeamId is the PR team, or the author's primary team when it has none.
+	ReviewTeamId int
 }
 
 type GetByPrIdWithReviewersIdsDTO struct {
 	Pr           domain.PullRequest
 	ReviewersIds []string
 	StatusName   domain.PRStatus
+	// ReviewTeamId is the PR team, or the author's primary team when it has none.
+	ReviewTeamId int
 }
 
 type PrWithStatusName struct {
@@ -69,3 +76,31 @@ func NewGetByPrIdWithReviewersIdsDTO(pr domain.PullRequest, reviewersIds []strin
 		StatusName:   statusName,
 	}
 }
+
+// UserFilter selects users to list. Zero fields match every user.
+type UserFilter struct {
+	// Query matches a prefix of the user id or a part of the name.
+	Query    string
+	TeamId   int
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
+
+type UserStatsDTO struct {
+	OpenReviews int
+	AuthoredPrs int
+}
+
+type ClaimedDeliveryDTO struct {
+	Delivery domain.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type FailedAttemptDTO struct {
+	DeliveryId    int64
+	ResponseCode  *int
+	LastError     string
+	NextAttemptAt *time.Time
+}
@@ -14,6 +14,7 @@ import (
 	repository "avito-internship/internal/repository"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddMembership mocks base method.
+func (m *MockUserRepository) AddMembership(ctx context.Context, teamId int, userId string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMembership", ctx, teamId, userId)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMembership indicates an expected call of AddMembership.
+func (mr *MockUserRepositoryMockRecorder) AddMembership(ctx, teamId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembership", reflect.TypeOf((*MockUserRepository)(nil).AddMembership), ctx, teamId, userId)
+}
+
 // AddUsersToTeam mocks base method.
 func (m *MockUserRepository) AddUsersToTeam(ctx context.Context, teamId int, users []domain.User) ([]domain.User, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +73,21 @@ func (mr *MockUserRepositoryMockRecorder) AddUsersToTeam(ctx, teamId, users any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUsersToTeam", reflect.TypeOf((*MockUserRepository)(nil).AddUsersToTeam), ctx, teamId, users)
 }
 
+// Anonymize mocks base method.
+func (m *MockUserRepository) Anonymize(ctx context.Context, userId, tombstoneId, pseudonym string) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, userId, tombstoneId, pseudonym)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserRepositoryMockRecorder) Anonymize(ctx, userId, tombstoneId, pseudonym any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserRepository)(nil).Anonymize), ctx, userId, tombstoneId, pseudonym)
+}
+
 // DeactivateUsers mocks base method.
 func (m *MockUserRepository) DeactivateUsers(ctx context.Context, ids []string) ([]domain.User, error) {
 	m.ctrl.T.Helper()
@@ -88,33 +119,124 @@ func (mr *MockUserRepositoryMockRecorder) GetById(ctx, userId any) *gomock.Call
 }
 
 // GetReassignCandidates mocks base method.
-func (m *MockUserRepository) GetReassignCandidates(ctx context.Context, authorId string, excludeIds []string, maxCandidates int) ([]domain.User, error) {
+func (m *MockUserRepository) GetReassignCandidates(ctx context.Context, teamId int, excludeIds []string, maxCandidates int) ([]domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReassignCandidates", ctx, authorId, excludeIds, maxCandidates)
+	ret := m.ctrl.Call(m, "GetReassignCandidates", ctx, teamId, excludeIds, maxCandidates)
 	ret0, _ := ret[0].([]domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetReassignCandidates indicates an expected call of GetReassignCandidates.
-func (mr *MockUserRepositoryMockRecorder) GetReassignCandidates(ctx, authorId, excludeIds, maxCandidates any) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) GetReassignCandidates(ctx, teamId, excludeIds, maxCandidates any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReassignCandidates", reflect.TypeOf((*MockUserRepository)(nil).GetReassignCandidates), ctx, authorId, excludeIds, maxCandidates)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReassignCandidates", reflect.TypeOf((*MockUserRepository)(nil).GetReassignCandidates), ctx, teamId, excludeIds, maxCandidates)
 }
 
 // GetReviewCandidates mocks base method.
-func (m *MockUserRepository) GetReviewCandidates(ctx context.Context, authorId string, maxCandidates int) ([]domain.User, error) {
+func (m *MockUserRepository) GetReviewCandidates(ctx context.Context, teamId int, authorId string, maxCandidates int) ([]domain.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReviewCandidates", ctx, authorId, maxCandidates)
+	ret := m.ctrl.Call(m, "GetReviewCandidates", ctx, teamId, authorId, maxCandidates)
 	ret0, _ := ret[0].([]domain.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetReviewCandidates indicates an expected call of GetReviewCandidates.
-func (mr *MockUserRepositoryMockRecorder) GetReviewCandidates(ctx, authorId, maxCandidates any) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) GetReviewCandidates(ctx, teamId, authorId, maxCandidates any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewCandidates", reflect.TypeOf((*MockUserRepository)(nil).GetReviewCandidates), ctx, authorId, maxCandidates)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewCandidates", reflect.TypeOf((*MockUserRepository)(nil).GetReviewCandidates), ctx, teamId, authorId, maxCandidates)
+}
+
+// GetStats mocks base method.
+func (m *MockUserRepository) GetStats(ctx context.Context, userId string, openStatusId int) (repository.UserStatsDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, userId, openStatusId)
+	ret0, _ := ret[0].(repository.UserStatsDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockUserRepositoryMockRecorder) GetStats(ctx, userId, openStatusId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockUserRepository)(nil).GetStats), ctx, userId, openStatusId)
+}
+
+// IsTombstone mocks base method.
+func (m *MockUserRepository) IsTombstone(ctx context.Context, userId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTombstone", ctx, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTombstone indicates an expected call of IsTombstone.
+func (mr *MockUserRepositoryMockRecorder) IsTombstone(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTombstone", reflect.TypeOf((*MockUserRepository)(nil).IsTombstone), ctx, userId)
+}
+
+// List mocks base method.
+func (m *MockUserRepository) List(ctx context.Context, filter repository.UserFilter) ([]domain.User, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, filter)
+}
+
+// MoveTeamMembers mocks base method.
+func (m *MockUserRepository) MoveTeamMembers(ctx context.Context, fromTeamId, toTeamId int) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTeamMembers", ctx, fromTeamId, toTeamId)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTeamMembers indicates an expected call of MoveTeamMembers.
+func (mr *MockUserRepositoryMockRecorder) MoveTeamMembers(ctx, fromTeamId, toTeamId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTeamMembers", reflect.TypeOf((*MockUserRepository)(nil).MoveTeamMembers), ctx, fromTeamId, toTeamId)
+}
+
+// MoveToTeam mocks base method.
+func (m *MockUserRepository) MoveToTeam(ctx context.Context, userId string, teamId int) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToTeam", ctx, userId, teamId)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToTeam indicates an expected call of MoveToTeam.
+func (mr *MockUserRepositoryMockRecorder) MoveToTeam(ctx, userId, teamId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToTeam", reflect.TypeOf((*MockUserRepository)(nil).MoveToTeam), ctx, userId, teamId)
+}
+
+// RemoveUsersFromTeam mocks base method.
+func (m *MockUserRepository) RemoveUsersFromTeam(ctx context.Context, teamId int, ids []string) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUsersFromTeam", ctx, teamId, ids)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveUsersFromTeam indicates an expected call of RemoveUsersFromTeam.
+func (mr *MockUserRepositoryMockRecorder) RemoveUsersFromTeam(ctx, teamId, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUsersFromTeam", reflect.TypeOf((*MockUserRepository)(nil).RemoveUsersFromTeam), ctx, teamId, ids)
 }
 
 // UpdateIsActive mocks base method.
@@ -171,6 +293,65 @@ func (mr *MockTeamRepositoryMockRecorder) Create(ctx, team any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTeamRepository)(nil).Create), ctx, team)
 }
 
+// Delete mocks base method.
+func (m *MockTeamRepository) Delete(ctx context.Context, teamId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, teamId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTeamRepositoryMockRecorder) Delete(ctx, teamId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTeamRepository)(nil).Delete), ctx, teamId)
+}
+
+// GetById mocks base method.
+func (m *MockTeamRepository) GetById(ctx context.Context, teamId int) (domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, teamId)
+	ret0, _ := ret[0].(domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTeamRepositoryMockRecorder) GetById(ctx, teamId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTeamRepository)(nil).GetById), ctx, teamId)
+}
+
+// GetByName mocks base method.
+func (m *MockTeamRepository) GetByName(ctx context.Context, teamName string) (domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", ctx, teamName)
+	ret0, _ := ret[0].(domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockTeamRepositoryMockRecorder) GetByName(ctx, teamName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockTeamRepository)(nil).GetByName), ctx, teamName)
+}
+
+// GetChildren mocks base method.
+func (m *MockTeamRepository) GetChildren(ctx context.Context, parentId int) ([]domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", ctx, parentId)
+	ret0, _ := ret[0].([]domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockTeamRepositoryMockRecorder) GetChildren(ctx, parentId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockTeamRepository)(nil).GetChildren), ctx, parentId)
+}
+
 // GetMembersByTeamNameWithUsers mocks base method.
 func (m *MockTeamRepository) GetMembersByTeamNameWithUsers(ctx context.Context, teamName string) ([]domain.User, error) {
 	m.ctrl.T.Helper()
@@ -201,6 +382,51 @@ func (mr *MockTeamRepositoryMockRecorder) GetTeamByUserId(ctx, userId any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamByUserId", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamByUserId), ctx, userId)
 }
 
+// GetTeamsByUserId mocks base method.
+func (m *MockTeamRepository) GetTeamsByUserId(ctx context.Context, userId string) ([]domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamsByUserId indicates an expected call of GetTeamsByUserId.
+func (mr *MockTeamRepositoryMockRecorder) GetTeamsByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsByUserId", reflect.TypeOf((*MockTeamRepository)(nil).GetTeamsByUserId), ctx, userId)
+}
+
+// Rename mocks base method.
+func (m *MockTeamRepository) Rename(ctx context.Context, teamId int, name string) (domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, teamId, name)
+	ret0, _ := ret[0].(domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockTeamRepositoryMockRecorder) Rename(ctx, teamId, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockTeamRepository)(nil).Rename), ctx, teamId, name)
+}
+
+// SetParent mocks base method.
+func (m *MockTeamRepository) SetParent(ctx context.Context, teamId, parentId, maxDepth int) (domain.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParent", ctx, teamId, parentId, maxDepth)
+	ret0, _ := ret[0].(domain.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetParent indicates an expected call of SetParent.
+func (mr *MockTeamRepositoryMockRecorder) SetParent(ctx, teamId, parentId, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockTeamRepository)(nil).SetParent), ctx, teamId, parentId, maxDepth)
+}
+
 // MockPullRequestRepository is a mock of PullRequestRepository interface.
 type MockPullRequestRepository struct {
 	ctrl     *gomock.Controller
@@ -255,6 +481,21 @@ func (mr *MockPullRequestRepositoryMockRecorder) GetByPrIdWithReviewersIds(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrIdWithReviewersIds", reflect.TypeOf((*MockPullRequestRepository)(nil).GetByPrIdWithReviewersIds), ctx, prId)
 }
 
+// GetOpenPRsByAuthorID mocks base method.
+func (m *MockPullRequestRepository) GetOpenPRsByAuthorID(ctx context.Context, authorId string, statusId int) (map[string]repository.GetOpenPRsByReviewerIDsDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenPRsByAuthorID", ctx, authorId, statusId)
+	ret0, _ := ret[0].(map[string]repository.GetOpenPRsByReviewerIDsDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenPRsByAuthorID indicates an expected call of GetOpenPRsByAuthorID.
+func (mr *MockPullRequestRepositoryMockRecorder) GetOpenPRsByAuthorID(ctx, authorId, statusId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenPRsByAuthorID", reflect.TypeOf((*MockPullRequestRepository)(nil).GetOpenPRsByAuthorID), ctx, authorId, statusId)
+}
+
 // GetOpenPRsByReviewerIDs mocks base method.
 func (m *MockPullRequestRepository) GetOpenPRsByReviewerIDs(ctx context.Context, prIds []string, statusId int) (map[string]repository.GetOpenPRsByReviewerIDsDTO, error) {
 	m.ctrl.T.Helper()
@@ -270,6 +511,21 @@ func (mr *MockPullRequestRepositoryMockRecorder) GetOpenPRsByReviewerIDs(ctx, pr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenPRsByReviewerIDs", reflect.TypeOf((*MockPullRequestRepository)(nil).GetOpenPRsByReviewerIDs), ctx, prIds, statusId)
 }
 
+// GetStatuses mocks base method.
+func (m *MockPullRequestRepository) GetStatuses(ctx context.Context, prIds []string) (map[string]domain.PRStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatuses", ctx, prIds)
+	ret0, _ := ret[0].(map[string]domain.PRStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatuses indicates an expected call of GetStatuses.
+func (mr *MockPullRequestRepositoryMockRecorder) GetStatuses(ctx, prIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatuses", reflect.TypeOf((*MockPullRequestRepository)(nil).GetStatuses), ctx, prIds)
+}
+
 // SetMergedStatus mocks base method.
 func (m *MockPullRequestRepository) SetMergedStatus(ctx context.Context, statusId int, prId string) (repository.SetMergedStatusDTO, error) {
 	m.ctrl.T.Helper()
@@ -420,3 +676,358 @@ func (mr *MockStatusRepositoryMockRecorder) GetByName(ctx, statusName any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockStatusRepository)(nil).GetByName), ctx, statusName)
 }
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockOutboxRepository) Add(ctx context.Context, events ...domain.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Add", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOutboxRepositoryMockRecorder) Add(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOutboxRepository)(nil).Add), varargs...)
+}
+
+// Claim mocks base method.
+func (m *MockOutboxRepository) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]domain.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, limit, leaseUntil)
+	ret0, _ := ret[0].([]domain.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockOutboxRepositoryMockRecorder) Claim(ctx, limit, leaseUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockOutboxRepository)(nil).Claim), ctx, limit, leaseUntil)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockOutboxRepositoryMockRecorder) MarkDelivered(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockOutboxRepository)(nil).MarkDelivered), ctx, id)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxRepositoryMockRecorder) MarkFailed(ctx, id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxRepository)(nil).MarkFailed), ctx, id, lastError)
+}
+
+// Reschedule mocks base method.
+func (m *MockOutboxRepository) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, id, nextAttemptAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockOutboxRepositoryMockRecorder) Reschedule(ctx, id, nextAttemptAt, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockOutboxRepository)(nil).Reschedule), ctx, id, nextAttemptAt, lastError)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockWebhookRepository) ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]repository.ClaimedDeliveryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, leaseUntil)
+	ret0, _ := ret[0].([]repository.ClaimedDeliveryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimDeliveries(ctx, limit, leaseUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimDeliveries), ctx, limit, leaseUntil)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, sub)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) CreateSubscription(ctx, sub any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).CreateSubscription), ctx, sub)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteSubscription), ctx, id)
+}
+
+// EnableSubscription mocks base method.
+func (m *MockWebhookRepository) EnableSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableSubscription", ctx, id)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableSubscription indicates an expected call of EnableSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) EnableSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).EnableSubscription), ctx, id)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, event domain.Event, body []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, event, body)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) EnqueueDeliveries(ctx, event, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).EnqueueDeliveries), ctx, event, body)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, subscriptionId, limit int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, subscriptionId, limit)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeliveries(ctx, subscriptionId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeliveries), ctx, subscriptionId, limit)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, id)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) GetSubscription(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).GetSubscription), ctx, id)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", ctx)
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockWebhookRepositoryMockRecorder) GetSubscriptions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockWebhookRepository)(nil).GetSubscriptions), ctx)
+}
+
+// MarkAttemptFailed mocks base method.
+func (m *MockWebhookRepository) MarkAttemptFailed(ctx context.Context, attempt repository.FailedAttemptDTO, disableAfter int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAttemptFailed", ctx, attempt, disableAfter)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAttemptFailed indicates an expected call of MarkAttemptFailed.
+func (mr *MockWebhookRepositoryMockRecorder) MarkAttemptFailed(ctx, attempt, disableAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAttemptFailed", reflect.TypeOf((*MockWebhookRepository)(nil).MarkAttemptFailed), ctx, attempt, disableAfter)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id int64, responseCode int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id, responseCode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockWebhookRepositoryMockRecorder) MarkDelivered(ctx, id, responseCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockWebhookRepository)(nil).MarkDelivered), ctx, id, responseCode)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, statusCode, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Complete(ctx, key, statusCode, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Complete), ctx, key, statusCode, body)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyRepository) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyRepositoryMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyRepository)(nil).Release), ctx, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, key domain.IdempotencyKey, staleBefore time.Time) (domain.IdempotencyKey, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, staleBefore)
+	ret0, _ := ret[0].(domain.IdempotencyKey)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyRepositoryMockRecorder) Reserve(ctx, key, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyRepository)(nil).Reserve), ctx, key, staleBefore)
+}
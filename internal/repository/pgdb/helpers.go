@@ -1,12 +1,32 @@
 package pgdb
 
 import (
+	"avito-internship/pkg/transaction"
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction stored in ctx or pool when there is none, so that
+// queries issued inside a usecase transaction take part in it.
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, err := transaction.TxFromCtx(ctx); err == nil {
+		return tx
+	}
+
+	return pool
+}
+
 func postgresDuplicate(err, errIsExists error) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -31,3 +51,26 @@ func checkGetQueryResult(err, errNotFound error) error {
 
 	return err
 }
+
+// derefInt maps a NULL id to zero.
+func derefInt(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// nullableInt maps a zero id to NULL.
+func nullableInt(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match literally in a LIKE pattern escaped with a backslash.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,112 @@
+package pgdb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{Pool: pool}
+}
+
+func (i *IdempotencyRepository) Reserve(ctx context.Context, key domain.IdempotencyKey, staleBefore time.Time) (domain.IdempotencyKey, bool, error) {
+	const op = "IdempotencyRepository.Reserve"
+
+	query := `
+		INSERT INTO idempotency_keys (key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, response_body = NULL,
+		    created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at <= $5)
+		RETURNING key
+	`
+
+	var reserved string
+	err := conn(ctx, i.Pool).QueryRow(ctx, query, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt, staleBefore).Scan(&reserved)
+	if err == nil {
+		return key, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyKey{}, false, e.Wrap(op, err)
+	}
+
+	var model IdempotencyKeyModel
+	err = conn(ctx, i.Pool).QueryRow(ctx, `
+		SELECT key, request_hash, status_code, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`, key.Key).Scan(&model.Key, &model.RequestHash, &model.StatusCode, &model.ResponseBody, &model.CreatedAt, &model.ExpiresAt)
+	// The key was released in between; the client retries.
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyKey{}, false, e.Wrap(op, e.ErrIdempotencyKeyInUse)
+	}
+	if err != nil {
+		return domain.IdempotencyKey{}, false, e.Wrap(op, err)
+	}
+
+	return toDomainIdempotencyKey(model), false, nil
+}
+
+func (i *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, body []byte) error {
+	const op = "IdempotencyRepository.Complete"
+
+	_, err := conn(ctx, i.Pool).Exec(ctx,
+		`UPDATE idempotency_keys SET status_code = $1, response_body = $2 WHERE key = $3`,
+		statusCode, string(body), key)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	const op = "IdempotencyRepository.Release"
+
+	_, err := conn(ctx, i.Pool).Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`, key)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "IdempotencyRepository.DeleteExpired"
+
+	tag, err := conn(ctx, i.Pool).Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func toDomainIdempotencyKey(model IdempotencyKeyModel) domain.IdempotencyKey {
+	key := domain.IdempotencyKey{
+		Key:         model.Key,
+		RequestHash: model.RequestHash,
+		CreatedAt:   model.CreatedAt,
+		ExpiresAt:   model.ExpiresAt,
+	}
+	if model.StatusCode != nil {
+		key.StatusCode = *model.StatusCode
+	}
+	if model.ResponseBody != nil {
+		key.Body = []byte(*model.ResponseBody)
+	}
+
+	return key
+}
@@ -0,0 +1,143 @@
+package pgdb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{Pool: pool}
+}
+
+// Add writes events in the transaction stored in ctx, so they are committed
+// together with the change they describe.
+func (o *OutboxRepository) Add(ctx context.Context, events ...domain.Event) error {
+	const op = "OutboxRepository.Add"
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	builder := sq.Insert("outbox_events").
+		Columns("event_type", "payload")
+
+	for _, event := range events {
+		builder = builder.Values(string(event.Type), event.Payload)
+	}
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]domain.Event, error) {
+	const op = "OutboxRepository.Claim"
+
+	// Rows locked by another dispatcher are skipped instead of waited for, and the
+	// lease keeps them away from other dispatchers until delivery is finished.
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, payload, attempts, created_at
+	`
+
+	rows, err := conn(ctx, o.Pool).Query(ctx, query, leaseUntil, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.Event, 0)
+	for rows.Next() {
+		var model EventModel
+		if err := rows.Scan(&model.Id, &model.Type, &model.Payload, &model.Attempts, &model.CreatedAt); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		events = append(events, toDomainEvent(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(events, func(a, b domain.Event) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	return events, nil
+}
+
+func (o *OutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	const op = "OutboxRepository.MarkDelivered"
+
+	query := `UPDATE outbox_events SET delivered_at = NOW(), last_error = NULL WHERE id = $1`
+	if _, err := conn(ctx, o.Pool).Exec(ctx, query, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	const op = "OutboxRepository.Reschedule"
+
+	query := `UPDATE outbox_events SET next_attempt_at = $1, last_error = $2 WHERE id = $3`
+	if _, err := conn(ctx, o.Pool).Exec(ctx, query, nextAttemptAt, lastError, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	const op = "OutboxRepository.MarkFailed"
+
+	query := `UPDATE outbox_events SET failed_at = NOW(), last_error = $1 WHERE id = $2`
+	if _, err := conn(ctx, o.Pool).Exec(ctx, query, lastError, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toDomainEvent(model EventModel) domain.Event {
+	return domain.Event{
+		Id:        model.Id,
+		Type:      model.Type,
+		Payload:   model.Payload,
+		Attempts:  model.Attempts,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -71,7 +71,7 @@ func (p *PrReviewerRepository) GetPRByReviewer(ctx context.Context, userId strin
 		return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
 	}
 
-	rows, err := p.Pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, p.Pool).Query(ctx, query, args...)
 	if err != nil {
 		return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
 	}
@@ -126,7 +126,7 @@ func (p *PrReviewerRepository) UpdateReviewer(ctx context.Context, oldUserId str
 	}
 
 	var returnedPrID string
-	err = p.Pool.QueryRow(ctx, query, args...).Scan(&returnedPrID)
+	err = conn(ctx, p.Pool).QueryRow(ctx, query, args...).Scan(&returnedPrID)
 	if err != nil {
 		return "", e.Wrap(op, err)
 	}
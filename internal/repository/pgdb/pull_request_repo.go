@@ -29,16 +29,16 @@ func (p *PullRequestsRepository) Create(ctx context.Context, pullRequest domain.
 
 	model := toPRModel(pullRequest)
 	builder := sq.Insert("pull_requests").
-		Columns("id", "name", "author_id", "status_id", "need_more_reviewers", "created_at").
-		Values(model.Id, model.Name, model.AuthorId, model.StatusId, model.NeedMoreReviewers, model.CreatedAt).
-		Suffix("RETURNING id, name, author_id, status_id, need_more_reviewers, created_at, merged_at")
+		Columns("id", "name", "author_id", "status_id", "need_more_reviewers", "created_at", "team_id").
+		Values(model.Id, model.Name, model.AuthorId, model.StatusId, model.NeedMoreReviewers, model.CreatedAt, model.TeamId).
+		Suffix("RETURNING id, name, author_id, status_id, need_more_reviewers, created_at, merged_at, team_id")
 
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		return domain.PullRequest{}, e.Wrap(op, err)
 	}
 
-	err = tx.QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.AuthorId, &model.StatusId, &model.NeedMoreReviewers, &model.CreatedAt, &model.MergedAt)
+	err = tx.QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.AuthorId, &model.StatusId, &model.NeedMoreReviewers, &model.CreatedAt, &model.MergedAt, &model.TeamId)
 	err = postgresDuplicate(err, e.ErrPRIsExists)
 	err = postgresForeignKeyViolation(err, e.ErrUserNotFound)
 	if err != nil {
@@ -56,15 +56,15 @@ func (p *PullRequestsRepository) SetMergedStatus(ctx context.Context, statusId i
 			UPDATE pull_requests
 			SET status_id = $1, merged_at = NOW()
 			WHERE id = $2
-			RETURNING id, name, author_id, status_id, need_more_reviewers, created_at, merged_at
+			RETURNING id, name, author_id, status_id, need_more_reviewers, created_at, merged_at, team_id
 		)
 		SELECT u.id, u.name, u.author_id, u.status_id, u.need_more_reviewers, u.created_at, u.merged_at,
-		       r.reviewer_id
+		       u.team_id, r.reviewer_id
 		FROM updated_pr u
 		LEFT JOIN pr_reviewers r ON r.pr_id = u.id
 	`
 
-	rows, err := p.Pool.Query(ctx, query, statusId, prId)
+	rows, err := conn(ctx, p.Pool).Query(ctx, query, statusId, prId)
 	if err != nil {
 		return r.SetMergedStatusDTO{}, e.Wrap(op, err)
 	}
@@ -76,7 +76,7 @@ func (p *PullRequestsRepository) SetMergedStatus(ctx context.Context, statusId i
 	for rows.Next() {
 		var reviewerID *string
 		if err := rows.Scan(&upd.Id, &upd.Name, &upd.AuthorId, &upd.StatusId,
-			&upd.NeedMoreReviewers, &upd.CreatedAt, &upd.MergedAt, &reviewerID); err != nil {
+			&upd.NeedMoreReviewers, &upd.CreatedAt, &upd.MergedAt, &upd.TeamId, &reviewerID); err != nil {
 			return r.SetMergedStatusDTO{}, e.Wrap(op, err)
 		}
 		if reviewerID != nil {
@@ -100,10 +100,12 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 
 	builder := sq.Select(
 		"pr.id", "pr.name", "pr.author_id", "pr.status_id", "pr.need_more_reviewers", "pr.created_at", "pr.merged_at",
+		"pr.team_id", "COALESCE(pr.team_id, author.team_id)",
 		"s.name AS status_name",
 		"r.reviewer_id",
 	).
 		From("pull_requests AS pr").
+		Join("users AS author ON author.id = pr.author_id").
 		LeftJoin("pr_reviewers AS r ON r.pr_id = pr.id").
 		LeftJoin("statuses AS s ON pr.status_id = s.id").
 		Where(sq.Eq{"pr.id": prId})
@@ -113,7 +115,7 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
 	}
 
-	rows, err := p.Pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, p.Pool).Query(ctx, query, args...)
 	if err != nil {
 		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
 	}
@@ -121,13 +123,14 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 
 	var (
 		model        PullRequestModel
+		reviewTeamId *int
 		reviewersIds = make([]string, 0)
 		statusName   domain.PRStatus
 		prFound      = false
 	)
 
 	for rows.Next() {
-		var reviewerId string
+		var reviewerId *string
 		if err := rows.Scan(
 			&model.Id,
 			&model.Name,
@@ -136,6 +139,8 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 			&model.NeedMoreReviewers,
 			&model.CreatedAt,
 			&model.MergedAt,
+			&model.TeamId,
+			&reviewTeamId,
 			&statusName,
 			&reviewerId,
 		); err != nil {
@@ -143,8 +148,8 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 		}
 		prFound = true
 
-		if reviewerId != "" {
-			reviewersIds = append(reviewersIds, reviewerId)
+		if reviewerId != nil {
+			reviewersIds = append(reviewersIds, *reviewerId)
 		}
 	}
 
@@ -156,7 +161,10 @@ func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context,
 		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, e.ErrPRNotFound)
 	}
 
-	return r.NewGetByPrIdWithReviewersIdsDTO(toDomainPR(model), reviewersIds, statusName), nil
+	dto := r.NewGetByPrIdWithReviewersIdsDTO(toDomainPR(model), reviewersIds, statusName)
+	dto.ReviewTeamId = derefInt(reviewTeamId)
+
+	return dto, nil
 }
 
 func (p *PullRequestsRepository) GetOpenPRsByReviewerIDs(ctx context.Context, reviewersIds []string, statusId int) (map[string]r.GetOpenPRsByReviewerIDsDTO, error) {
@@ -177,8 +185,11 @@ func (p *PullRequestsRepository) GetOpenPRsByReviewerIDs(ctx context.Context, re
           pr.need_more_reviewers AS need_more_reviewers,
           pr.created_at AS created_at,
           pr.merged_at AS merged_at,
-          r_all.reviewer_id AS reviewer_id
+          r_all.reviewer_id AS reviewer_id,
+          pr.team_id AS team_id,
+          COALESCE(pr.team_id, author.team_id) AS review_team_id
        FROM pull_requests pr
+       JOIN users author ON author.id = pr.author_id
        JOIN pr_reviewers r_search ON pr.id = r_search.pr_id
        JOIN pr_reviewers r_all ON pr.id = r_all.pr_id
        JOIN statuses s ON pr.status_id = s.id
@@ -197,30 +208,34 @@ func (p *PullRequestsRepository) GetOpenPRsByReviewerIDs(ctx context.Context, re
 
 	for rows.Next() {
 		var prID, reviewerID, statusName string
-		var pr domain.PullRequest
+		var model PullRequestModel
+		var reviewTeamId *int
 
 		if err := rows.Scan(
-			&pr.Id,
-			&pr.Name,
-			&pr.AuthorId,
-			&pr.StatusId,
+			&model.Id,
+			&model.Name,
+			&model.AuthorId,
+			&model.StatusId,
 			&statusName,
-			&pr.NeedMoreReviewers,
-			&pr.CreatedAt,
-			&pr.MergedAt,
+			&model.NeedMoreReviewers,
+			&model.CreatedAt,
+			&model.MergedAt,
 			&reviewerID,
+			&model.TeamId,
+			&reviewTeamId,
 		); err != nil {
 			return nil, e.Wrap(op, err)
 		}
 
-		prID = pr.Id
+		prID = model.Id
 
 		dto, exists := prTempMap[prID]
 		if !exists {
 			dto = r.GetOpenPRsByReviewerIDsDTO{
-				Pr:           pr,
+				Pr:           toDomainPR(model),
 				ReviewersIds: []string{},
 				StatusName:   statusName,
+				ReviewTeamId: derefInt(reviewTeamId),
 			}
 			prTempMap[prID] = dto
 		}
@@ -245,6 +260,71 @@ func (p *PullRequestsRepository) GetOpenPRsByReviewerIDs(ctx context.Context, re
 	return prTempMap, nil
 }
 
+func (p *PullRequestsRepository) GetOpenPRsByAuthorID(ctx context.Context, authorId string, statusId int) (map[string]r.GetOpenPRsByReviewerIDsDTO, error) {
+	const op = "PullRequestsRepository.GetOpenPRsByAuthorID"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	query := `
+       SELECT
+          pr.id, pr.name, pr.author_id, pr.status_id, s.name,
+          pr.need_more_reviewers, pr.created_at, pr.merged_at, r.reviewer_id,
+          pr.team_id, COALESCE(pr.team_id, author.team_id)
+       FROM pull_requests pr
+       JOIN users author ON author.id = pr.author_id
+       JOIN pr_reviewers r ON pr.id = r.pr_id
+       JOIN statuses s ON pr.status_id = s.id
+       WHERE pr.author_id = $1
+          AND pr.status_id = $2
+       ORDER BY pr.id;
+    `
+
+	rows, err := tx.Query(ctx, query, authorId, statusId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	prs := make(map[string]r.GetOpenPRsByReviewerIDsDTO)
+	for rows.Next() {
+		var (
+			model        PullRequestModel
+			statusName   string
+			reviewerID   string
+			reviewTeamId *int
+		)
+
+		if err := rows.Scan(
+			&model.Id, &model.Name, &model.AuthorId, &model.StatusId, &statusName,
+			&model.NeedMoreReviewers, &model.CreatedAt, &model.MergedAt, &reviewerID,
+			&model.TeamId, &reviewTeamId,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		dto, exists := prs[model.Id]
+		if !exists {
+			dto = r.GetOpenPRsByReviewerIDsDTO{
+				Pr:           toDomainPR(model),
+				ReviewersIds: []string{},
+				StatusName:   statusName,
+				ReviewTeamId: derefInt(reviewTeamId),
+			}
+		}
+		dto.ReviewersIds = append(dto.ReviewersIds, reviewerID)
+		prs[model.Id] = dto
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return prs, nil
+}
+
 func toPRModel(p domain.PullRequest) PullRequestModel {
 	return PullRequestModel{
 		Id:                p.Id,
@@ -254,6 +334,7 @@ func toPRModel(p domain.PullRequest) PullRequestModel {
 		NeedMoreReviewers: p.NeedMoreReviewers,
 		CreatedAt:         p.CreatedAt,
 		MergedAt:          p.MergedAt,
+		TeamId:            nullableInt(p.TeamId),
 	}
 }
 
@@ -266,5 +347,43 @@ func toDomainPR(p PullRequestModel) domain.PullRequest {
 		NeedMoreReviewers: p.NeedMoreReviewers,
 		CreatedAt:         p.CreatedAt,
 		MergedAt:          p.MergedAt,
+		TeamId:            derefInt(p.TeamId),
 	}
 }
+
+func (p *PullRequestsRepository) GetStatuses(ctx context.Context, prIds []string) (map[string]domain.PRStatus, error) {
+	const op = "PullRequestsRepository.GetStatuses"
+
+	statuses := make(map[string]domain.PRStatus, len(prIds))
+	if len(prIds) == 0 {
+		return statuses, nil
+	}
+
+	query := `
+		SELECT pr.id, s.name
+		FROM pull_requests pr
+		JOIN statuses s ON pr.status_id = s.id
+		WHERE pr.id = ANY($1)
+	`
+
+	rows, err := conn(ctx, p.Pool).Query(ctx, query, prIds)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var status domain.PRStatus
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		statuses[id] = status
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return statuses, nil
+}
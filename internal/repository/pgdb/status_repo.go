@@ -24,7 +24,7 @@ func (s *StatusRepo) GetById(ctx context.Context, statusId int) (domain.Status,
 	query := `SELECT id, name FROM statuses WHERE id = $1`
 
 	var model StatusModel
-	err := s.Pool.QueryRow(ctx, query, statusId).Scan(&model.Id, &model.Name)
+	err := conn(ctx, s.Pool).QueryRow(ctx, query, statusId).Scan(&model.Id, &model.Name)
 	if err = checkGetQueryResult(err, e.ErrStatusNotFound); err != nil {
 		return domain.Status{}, e.Wrap(op, err)
 	}
@@ -45,7 +45,7 @@ func (s *StatusRepo) GetByName(ctx context.Context, statusName string) (domain.S
 	query := `SELECT id, name FROM statuses WHERE name = $1`
 
 	var model StatusModel
-	err := s.Pool.QueryRow(ctx, query, statusName).Scan(&model.Id, &model.Name)
+	err := conn(ctx, s.Pool).QueryRow(ctx, query, statusName).Scan(&model.Id, &model.Name)
 	if err = checkGetQueryResult(err, e.ErrStatusNotFound); err != nil {
 		return domain.Status{}, e.Wrap(op, err)
 	}
@@ -10,6 +10,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var teamColumns = []string{"id", "name", "parent_id", "max_depth"}
+
 type TeamRepository struct {
 	Pool *pgxpool.Pool
 }
@@ -52,7 +54,8 @@ func (t *TeamRepository) GetMembersByTeamNameWithUsers(ctx context.Context, team
 		"users.id", "users.name", "users.is_active", "users.team_id",
 	).
 		From("teams").
-		LeftJoin("users ON teams.id = users.team_id").
+		LeftJoin("team_members tm ON tm.team_id = teams.id").
+		LeftJoin("users ON users.id = tm.user_id").
 		Where(sq.Eq{"teams.name": teamName})
 
 	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
@@ -60,7 +63,7 @@ func (t *TeamRepository) GetMembersByTeamNameWithUsers(ctx context.Context, team
 		return nil, e.Wrap(op, err)
 	}
 
-	rows, err := t.Pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, t.Pool).Query(ctx, query, args...)
 	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -78,7 +81,7 @@ func (t *TeamRepository) GetMembersByTeamNameWithUsers(ctx context.Context, team
 			uId       *string
 			uName     *string
 			uIsActive *bool
-			uTeamId   int
+			uTeamId   *int
 		)
 
 		err := rows.Scan(
@@ -124,7 +127,7 @@ func (t *TeamRepository) GetTeamByUserId(ctx context.Context, userId string) (do
 	}
 
 	var model TeamModel
-	err = t.Pool.QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name)
+	err = conn(ctx, t.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name)
 	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
 		return domain.Team{}, e.Wrap(op, err)
 	}
@@ -132,16 +135,193 @@ func (t *TeamRepository) GetTeamByUserId(ctx context.Context, userId string) (do
 	return toDomainTeam(model), nil
 }
 
+func (t *TeamRepository) GetTeamsByUserId(ctx context.Context, userId string) ([]domain.Team, error) {
+	const op = "TeamRepository.GetTeamsByUserId"
+
+	builder := sq.Select("teams.id", "teams.name").
+		From("teams").
+		Join("team_members tm ON tm.team_id = teams.id").
+		Where(sq.Eq{"tm.user_id": userId}).
+		OrderBy("teams.name")
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, t.Pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	teams := make([]domain.Team, 0)
+	for rows.Next() {
+		var model TeamModel
+		if err := rows.Scan(&model.Id, &model.Name); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		teams = append(teams, toDomainTeam(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return teams, nil
+}
+
+func (t *TeamRepository) GetById(ctx context.Context, teamId int) (domain.Team, error) {
+	const op = "TeamRepository.GetById"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"id": teamId})
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetByName(ctx context.Context, teamName string) (domain.Team, error) {
+	const op = "TeamRepository.GetByName"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"name": teamName})
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) Rename(ctx context.Context, teamId int, name string) (domain.Team, error) {
+	const op = "TeamRepository.Rename"
+
+	builder := sq.Update("teams").
+		Set("name", name).
+		Where(sq.Eq{"id": teamId}).
+		Suffix("RETURNING id, name, parent_id, max_depth")
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err = postgresDuplicate(err, e.ErrTeamIsExists); err != nil {
+		return domain.Team{}, e.Wrap(op, checkGetQueryResult(err, e.ErrTeamNotFound))
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetChildren(ctx context.Context, parentId int) ([]domain.Team, error) {
+	const op = "TeamRepository.GetChildren"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"parent_id": parentId}).
+		OrderBy("name")
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, t.Pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	teams := make([]domain.Team, 0)
+	for rows.Next() {
+		var model TeamModel
+		if err := rows.Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		teams = append(teams, toDomainTeam(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return teams, nil
+}
+
+func (t *TeamRepository) SetParent(ctx context.Context, teamId int, parentId int, maxDepth int) (domain.Team, error) {
+	const op = "TeamRepository.SetParent"
+
+	builder := sq.Update("teams").
+		Set("parent_id", nullableInt(parentId)).
+		Set("max_depth", maxDepth).
+		Where(sq.Eq{"id": teamId}).
+		Suffix("RETURNING id, name, parent_id, max_depth")
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) Delete(ctx context.Context, teamId int) error {
+	const op = "TeamRepository.Delete"
+
+	tag, err := conn(ctx, t.Pool).Exec(ctx, `DELETE FROM teams WHERE id = $1`, teamId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return e.Wrap(op, e.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
 func toDomainTeam(model TeamModel) domain.Team {
 	return domain.Team{
-		Id:   model.Id,
-		Name: model.Name,
+		Id:       model.Id,
+		Name:     model.Name,
+		ParentId: derefInt(model.ParentId),
+		MaxDepth: model.MaxDepth,
 	}
 }
 
 func toTeamModel(team domain.Team) TeamModel {
 	return TeamModel{
-		Id:   team.Id,
-		Name: team.Name,
+		Id:       team.Id,
+		Name:     team.Name,
+		ParentId: nullableInt(team.ParentId),
+		MaxDepth: team.MaxDepth,
 	}
 }
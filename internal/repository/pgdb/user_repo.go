@@ -2,11 +2,14 @@ package pgdb
 
 import (
 	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
 	"avito-internship/pkg/e"
 	"avito-internship/pkg/transaction"
 	"context"
+	"encoding/json"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -32,7 +35,7 @@ func (u *UserRepository) UpdateIsActive(ctx context.Context, userId string, isAc
 	}
 
 	var updModel UserModel
-	err = u.Pool.QueryRow(ctx, query, args...).Scan(&updModel.Id, &updModel.Name, &updModel.IsActive, &updModel.TeamId)
+	err = conn(ctx, u.Pool).QueryRow(ctx, query, args...).Scan(&updModel.Id, &updModel.Name, &updModel.IsActive, &updModel.TeamId)
 	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
 		return domain.User{}, e.Wrap(op, err)
 	}
@@ -53,7 +56,7 @@ func (u *UserRepository) GetById(ctx context.Context, userId string) (domain.Use
 	}
 
 	var model UserModel
-	err = u.Pool.QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+	err = conn(ctx, u.Pool).QueryRow(ctx, query, args...).Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
 	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
 		return domain.User{}, e.Wrap(op, err)
 	}
@@ -61,7 +64,88 @@ func (u *UserRepository) GetById(ctx context.Context, userId string) (domain.Use
 	return toDomainUser(model), nil
 }
 
-func (u *UserRepository) GetReviewCandidates(ctx context.Context, authorId string, maxReviewers int) ([]domain.User, error) {
+func (u *UserRepository) List(ctx context.Context, filter r.UserFilter) ([]domain.User, int, error) {
+	const op = "UserRepository.List"
+
+	where := sq.And{}
+	if filter.Query != "" {
+		where = append(where, sq.Or{
+			sq.Like{"users.id": escapeLike(filter.Query) + "%"},
+			sq.ILike{"users.name": "%" + escapeLike(filter.Query) + "%"},
+		})
+	}
+	if filter.TeamId != 0 {
+		where = append(where, sq.Expr("EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = users.id AND tm.team_id = ?)", filter.TeamId))
+	}
+	if filter.IsActive != nil {
+		where = append(where, sq.Eq{"users.is_active": *filter.IsActive})
+	}
+
+	countQuery, args, err := sq.Select("COUNT(*)").From("users").Where(where).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	var total int
+	if err := conn(ctx, u.Pool).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	builder := sq.Select("users.id", "users.name", "users.is_active", "users.team_id").
+		From("users").
+		Where(where).
+		OrderBy("users.id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, u.Pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	models := make([]UserModel, 0)
+	for rows.Next() {
+		var m UserModel
+		if err := rows.Scan(&m.Id, &m.Name, &m.IsActive, &m.TeamId); err != nil {
+			return nil, 0, e.Wrap(op, err)
+		}
+		models = append(models, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(models), total, nil
+}
+
+func (u *UserRepository) GetStats(ctx context.Context, userId string, openStatusId int) (r.UserStatsDTO, error) {
+	const op = "UserRepository.GetStats"
+
+	query := `
+       SELECT
+           (SELECT COUNT(*) FROM pr_reviewers r
+            JOIN pull_requests pr ON pr.id = r.pr_id
+            WHERE r.reviewer_id = $1 AND pr.status_id = $2),
+           (SELECT COUNT(*) FROM pull_requests WHERE author_id = $1)
+    `
+
+	var stats r.UserStatsDTO
+	err := conn(ctx, u.Pool).QueryRow(ctx, query, userId, openStatusId).Scan(&stats.OpenReviews, &stats.AuthoredPrs)
+	if err != nil {
+		return r.UserStatsDTO{}, e.Wrap(op, err)
+	}
+
+	return stats, nil
+}
+
+func (u *UserRepository) GetReviewCandidates(ctx context.Context, teamId int, authorId string, maxReviewers int) ([]domain.User, error) {
 	const op = "UserRepository.GetReviewCandidates"
 
 	tx, err := transaction.TxFromCtx(ctx)
@@ -70,17 +154,18 @@ func (u *UserRepository) GetReviewCandidates(ctx context.Context, authorId strin
 	}
 
 	query := `
-       SELECT id, name, is_active, team_id
+       SELECT users.id, users.name, users.is_active, users.team_id
        FROM users
+       JOIN team_members tm ON tm.user_id = users.id
        WHERE
-           team_id = (SELECT team_id FROM users WHERE id = $1)
-           AND is_active = TRUE
-           AND id != $1
+           tm.team_id = $1
+           AND users.is_active = TRUE
+           AND users.id != $2
        ORDER BY random()
-       LIMIT $2
+       LIMIT $3
     `
 
-	rows, err := tx.Query(ctx, query, authorId, maxReviewers)
+	rows, err := tx.Query(ctx, query, teamId, authorId, maxReviewers)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -103,14 +188,15 @@ func (u *UserRepository) GetReviewCandidates(ctx context.Context, authorId strin
 	return toArrDomainUser(models), nil
 }
 
-func (u *UserRepository) GetReassignCandidates(ctx context.Context, authorId string, excludeIds []string, maxCandidates int) ([]domain.User, error) {
+func (u *UserRepository) GetReassignCandidates(ctx context.Context, teamId int, excludeIds []string, maxCandidates int) ([]domain.User, error) {
 	const op = "UserRepository.GetReassignCandidates"
 
-	builder := sq.Select("id", "name", "is_active", "team_id").
+	builder := sq.Select("users.id", "users.name", "users.is_active", "users.team_id").
 		From("users").
-		Where(sq.Expr("team_id = (SELECT team_id FROM users WHERE id = ?)", authorId)).
-		Where(sq.Eq{"is_active": true}).
-		Where(sq.NotEq{"id": excludeIds}).
+		Join("team_members tm ON tm.user_id = users.id").
+		Where(sq.Eq{"tm.team_id": teamId}).
+		Where(sq.Eq{"users.is_active": true}).
+		Where(sq.NotEq{"users.id": excludeIds}).
 		OrderBy("random()").
 		Limit(uint64(maxCandidates))
 
@@ -119,7 +205,7 @@ func (u *UserRepository) GetReassignCandidates(ctx context.Context, authorId str
 		return nil, e.Wrap(op, err)
 	}
 
-	rows, err := u.Pool.Query(ctx, query, args...)
+	rows, err := conn(ctx, u.Pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -176,7 +262,9 @@ func (u *UserRepository) AddUsersToTeam(ctx context.Context, teamId int, users [
 		SET
 			name = EXCLUDED.name,
 			is_active = EXCLUDED.is_active,
-			team_id = EXCLUDED.team_id
+			team_id = COALESCE(users.team_id, EXCLUDED.team_id)
+		WHERE users.team_id IS NULL OR users.team_id = EXCLUDED.team_id
+			OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = EXCLUDED.team_id AND tm.user_id = users.id)
 		RETURNING id, name, is_active, team_id
 	`
 
@@ -187,6 +275,7 @@ func (u *UserRepository) AddUsersToTeam(ctx context.Context, teamId int, users [
 	defer rows.Close()
 
 	updUsers := make([]domain.User, 0, len(users))
+	updIds := make([]string, 0, len(users))
 	for rows.Next() {
 		var userId string
 		var userName string
@@ -198,15 +287,50 @@ func (u *UserRepository) AddUsersToTeam(ctx context.Context, teamId int, users [
 
 		updUser := domain.NewUser(userId, userName, userIsActive, userTeamId)
 		updUsers = append(updUsers, *updUser)
+		updIds = append(updIds, userId)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT $1, u.id FROM UNNEST($2::varchar[]) AS u(id)
+		ON CONFLICT DO NOTHING`, teamId, updIds)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	return updUsers, nil
 }
 
+func (u *UserRepository) AddMembership(ctx context.Context, teamId int, userId string) (domain.User, error) {
+	const op = "UserRepository.AddMembership"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	var model UserModel
+	err = tx.QueryRow(ctx, `
+		UPDATE users SET team_id = COALESCE(team_id, $1)
+		WHERE id = $2
+		RETURNING id, name, is_active, team_id`, teamId, userId).
+		Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO team_members (team_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, teamId, userId)
+	if err = postgresForeignKeyViolation(err, e.ErrTeamNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
 func (u *UserRepository) DeactivateUsers(ctx context.Context, ids []string) ([]domain.User, error) {
 	const op = "UserRepository.DeactivateTeamMembers"
 
@@ -247,13 +371,277 @@ func (u *UserRepository) DeactivateUsers(ctx context.Context, ids []string) ([]d
 	return toArrDomainUser(users), nil
 }
 
+func (u *UserRepository) MoveToTeam(ctx context.Context, userId string, teamId int) (domain.User, error) {
+	const op = "UserRepository.MoveToTeam"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM team_members tm
+		USING users u
+		WHERE u.id = $1 AND tm.user_id = u.id AND tm.team_id = u.team_id`, userId)
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	var model UserModel
+	err = tx.QueryRow(ctx, `UPDATE users SET team_id = $1 WHERE id = $2 RETURNING id, name, is_active, team_id`, teamId, userId).
+		Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO team_members (team_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, teamId, userId)
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+func (u *UserRepository) MoveTeamMembers(ctx context.Context, fromTeamId, toTeamId int) ([]domain.User, error) {
+	const op = "UserRepository.MoveTeamMembers"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT $1, user_id FROM team_members WHERE team_id = $2
+		ON CONFLICT DO NOTHING`, toTeamId, fromTeamId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET team_id = $1 WHERE team_id = $2`, toTeamId, fromTeamId); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH members AS (
+			DELETE FROM team_members WHERE team_id = $1 RETURNING user_id
+		)
+		SELECT u.id, u.name, u.is_active, u.team_id
+		FROM users u
+		JOIN members m ON m.user_id = u.id`, fromTeamId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	users := make([]UserModel, 0)
+	for rows.Next() {
+		var m UserModel
+		if err := rows.Scan(&m.Id, &m.Name, &m.IsActive, &m.TeamId); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		users = append(users, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(users), nil
+}
+
+func (u *UserRepository) RemoveUsersFromTeam(ctx context.Context, teamId int, ids []string) ([]domain.User, error) {
+	const op = "UserRepository.RemoveUsersFromTeam"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	removed := make([]string, 0, len(ids))
+	rows, err := tx.Query(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = ANY($2) RETURNING user_id`, teamId, ids)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, e.Wrap(op, err)
+		}
+		removed = append(removed, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	// Users leaving their primary team fall back to another membership, if any.
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET team_id = (SELECT MIN(tm.team_id) FROM team_members tm WHERE tm.user_id = users.id)
+		WHERE id = ANY($2) AND team_id = $1`, teamId, removed)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err = tx.Query(ctx, `SELECT id, name, is_active, team_id FROM users WHERE id = ANY($1)`, removed)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	users := make([]UserModel, 0, len(ids))
+	for rows.Next() {
+		var m UserModel
+		if err := rows.Scan(&m.Id, &m.Name, &m.IsActive, &m.TeamId); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		users = append(users, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(users), nil
+}
+
+func (u *UserRepository) IsTombstone(ctx context.Context, userId string) (bool, error) {
+	const op = "UserRepository.IsTombstone"
+
+	var exists bool
+	err := conn(ctx, u.Pool).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_tombstones WHERE user_id = $1)`, userId).Scan(&exists)
+	if err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return exists, nil
+}
+
+func (u *UserRepository) Anonymize(ctx context.Context, userId, tombstoneId, pseudonym string) (domain.User, error) {
+	const op = "UserRepository.Anonymize"
+
+	tx, err := transaction.TxFromCtx(ctx)
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	var model UserModel
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (id, name, team_id, is_active)
+		SELECT $2, $3, team_id, false FROM users WHERE id = $1
+		RETURNING id, name, is_active, team_id`, userId, tombstoneId, pseudonym).
+		Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO user_tombstones (user_id) VALUES ($1)`, tombstoneId); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	// The foreign keys have no ON UPDATE CASCADE, so the references move to the
+	// tombstone before the user's row goes.
+	for _, query := range []string{
+		`UPDATE team_members SET user_id = $2 WHERE user_id = $1`,
+		`UPDATE pull_requests SET author_id = $2 WHERE author_id = $1`,
+		`UPDATE pr_reviewers SET reviewer_id = $2 WHERE reviewer_id = $1`,
+	} {
+		if _, err := tx.Exec(ctx, query, userId, tombstoneId); err != nil {
+			return domain.User{}, e.Wrap(op, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userId); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	// Stored events and webhook deliveries name the user by id and now name the
+	// tombstone. Stored responses may carry the name as well, so they are dropped.
+	for _, queries := range [][2]string{
+		{`SELECT id, payload::text FROM outbox_events WHERE strpos(payload::text, $1) > 0`,
+			`UPDATE outbox_events SET payload = $2::jsonb WHERE id = $1`},
+		{`SELECT id, body FROM webhook_deliveries WHERE strpos(body, $1) > 0`,
+			`UPDATE webhook_deliveries SET body = $2 WHERE id = $1`},
+	} {
+		if err := replaceUserId(ctx, tx, queries[0], queries[1], userId, tombstoneId); err != nil {
+			return domain.User{}, e.Wrap(op, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM idempotency_keys WHERE strpos(response_body, $1) > 0`, jsonString(userId)); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+// replaceUserId renames the user in the JSON documents that selectQuery returns
+// as (id, document) and writes the changed ones back with updateQuery.
+func replaceUserId(ctx context.Context, tx pgx.Tx, selectQuery, updateQuery, oldId, newId string) error {
+	docs, err := jsonDocuments(ctx, tx, selectQuery, jsonString(oldId))
+	if err != nil {
+		return err
+	}
+
+	for id, doc := range docs {
+		res, changed, err := domain.ReplaceUserId([]byte(doc), oldId, newId)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		if _, err := tx.Exec(ctx, updateQuery, id, string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func jsonDocuments(ctx context.Context, tx pgx.Tx, query string, args ...any) (map[int64]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id  int64
+			doc string
+		)
+		if err := rows.Scan(&id, &doc); err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+
+	return docs, rows.Err()
+}
+
+// jsonString quotes s the way ids appear in stored JSON.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 func toDomainUser(u UserModel) domain.User {
-	return domain.User{
+	user := domain.User{
 		Id:       u.Id,
 		Name:     u.Name,
 		IsActive: u.IsActive,
-		TeamId:   u.TeamId,
 	}
+	if u.TeamId != nil {
+		user.TeamId = *u.TeamId
+	}
+
+	return user
 }
 
 func toArrDomainUser(u []UserModel) []domain.User {
@@ -0,0 +1,406 @@
+package pgdb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.body, d.status, d.attempts,
+	d.response_code, d.last_error, d.created_at, d.next_attempt_at, d.delivered_at`
+
+type WebhookRepository struct {
+	Pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{Pool: pool}
+}
+
+func (w *WebhookRepository) CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.CreateSubscription"
+
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, is_active)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, is_active, consecutive_failures, created_at, disabled_at
+	`
+
+	var model WebhookSubscriptionModel
+	err := conn(ctx, w.Pool).QueryRow(ctx, query, sub.URL, sub.Secret, sub.IsActive).
+		Scan(&model.Id, &model.URL, &model.Secret, &model.IsActive, &model.ConsecutiveFailures, &model.CreatedAt, &model.DisabledAt)
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	builder := sq.Insert("webhook_subscription_events").
+		Columns("subscription_id", "event_type")
+	for _, eventType := range sub.Events {
+		builder = builder.Values(model.Id, string(eventType))
+	}
+
+	insertQuery, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	if _, err := conn(ctx, w.Pool).Exec(ctx, insertQuery, args...); err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	return toDomainSubscription(model, sub.Events), nil
+}
+
+func (w *WebhookRepository) GetSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.GetSubscription"
+
+	subs, err := w.getSubscriptions(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	if len(subs) == 0 {
+		return domain.WebhookSubscription{}, e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	return subs[0], nil
+}
+
+func (w *WebhookRepository) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.GetSubscriptions"
+
+	subs, err := w.getSubscriptions(ctx, nil)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return subs, nil
+}
+
+func (w *WebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	const op = "WebhookRepository.DeleteSubscription"
+
+	tag, err := conn(ctx, w.Pool).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) EnableSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.EnableSubscription"
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET is_active = TRUE, consecutive_failures = 0, disabled_at = NULL
+		WHERE id = $1
+	`
+
+	tag, err := conn(ctx, w.Pool).Exec(ctx, query, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.WebhookSubscription{}, e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	sub, err := w.GetSubscription(ctx, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	return sub, nil
+}
+
+func (w *WebhookRepository) EnqueueDeliveries(ctx context.Context, event domain.Event, body []byte) (int, error) {
+	const op = "WebhookRepository.EnqueueDeliveries"
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, body)
+		SELECT s.id, $1, $2, $3
+		FROM webhook_subscriptions s
+		JOIN webhook_subscription_events se ON se.subscription_id = s.id
+		WHERE s.is_active AND se.event_type = $2
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	tag, err := conn(ctx, w.Pool).Exec(ctx, query, event.Id, string(event.Type), string(body))
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDeliveries skips deliveries of disabled subscriptions; they stay pending
+// and go out once the subscription is enabled again.
+func (w *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]repository.ClaimedDeliveryDTO, error) {
+	const op = "WebhookRepository.ClaimDeliveries"
+
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $1
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT pd.id FROM webhook_deliveries pd
+			JOIN webhook_subscriptions ps ON ps.id = pd.subscription_id
+			WHERE pd.status = 'PENDING' AND pd.next_attempt_at <= NOW() AND ps.is_active
+			ORDER BY pd.id
+			LIMIT $2
+			FOR UPDATE OF pd SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, s.url, s.secret
+	`
+
+	rows, err := conn(ctx, w.Pool).Query(ctx, query, leaseUntil, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	claimed := make([]repository.ClaimedDeliveryDTO, 0)
+	for rows.Next() {
+		var model WebhookDeliveryModel
+		var dto repository.ClaimedDeliveryDTO
+		dest := append(deliveryDest(&model), &dto.URL, &dto.Secret)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		dto.Delivery = toDomainDelivery(model)
+		claimed = append(claimed, dto)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	slices.SortFunc(claimed, func(a, b repository.ClaimedDeliveryDTO) int {
+		return cmp.Compare(a.Delivery.Id, b.Delivery.Id)
+	})
+
+	return claimed, nil
+}
+
+func (w *WebhookRepository) MarkDelivered(ctx context.Context, id int64, responseCode int) error {
+	const op = "WebhookRepository.MarkDelivered"
+
+	query := `
+		WITH d AS (
+			UPDATE webhook_deliveries
+			SET status = 'DELIVERED', delivered_at = NOW(), response_code = $1, last_error = NULL
+			WHERE id = $2
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscriptions s
+		SET consecutive_failures = 0
+		FROM d
+		WHERE s.id = d.subscription_id
+	`
+
+	if _, err := conn(ctx, w.Pool).Exec(ctx, query, responseCode, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) MarkAttemptFailed(ctx context.Context, attempt repository.FailedAttemptDTO, disableAfter int) (bool, error) {
+	const op = "WebhookRepository.MarkAttemptFailed"
+
+	// The self-join reads the subscription as it was before the update, which
+	// tells whether this very failure disabled it.
+	query := `
+		WITH d AS (
+			UPDATE webhook_deliveries
+			SET status = CASE WHEN $3::timestamptz IS NULL THEN 'FAILED' ELSE status END,
+				next_attempt_at = COALESCE($3::timestamptz, next_attempt_at),
+				response_code = $1,
+				last_error = $2
+			WHERE id = $4
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscriptions s
+		SET consecutive_failures = old.consecutive_failures + 1,
+			is_active = old.is_active AND old.consecutive_failures + 1 < $5,
+			disabled_at = CASE
+				WHEN old.is_active AND old.consecutive_failures + 1 >= $5 THEN NOW()
+				ELSE old.disabled_at
+			END
+		FROM d, webhook_subscriptions old
+		WHERE s.id = d.subscription_id AND old.id = s.id
+		RETURNING old.is_active AND NOT s.is_active
+	`
+
+	var disabled bool
+	err := conn(ctx, w.Pool).QueryRow(ctx, query,
+		attempt.ResponseCode, attempt.LastError, attempt.NextAttemptAt, attempt.DeliveryId, disableAfter,
+	).Scan(&disabled)
+	if err != nil {
+		return false, e.Wrap(op, checkGetQueryResult(err, e.ErrWebhookNotFound))
+	}
+
+	return disabled, nil
+}
+
+func (w *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionId int, limit int) ([]domain.WebhookDelivery, error) {
+	const op = "WebhookRepository.GetDeliveries"
+
+	query := `SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1
+		ORDER BY d.id DESC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, w.Pool).Query(ctx, query, subscriptionId, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var model WebhookDeliveryModel
+		if err := rows.Scan(deliveryDest(&model)...); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		deliveries = append(deliveries, toDomainDelivery(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (w *WebhookRepository) getSubscriptions(ctx context.Context, where sq.Sqlizer) ([]domain.WebhookSubscription, error) {
+	builder := sq.Select("id", "url", "secret", "is_active", "consecutive_failures", "created_at", "disabled_at").
+		From("webhook_subscriptions").
+		OrderBy("id")
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, w.Pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []WebhookSubscriptionModel
+	var ids []int
+	for rows.Next() {
+		var model WebhookSubscriptionModel
+		if err := rows.Scan(&model.Id, &model.URL, &model.Secret, &model.IsActive, &model.ConsecutiveFailures, &model.CreatedAt, &model.DisabledAt); err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+		ids = append(ids, model.Id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events, err := w.getSubscriptionEvents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.WebhookSubscription, 0, len(models))
+	for _, model := range models {
+		subs = append(subs, toDomainSubscription(model, events[model.Id]))
+	}
+
+	return subs, nil
+}
+
+func (w *WebhookRepository) getSubscriptionEvents(ctx context.Context, ids []int) (map[int][]domain.EventType, error) {
+	events := make(map[int][]domain.EventType, len(ids))
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	query, args, err := sq.Select("subscription_id", "event_type").
+		From("webhook_subscription_events").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "event_type").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, w.Pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var eventType domain.EventType
+		if err := rows.Scan(&id, &eventType); err != nil {
+			return nil, err
+		}
+		events[id] = append(events[id], eventType)
+	}
+
+	return events, rows.Err()
+}
+
+func deliveryDest(model *WebhookDeliveryModel) []any {
+	return []any{
+		&model.Id, &model.SubscriptionId, &model.EventId, &model.EventType, &model.Body, &model.Status, &model.Attempts,
+		&model.ResponseCode, &model.LastError, &model.CreatedAt, &model.NextAttemptAt, &model.DeliveredAt,
+	}
+}
+
+func toDomainSubscription(model WebhookSubscriptionModel, events []domain.EventType) domain.WebhookSubscription {
+	return domain.WebhookSubscription{
+		Id:                  model.Id,
+		URL:                 model.URL,
+		Events:              events,
+		Secret:              model.Secret,
+		IsActive:            model.IsActive,
+		ConsecutiveFailures: model.ConsecutiveFailures,
+		CreatedAt:           model.CreatedAt,
+		DisabledAt:          model.DisabledAt,
+	}
+}
+
+func toDomainDelivery(model WebhookDeliveryModel) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		Id:             model.Id,
+		SubscriptionId: model.SubscriptionId,
+		EventId:        model.EventId,
+		EventType:      model.EventType,
+		Body:           []byte(model.Body),
+		Status:         domain.DeliveryStatus(model.Status),
+		Attempts:       model.Attempts,
+		ResponseCode:   model.ResponseCode,
+		LastError:      model.LastError,
+		CreatedAt:      model.CreatedAt,
+		NextAttemptAt:  model.NextAttemptAt,
+		DeliveredAt:    model.DeliveredAt,
+	}
+}
@@ -3,21 +3,60 @@ package repository
 import (
 	"avito-internship/internal/domain"
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	UpdateIsActive(ctx context.Context, userId string, isActive bool) (domain.User, error)
 	GetById(ctx context.Context, userId string) (domain.User, error)
-	GetReviewCandidates(ctx context.Context, authorId string, maxCandidates int) ([]domain.User, error)
-	GetReassignCandidates(ctx context.Context, authorId string, excludeIds []string, maxCandidates int) ([]domain.User, error)
+	// List returns a page of matching users ordered by id and the number of all matches.
+	List(ctx context.Context, filter UserFilter) ([]domain.User, int, error)
+	// GetStats counts the user's reviews on PRs in the open status and the PRs they authored.
+	GetStats(ctx context.Context, userId string, openStatusId int) (UserStatsDTO, error)
+	// GetReviewCandidates picks random active members of the team other than the author.
+	GetReviewCandidates(ctx context.Context, teamId int, authorId string, maxCandidates int) ([]domain.User, error)
+	GetReassignCandidates(ctx context.Context, teamId int, excludeIds []string, maxCandidates int) ([]domain.User, error)
+	// AddUsersToTeam creates the users or updates them in place. Users whose
+	// primary team is another one, and who are not yet members, are left
+	// untouched and missing from the result.
 	AddUsersToTeam(ctx context.Context, teamId int, users []domain.User) ([]domain.User, error)
+	// AddMembership joins an existing user to one more team. It becomes the
+	// primary team of users without one.
+	AddMembership(ctx context.Context, teamId int, userId string) (domain.User, error)
+	// MoveToTeam replaces the primary team of the user; other memberships stay.
+	MoveToTeam(ctx context.Context, userId string, teamId int) (domain.User, error)
+	// MoveTeamMembers moves every member of one team to another.
+	MoveTeamMembers(ctx context.Context, fromTeamId, toTeamId int) ([]domain.User, error)
 	DeactivateUsers(ctx context.Context, ids []string) ([]domain.User, error)
+	// RemoveUsersFromTeam detaches the users from the team; they keep their accounts.
+	// Users leaving their primary team fall back to another membership.
+	RemoveUsersFromTeam(ctx context.Context, teamId int, ids []string) ([]domain.User, error)
+	// IsTombstone reports whether the user was created by Anonymize.
+	IsTombstone(ctx context.Context, userId string) (bool, error)
+	// Anonymize replaces the user with an inactive tombstone named pseudonym and
+	// records it in user_tombstones. The tombstone takes over the memberships,
+	// authored PRs and reviews, and the user's row is deleted. The user id fields
+	// of stored events and webhook deliveries name the tombstone instead, and
+	// stored idempotent responses that mention the user are deleted.
+	Anonymize(ctx context.Context, userId, tombstoneId, pseudonym string) (domain.User, error)
 }
 
 type TeamRepository interface {
 	Create(ctx context.Context, team domain.Team) (domain.Team, error)
 	GetMembersByTeamNameWithUsers(ctx context.Context, teamName string) ([]domain.User, error)
+	// GetTeamByUserId returns the primary team of the user.
 	GetTeamByUserId(ctx context.Context, userId string) (domain.Team, error)
+	// GetTeamsByUserId returns every team the user is a member of, by name.
+	GetTeamsByUserId(ctx context.Context, userId string) ([]domain.Team, error)
+	GetById(ctx context.Context, teamId int) (domain.Team, error)
+	GetByName(ctx context.Context, teamName string) (domain.Team, error)
+	Rename(ctx context.Context, teamId int, name string) (domain.Team, error)
+	// GetChildren returns the direct subteams of parentId, by name.
+	GetChildren(ctx context.Context, parentId int) ([]domain.Team, error)
+	// SetParent detaches the team when parentId is 0.
+	SetParent(ctx context.Context, teamId int, parentId int, maxDepth int) (domain.Team, error)
+	// Delete fails while the team has members.
+	Delete(ctx context.Context, teamId int) error
 }
 
 type PullRequestRepository interface {
@@ -25,6 +64,10 @@ type PullRequestRepository interface {
 	SetMergedStatus(ctx context.Context, statusId int, prId string) (SetMergedStatusDTO, error)
 	GetByPrIdWithReviewersIds(ctx context.Context, prId string) (GetByPrIdWithReviewersIdsDTO, error)
 	GetOpenPRsByReviewerIDs(ctx context.Context, prIds []string, statusId int) (map[string]GetOpenPRsByReviewerIDsDTO, error)
+	// GetOpenPRsByAuthorID returns the author's PRs in the status that have reviewers.
+	GetOpenPRsByAuthorID(ctx context.Context, authorId string, statusId int) (map[string]GetOpenPRsByReviewerIDsDTO, error)
+	// GetStatuses returns the status of every pull request in prIds that exists.
+	GetStatuses(ctx context.Context, prIds []string) (map[string]domain.PRStatus, error)
 }
 
 type PrReviewerRepository interface {
@@ -38,3 +81,44 @@ type StatusRepository interface {
 	GetById(ctx context.Context, statusId int) (domain.Status, error)
 	GetByName(ctx context.Context, statusName string) (domain.Status, error)
 }
+
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...domain.Event) error
+	// Claim leases up to limit due events until leaseUntil and increments their attempts.
+	Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]domain.Event, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int) error
+	// EnableSubscription reactivates a subscription and resets its failure counter.
+	EnableSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error)
+	// EnqueueDeliveries queues body for every active subscription to the event type.
+	// Enqueuing the same event twice is a no-op.
+	EnqueueDeliveries(ctx context.Context, event domain.Event, body []byte) (int, error)
+	// ClaimDeliveries leases up to limit due deliveries until leaseUntil and increments their attempts.
+	ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]ClaimedDeliveryDTO, error)
+	// MarkDelivered completes the delivery and resets the subscription failure counter.
+	MarkDelivered(ctx context.Context, id int64, responseCode int) error
+	// MarkAttemptFailed records a failed attempt. The delivery is retried at nextAttemptAt,
+	// or failed for good when it is nil. The subscription is disabled once it has failed
+	// disableAfter times in a row; the result reports whether that happened.
+	MarkAttemptFailed(ctx context.Context, attempt FailedAttemptDTO, disableAfter int) (bool, error)
+	GetDeliveries(ctx context.Context, subscriptionId int, limit int) ([]domain.WebhookDelivery, error)
+}
+
+type IdempotencyRepository interface {
+	// Reserve stores a new in-progress key. An expired key, or one left in
+	// progress since before staleBefore, is taken over. Otherwise the stored key
+	// is returned with reserved set to false.
+	Reserve(ctx context.Context, key domain.IdempotencyKey, staleBefore time.Time) (stored domain.IdempotencyKey, reserved bool, err error)
+	Complete(ctx context.Context, key string, statusCode int, body []byte) error
+	// Release deletes an in-progress key so that the request can be retried.
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
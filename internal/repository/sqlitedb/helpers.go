@@ -0,0 +1,97 @@
+package sqlitedb
+
+import (
+	"avito-internship/pkg/transaction"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction stored in ctx or db when there is none.
+// The pool holds a single connection, so queries issued while a transaction
+// is open must go through that transaction.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, err := transaction.SqlTxFromCtx(ctx); err == nil {
+		return tx
+	}
+
+	return db
+}
+
+// withTx runs fn in the transaction stored in ctx or in a new one when there is none.
+func withTx(ctx context.Context, db *sql.DB, fn func(q querier) error) error {
+	if tx, err := transaction.SqlTxFromCtx(ctx); err == nil {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func sqliteDuplicate(err, errIsExists error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		return errIsExists
+	}
+	return err
+}
+
+func sqliteForeignKeyViolation(err, errNotFound error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
+		return errNotFound
+	}
+
+	return err
+}
+
+func checkGetQueryResult(err, errNotFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errNotFound
+	}
+
+	return err
+}
+
+// derefInt maps a NULL id to zero.
+func derefInt(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// nullableInt maps a zero id to NULL.
+func nullableInt(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match literally in a LIKE pattern escaped with a backslash.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,119 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	DB *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{DB: db}
+}
+
+func (i *IdempotencyRepository) Reserve(ctx context.Context, key domain.IdempotencyKey, staleBefore time.Time) (domain.IdempotencyKey, bool, error) {
+	const op = "IdempotencyRepository.Reserve"
+
+	var (
+		stored   domain.IdempotencyKey
+		reserved bool
+	)
+	err := withTx(ctx, i.DB, func(q querier) error {
+		query := `
+			INSERT INTO idempotency_keys (key, request_hash, created_at, expires_at)
+			VALUES (?1, ?2, ?3, ?4)
+			ON CONFLICT (key) DO UPDATE
+			SET request_hash = excluded.request_hash, status_code = NULL, response_body = NULL,
+			    created_at = excluded.created_at, expires_at = excluded.expires_at
+			WHERE idempotency_keys.expires_at <= excluded.created_at
+			   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at <= ?5)
+			RETURNING key
+		`
+
+		var k string
+		err := q.QueryRowContext(ctx, query, key.Key, key.RequestHash, formatTime(key.CreatedAt), formatTime(key.ExpiresAt), formatTime(staleBefore)).Scan(&k)
+		if err == nil {
+			stored, reserved = key, true
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		var model IdempotencyKeyModel
+		err = q.QueryRowContext(ctx, `
+			SELECT key, request_hash, status_code, response_body, created_at, expires_at
+			FROM idempotency_keys
+			WHERE key = ?
+		`, key.Key).Scan(&model.Key, &model.RequestHash, &model.StatusCode, &model.ResponseBody, &model.CreatedAt, &model.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		stored = toDomainIdempotencyKey(model)
+		return nil
+	})
+	if err != nil {
+		return domain.IdempotencyKey{}, false, e.Wrap(op, err)
+	}
+
+	return stored, reserved, nil
+}
+
+func (i *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, body []byte) error {
+	const op = "IdempotencyRepository.Complete"
+
+	_, err := conn(ctx, i.DB).ExecContext(ctx,
+		`UPDATE idempotency_keys SET status_code = ?, response_body = ? WHERE key = ?`,
+		statusCode, string(body), key)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	const op = "IdempotencyRepository.Release"
+
+	_, err := conn(ctx, i.DB).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ? AND status_code IS NULL`, key)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const op = "IdempotencyRepository.DeleteExpired"
+
+	res, err := conn(ctx, i.DB).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, formatTime(now))
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return res.RowsAffected()
+}
+
+func toDomainIdempotencyKey(model IdempotencyKeyModel) domain.IdempotencyKey {
+	key := domain.IdempotencyKey{
+		Key:         model.Key,
+		RequestHash: model.RequestHash,
+		CreatedAt:   model.CreatedAt,
+		ExpiresAt:   model.ExpiresAt,
+	}
+	if model.StatusCode != nil {
+		key.StatusCode = *model.StatusCode
+	}
+	if model.ResponseBody != nil {
+		key.Body = []byte(*model.ResponseBody)
+	}
+
+	return key
+}
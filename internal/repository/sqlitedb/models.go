@@ -0,0 +1,78 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"time"
+)
+
+type UserModel struct {
+	Id       string `db:"id"`
+	Name     string `db:"name"`
+	IsActive bool   `db:"is_active"`
+	TeamId   *int   `db:"team_id"`
+}
+
+type TeamModel struct {
+	Id       int    `db:"id"`
+	Name     string `db:"name"`
+	ParentId *int   `db:"parent_id"`
+	MaxDepth int    `db:"max_depth"`
+}
+
+type PullRequestModel struct {
+	Id                string     `db:"id"`
+	Name              string     `db:"name"`
+	AuthorId          string     `db:"author_id"`
+	StatusId          int        `db:"status_id"`
+	NeedMoreReviewers bool       `db:"need_more_reviewers"`
+	CreatedAt         time.Time  `db:"created_at"`
+	MergedAt          *time.Time `db:"merged_at"`
+	TeamId            *int       `db:"team_id"`
+}
+
+type StatusModel struct {
+	Id   int             `db:"id"`
+	Name domain.PRStatus `db:"name"`
+}
+
+type EventModel struct {
+	Id        int64            `db:"id"`
+	Type      domain.EventType `db:"event_type"`
+	Payload   string           `db:"payload"`
+	Attempts  int              `db:"attempts"`
+	CreatedAt time.Time        `db:"created_at"`
+}
+
+type WebhookSubscriptionModel struct {
+	Id                  int        `db:"id"`
+	URL                 string     `db:"url"`
+	Secret              string     `db:"secret"`
+	IsActive            bool       `db:"is_active"`
+	ConsecutiveFailures int        `db:"consecutive_failures"`
+	CreatedAt           time.Time  `db:"created_at"`
+	DisabledAt          *time.Time `db:"disabled_at"`
+}
+
+type WebhookDeliveryModel struct {
+	Id             int64            `db:"id"`
+	SubscriptionId int              `db:"subscription_id"`
+	EventId        int64            `db:"event_id"`
+	EventType      domain.EventType `db:"event_type"`
+	Body           string           `db:"body"`
+	Status         string           `db:"status"`
+	Attempts       int              `db:"attempts"`
+	ResponseCode   *int             `db:"response_code"`
+	LastError      *string          `db:"last_error"`
+	CreatedAt      time.Time        `db:"created_at"`
+	NextAttemptAt  time.Time        `db:"next_attempt_at"`
+	DeliveredAt    *time.Time       `db:"delivered_at"`
+}
+
+type IdempotencyKeyModel struct {
+	Key          string    `db:"key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   *int      `db:"status_code"`
+	ResponseBody *string   `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
@@ -0,0 +1,149 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// timeLayout matches CURRENT_TIMESTAMP, so stored times compare as strings.
+const timeLayout = "2006-01-02 15:04:05.000"
+
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// Add writes events in the transaction stored in ctx, so they are committed
+// together with the change they describe.
+func (o *OutboxRepository) Add(ctx context.Context, events ...domain.Event) error {
+	const op = "OutboxRepository.Add"
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	now := formatTime(time.Now())
+	builder := sq.Insert("outbox_events").
+		Columns("event_type", "payload", "created_at", "next_attempt_at")
+
+	for _, event := range events {
+		builder = builder.Values(string(event.Type), string(event.Payload), now, now)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Claim needs no row locks: SQLite serializes writers, so a single UPDATE
+// cannot hand the same event to two dispatchers.
+func (o *OutboxRepository) Claim(ctx context.Context, limit int, leaseUntil time.Time) ([]domain.Event, error) {
+	const op = "OutboxRepository.Claim"
+
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = ?1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?2
+			ORDER BY id
+			LIMIT ?3
+		)
+		RETURNING id, event_type, payload, attempts, created_at
+	`
+
+	rows, err := conn(ctx, o.DB).QueryContext(ctx, query, formatTime(leaseUntil), formatTime(time.Now()), limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.Event, 0)
+	for rows.Next() {
+		var model EventModel
+		if err := rows.Scan(&model.Id, &model.Type, &model.Payload, &model.Attempts, &model.CreatedAt); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		events = append(events, toDomainEvent(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	slices.SortFunc(events, func(a, b domain.Event) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	return events, nil
+}
+
+func (o *OutboxRepository) MarkDelivered(ctx context.Context, id int64) error {
+	const op = "OutboxRepository.MarkDelivered"
+
+	query := `UPDATE outbox_events SET delivered_at = ?, last_error = NULL WHERE id = ?`
+	if _, err := conn(ctx, o.DB).ExecContext(ctx, query, formatTime(time.Now()), id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	const op = "OutboxRepository.Reschedule"
+
+	query := `UPDATE outbox_events SET next_attempt_at = ?, last_error = ? WHERE id = ?`
+	if _, err := conn(ctx, o.DB).ExecContext(ctx, query, formatTime(nextAttemptAt), lastError, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (o *OutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	const op = "OutboxRepository.MarkFailed"
+
+	query := `UPDATE outbox_events SET failed_at = ?, last_error = ? WHERE id = ?`
+	if _, err := conn(ctx, o.DB).ExecContext(ctx, query, formatTime(time.Now()), lastError, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func toDomainEvent(model EventModel) domain.Event {
+	return domain.Event{
+		Id:        model.Id,
+		Type:      model.Type,
+		Payload:   []byte(model.Payload),
+		Attempts:  model.Attempts,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,183 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type PrReviewerRepository struct {
+	DB *sql.DB
+}
+
+func NewPrReviewerRepository(db *sql.DB) *PrReviewerRepository {
+	return &PrReviewerRepository{DB: db}
+}
+
+func (p *PrReviewerRepository) AddReviewers(ctx context.Context, poolRequestId string, reviewersId []string) error {
+	const op = "PrReviewerRepository.AddReviewers"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	builder := sq.Insert("pr_reviewers").
+		Columns("reviewer_id", "pr_id")
+
+	for _, reviewerId := range reviewersId {
+		builder = builder.Values(reviewerId, poolRequestId)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (p *PrReviewerRepository) GetPRByReviewer(ctx context.Context, userId string) (r.GetPRByReviewerDTO, error) {
+	const op = "PrReviewerRepository.GetPRByReviewer"
+
+	builder := sq.Select(
+		"pr.id",
+		"pr.name",
+		"pr.author_id",
+		"pr.status_id",
+		"pr.need_more_reviewers",
+		"pr.created_at",
+		"pr.merged_at",
+		"s.name AS status_name",
+	).
+		From("pull_requests pr").
+		Join("pr_reviewers r ON r.pr_id = pr.id").
+		Join("statuses s ON s.id = pr.status_id").
+		Where(sq.Eq{"r.reviewer_id": userId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, p.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	pullRequests := make([]domain.PullRequest, 0)
+	statusNames := make([]domain.PRStatus, 0)
+
+	for rows.Next() {
+		var (
+			model      PullRequestModel
+			statusName domain.PRStatus
+		)
+		if err := rows.Scan(
+			&model.Id,
+			&model.Name,
+			&model.AuthorId,
+			&model.StatusId,
+			&model.NeedMoreReviewers,
+			&model.CreatedAt,
+			&model.MergedAt,
+			&statusName,
+		); err != nil {
+			return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
+		}
+
+		pullRequests = append(pullRequests, toDomainPR(model))
+		statusNames = append(statusNames, statusName)
+	}
+
+	if err := rows.Err(); err != nil {
+		return r.GetPRByReviewerDTO{}, e.Wrap(op, err)
+	}
+
+	return r.NewGetPRByReviewerDTO(pullRequests, statusNames), nil
+}
+
+func (p *PrReviewerRepository) UpdateReviewer(ctx context.Context, oldUserId string, newUserId string, poolRequestId string) (string, error) {
+	const op = "PrReviewerRepository.UpdateReviewer"
+
+	builder := sq.Update("pr_reviewers").
+		Set("reviewer_id", newUserId).
+		Where(sq.Eq{
+			"reviewer_id": oldUserId,
+			"pr_id":       poolRequestId,
+		}).
+		Suffix("RETURNING reviewer_id")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	var returnedPrID string
+	err = conn(ctx, p.DB).QueryRowContext(ctx, query, args...).Scan(&returnedPrID)
+	if err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	return returnedPrID, nil
+}
+
+func (p *PrReviewerRepository) UpdateReviewers(ctx context.Context, changes map[string]r.PrReviewerChange) error {
+	const op = "PrReviewerRepository.UpdateReviewers"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	delPairs := sq.Or{}
+	for prID, change := range changes {
+		for _, reviewerId := range change.ToRemove {
+			delPairs = append(delPairs, sq.Eq{"pr_id": prID, "reviewer_id": reviewerId})
+		}
+	}
+	if len(delPairs) > 0 {
+		delSQL, args, err := sq.Delete("pr_reviewers").Where(delPairs).ToSql()
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if _, err := tx.ExecContext(ctx, delSQL, args...); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	insertBuilder := sq.Insert("pr_reviewers").
+		Columns("pr_id", "reviewer_id").
+		Suffix("ON CONFLICT DO NOTHING")
+
+	hasInserts := false
+	for prID, change := range changes {
+		for _, reviewerId := range change.ToAdd {
+			insertBuilder = insertBuilder.Values(prID, reviewerId)
+			hasInserts = true
+		}
+	}
+
+	if hasInserts {
+		sqlStr, args, err := insertBuilder.ToSql()
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if _, err := tx.ExecContext(ctx, sqlStr, args...); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
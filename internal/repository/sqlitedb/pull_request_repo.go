@@ -0,0 +1,416 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type PullRequestsRepository struct {
+	DB *sql.DB
+}
+
+func NewPullRequestsRepository(db *sql.DB) *PullRequestsRepository {
+	return &PullRequestsRepository{DB: db}
+}
+
+func (p *PullRequestsRepository) Create(ctx context.Context, pullRequest domain.PullRequest) (domain.PullRequest, error) {
+	const op = "PullRequestsRepository.Create"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return domain.PullRequest{}, e.Wrap(op, err)
+	}
+
+	model := toPRModel(pullRequest)
+	builder := sq.Insert("pull_requests").
+		Columns("id", "name", "author_id", "status_id", "need_more_reviewers", "created_at", "team_id").
+		Values(model.Id, model.Name, model.AuthorId, model.StatusId, model.NeedMoreReviewers, model.CreatedAt, model.TeamId).
+		Suffix("RETURNING id, name, author_id, status_id, need_more_reviewers, team_id")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.PullRequest{}, e.Wrap(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.AuthorId, &model.StatusId, &model.NeedMoreReviewers, &model.TeamId)
+	err = sqliteDuplicate(err, e.ErrPRIsExists)
+	err = sqliteForeignKeyViolation(err, e.ErrUserNotFound)
+	if err != nil {
+		return domain.PullRequest{}, e.Wrap(op, err)
+	}
+
+	return toDomainPR(model), nil
+}
+
+// SetMergedStatus replaces the Postgres RETURNING CTE with an UPDATE followed by
+// a reviewers lookup in the same transaction.
+func (p *PullRequestsRepository) SetMergedStatus(ctx context.Context, statusId int, prId string) (r.SetMergedStatusDTO, error) {
+	const op = "PullRequestsRepository.SetMergedStatus"
+
+	var (
+		upd       PullRequestModel
+		reviewers []string
+	)
+
+	err := withTx(ctx, p.DB, func(q querier) error {
+		res, err := q.ExecContext(ctx,
+			`UPDATE pull_requests SET status_id = ?, merged_at = ? WHERE id = ?`,
+			statusId, time.Now(), prId,
+		)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return e.ErrPRNotFound
+		}
+
+		upd, err = getPR(ctx, q, prId)
+		if err != nil {
+			return err
+		}
+
+		reviewers, err = getReviewersIds(ctx, q, prId)
+		return err
+	})
+	if err != nil {
+		return r.SetMergedStatusDTO{}, e.Wrap(op, err)
+	}
+
+	return r.NewSetMergedStatusDTO(toDomainPR(upd), reviewers), nil
+}
+
+func (p *PullRequestsRepository) GetByPrIdWithReviewersIds(ctx context.Context, prId string) (r.GetByPrIdWithReviewersIdsDTO, error) {
+	const op = "PullRequestsRepository.GetByPrIdWithReviewersIds"
+
+	builder := sq.Select(
+		"pr.id", "pr.name", "pr.author_id", "pr.status_id", "pr.need_more_reviewers", "pr.created_at", "pr.merged_at",
+		"pr.team_id", "COALESCE(pr.team_id, author.team_id)",
+		"s.name AS status_name",
+		"r.reviewer_id",
+	).
+		From("pull_requests AS pr").
+		Join("users AS author ON author.id = pr.author_id").
+		LeftJoin("pr_reviewers AS r ON r.pr_id = pr.id").
+		LeftJoin("statuses AS s ON pr.status_id = s.id").
+		Where(sq.Eq{"pr.id": prId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, p.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var (
+		model        PullRequestModel
+		reviewTeamId *int
+		reviewersIds = make([]string, 0)
+		statusName   domain.PRStatus
+		prFound      = false
+	)
+
+	for rows.Next() {
+		var reviewerId *string
+		if err := rows.Scan(
+			&model.Id,
+			&model.Name,
+			&model.AuthorId,
+			&model.StatusId,
+			&model.NeedMoreReviewers,
+			&model.CreatedAt,
+			&model.MergedAt,
+			&model.TeamId,
+			&reviewTeamId,
+			&statusName,
+			&reviewerId,
+		); err != nil {
+			return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
+		}
+		prFound = true
+
+		if reviewerId != nil {
+			reviewersIds = append(reviewersIds, *reviewerId)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, err)
+	}
+
+	if !prFound {
+		return r.GetByPrIdWithReviewersIdsDTO{}, e.Wrap(op, e.ErrPRNotFound)
+	}
+
+	dto := r.NewGetByPrIdWithReviewersIdsDTO(toDomainPR(model), reviewersIds, statusName)
+	dto.ReviewTeamId = derefInt(reviewTeamId)
+
+	return dto, nil
+}
+
+func (p *PullRequestsRepository) GetOpenPRsByReviewerIDs(ctx context.Context, reviewersIds []string, statusId int) (map[string]r.GetOpenPRsByReviewerIDsDTO, error) {
+	const op = "PullRequestsRepository.GetOpenPRsByReviewerIDs"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	builder := sq.Select(
+		"pr.id", "pr.name", "pr.author_id", "pr.status_id", "s.name",
+		"pr.need_more_reviewers", "pr.created_at", "pr.merged_at", "r_all.reviewer_id",
+		"pr.team_id", "COALESCE(pr.team_id, author.team_id)",
+	).
+		Distinct().
+		From("pull_requests pr").
+		Join("users author ON author.id = pr.author_id").
+		Join("pr_reviewers r_search ON pr.id = r_search.pr_id").
+		Join("pr_reviewers r_all ON pr.id = r_all.pr_id").
+		Join("statuses s ON pr.status_id = s.id").
+		Where(sq.Eq{"r_search.reviewer_id": reviewersIds}).
+		Where(sq.Eq{"pr.status_id": statusId}).
+		OrderBy("pr.id")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	prTempMap := make(map[string]r.GetOpenPRsByReviewerIDsDTO)
+
+	for rows.Next() {
+		var (
+			model        PullRequestModel
+			statusName   string
+			reviewerID   string
+			reviewTeamId *int
+		)
+
+		if err := rows.Scan(
+			&model.Id,
+			&model.Name,
+			&model.AuthorId,
+			&model.StatusId,
+			&statusName,
+			&model.NeedMoreReviewers,
+			&model.CreatedAt,
+			&model.MergedAt,
+			&reviewerID,
+			&model.TeamId,
+			&reviewTeamId,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		dto, exists := prTempMap[model.Id]
+		if !exists {
+			dto = r.GetOpenPRsByReviewerIDsDTO{
+				Pr:           toDomainPR(model),
+				ReviewersIds: []string{},
+				StatusName:   statusName,
+				ReviewTeamId: derefInt(reviewTeamId),
+			}
+		}
+
+		dto.ReviewersIds = append(dto.ReviewersIds, reviewerID)
+		prTempMap[model.Id] = dto
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return prTempMap, nil
+}
+
+func (p *PullRequestsRepository) GetOpenPRsByAuthorID(ctx context.Context, authorId string, statusId int) (map[string]r.GetOpenPRsByReviewerIDsDTO, error) {
+	const op = "PullRequestsRepository.GetOpenPRsByAuthorID"
+
+	builder := sq.Select(
+		"pr.id", "pr.name", "pr.author_id", "pr.status_id", "s.name",
+		"pr.need_more_reviewers", "pr.created_at", "pr.merged_at", "r.reviewer_id",
+		"pr.team_id", "COALESCE(pr.team_id, author.team_id)",
+	).
+		From("pull_requests pr").
+		Join("users author ON author.id = pr.author_id").
+		Join("pr_reviewers r ON pr.id = r.pr_id").
+		Join("statuses s ON pr.status_id = s.id").
+		Where(sq.Eq{"pr.author_id": authorId}).
+		Where(sq.Eq{"pr.status_id": statusId}).
+		OrderBy("pr.id")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, p.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	prs := make(map[string]r.GetOpenPRsByReviewerIDsDTO)
+	for rows.Next() {
+		var (
+			model        PullRequestModel
+			statusName   string
+			reviewerID   string
+			reviewTeamId *int
+		)
+
+		if err := rows.Scan(
+			&model.Id, &model.Name, &model.AuthorId, &model.StatusId, &statusName,
+			&model.NeedMoreReviewers, &model.CreatedAt, &model.MergedAt, &reviewerID,
+			&model.TeamId, &reviewTeamId,
+		); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		dto, exists := prs[model.Id]
+		if !exists {
+			dto = r.GetOpenPRsByReviewerIDsDTO{
+				Pr:           toDomainPR(model),
+				ReviewersIds: []string{},
+				StatusName:   statusName,
+				ReviewTeamId: derefInt(reviewTeamId),
+			}
+		}
+		dto.ReviewersIds = append(dto.ReviewersIds, reviewerID)
+		prs[model.Id] = dto
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return prs, nil
+}
+
+func getPR(ctx context.Context, q querier, prId string) (PullRequestModel, error) {
+	query := `
+		SELECT id, name, author_id, status_id, need_more_reviewers, created_at, merged_at, team_id
+		FROM pull_requests
+		WHERE id = ?
+	`
+
+	var model PullRequestModel
+	err := q.QueryRowContext(ctx, query, prId).Scan(
+		&model.Id, &model.Name, &model.AuthorId, &model.StatusId,
+		&model.NeedMoreReviewers, &model.CreatedAt, &model.MergedAt, &model.TeamId,
+	)
+	if err := checkGetQueryResult(err, e.ErrPRNotFound); err != nil {
+		return PullRequestModel{}, err
+	}
+
+	return model, nil
+}
+
+func getReviewersIds(ctx context.Context, q querier, prId string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `SELECT reviewer_id FROM pr_reviewers WHERE pr_id = ?`, prId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := make([]string, 0)
+	for rows.Next() {
+		var reviewerId string
+		if err := rows.Scan(&reviewerId); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, reviewerId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+func toPRModel(p domain.PullRequest) PullRequestModel {
+	return PullRequestModel{
+		Id:                p.Id,
+		Name:              p.Name,
+		AuthorId:          p.AuthorId,
+		StatusId:          p.StatusId,
+		NeedMoreReviewers: p.NeedMoreReviewers,
+		CreatedAt:         p.CreatedAt,
+		MergedAt:          p.MergedAt,
+		TeamId:            nullableInt(p.TeamId),
+	}
+}
+
+func toDomainPR(p PullRequestModel) domain.PullRequest {
+	return domain.PullRequest{
+		Id:                p.Id,
+		Name:              p.Name,
+		AuthorId:          p.AuthorId,
+		StatusId:          p.StatusId,
+		NeedMoreReviewers: p.NeedMoreReviewers,
+		CreatedAt:         p.CreatedAt,
+		MergedAt:          p.MergedAt,
+		TeamId:            derefInt(p.TeamId),
+	}
+}
+
+func (p *PullRequestsRepository) GetStatuses(ctx context.Context, prIds []string) (map[string]domain.PRStatus, error) {
+	const op = "PullRequestsRepository.GetStatuses"
+
+	statuses := make(map[string]domain.PRStatus, len(prIds))
+	if len(prIds) == 0 {
+		return statuses, nil
+	}
+
+	query, args, err := sq.Select("pr.id", "s.name").
+		From("pull_requests pr").
+		Join("statuses s ON pr.status_id = s.id").
+		Where(sq.Eq{"pr.id": prIds}).
+		ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, p.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var status domain.PRStatus
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		statuses[id] = status
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,53 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"context"
+	"database/sql"
+)
+
+type StatusRepo struct {
+	DB *sql.DB
+}
+
+func NewStatusRepo(db *sql.DB) *StatusRepo {
+	return &StatusRepo{
+		DB: db,
+	}
+}
+
+func (s *StatusRepo) GetById(ctx context.Context, statusId int) (domain.Status, error) {
+	const op = "StatusRepo.GetById"
+
+	query := `SELECT id, name FROM statuses WHERE id = ?`
+
+	var model StatusModel
+	err := conn(ctx, s.DB).QueryRowContext(ctx, query, statusId).Scan(&model.Id, &model.Name)
+	if err = checkGetQueryResult(err, e.ErrStatusNotFound); err != nil {
+		return domain.Status{}, e.Wrap(op, err)
+	}
+
+	return toDomainStatus(model), nil
+}
+
+func toDomainStatus(status StatusModel) domain.Status {
+	return domain.Status{
+		Id:   status.Id,
+		Name: status.Name,
+	}
+}
+
+func (s *StatusRepo) GetByName(ctx context.Context, statusName string) (domain.Status, error) {
+	const op = "StatusRepo.GetByName"
+
+	query := `SELECT id, name FROM statuses WHERE name = ?`
+
+	var model StatusModel
+	err := conn(ctx, s.DB).QueryRowContext(ctx, query, statusName).Scan(&model.Id, &model.Name)
+	if err = checkGetQueryResult(err, e.ErrStatusNotFound); err != nil {
+		return domain.Status{}, e.Wrap(op, err)
+	}
+
+	return toDomainStatus(model), nil
+}
@@ -0,0 +1,332 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var teamColumns = []string{"id", "name", "parent_id", "max_depth"}
+
+type TeamRepository struct {
+	DB *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{DB: db}
+}
+
+func (t *TeamRepository) Create(ctx context.Context, team domain.Team) (domain.Team, error) {
+	const op = "TeamRepository.Create"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	model := toTeamModel(team)
+	queryBuilder := sq.Insert("teams").
+		Columns("name").
+		Values(model.Name).
+		Suffix("RETURNING id, name")
+
+	teamQuery, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, teamQuery, args...).Scan(&model.Id, &model.Name)
+	if err = sqliteDuplicate(err, e.ErrTeamIsExists); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetMembersByTeamNameWithUsers(ctx context.Context, teamName string) ([]domain.User, error) {
+	const op = "TeamRepository.GetMembersByTeamNameWithUsers"
+
+	builder := sq.Select(
+		"users.id", "users.name", "users.is_active", "users.team_id",
+	).
+		From("teams").
+		LeftJoin("team_members tm ON tm.team_id = teams.id").
+		LeftJoin("users ON users.id = tm.user_id").
+		Where(sq.Eq{"teams.name": teamName})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, t.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var (
+		usersModel = make([]UserModel, 0)
+		teamFound  bool
+	)
+
+	for rows.Next() {
+		teamFound = true
+
+		var (
+			uId       *string
+			uName     *string
+			uIsActive *bool
+			uTeamId   *int
+		)
+
+		err := rows.Scan(
+			&uId, &uName, &uIsActive, &uTeamId,
+		)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		if uId != nil {
+			usersModel = append(usersModel, UserModel{
+				Id:       *uId,
+				Name:     *uName,
+				IsActive: *uIsActive,
+				TeamId:   uTeamId,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if !teamFound {
+		return nil, e.Wrap(op, e.ErrTeamNotFound)
+	}
+
+	return toArrDomainUser(usersModel), nil
+}
+
+func (t *TeamRepository) GetTeamByUserId(ctx context.Context, userId string) (domain.Team, error) {
+	const op = "TeamRepository.GetTeamByUserId"
+
+	builder := sq.Select("teams.id", "teams.name").
+		From("teams").
+		Join("users ON teams.id = users.team_id").
+		Where(sq.Eq{"users.id": userId}).
+		Limit(1)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetTeamsByUserId(ctx context.Context, userId string) ([]domain.Team, error) {
+	const op = "TeamRepository.GetTeamsByUserId"
+
+	builder := sq.Select("teams.id", "teams.name").
+		From("teams").
+		Join("team_members tm ON tm.team_id = teams.id").
+		Where(sq.Eq{"tm.user_id": userId}).
+		OrderBy("teams.name")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, t.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	teams := make([]domain.Team, 0)
+	for rows.Next() {
+		var model TeamModel
+		if err := rows.Scan(&model.Id, &model.Name); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		teams = append(teams, toDomainTeam(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return teams, nil
+}
+
+func (t *TeamRepository) GetById(ctx context.Context, teamId int) (domain.Team, error) {
+	const op = "TeamRepository.GetById"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"id": teamId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetByName(ctx context.Context, teamName string) (domain.Team, error) {
+	const op = "TeamRepository.GetByName"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"name": teamName})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) Rename(ctx context.Context, teamId int, name string) (domain.Team, error) {
+	const op = "TeamRepository.Rename"
+
+	builder := sq.Update("teams").
+		Set("name", name).
+		Where(sq.Eq{"id": teamId}).
+		Suffix("RETURNING id, name, parent_id, max_depth")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err = sqliteDuplicate(err, e.ErrTeamIsExists); err != nil {
+		return domain.Team{}, e.Wrap(op, checkGetQueryResult(err, e.ErrTeamNotFound))
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) GetChildren(ctx context.Context, parentId int) ([]domain.Team, error) {
+	const op = "TeamRepository.GetChildren"
+
+	builder := sq.Select(teamColumns...).
+		From("teams").
+		Where(sq.Eq{"parent_id": parentId}).
+		OrderBy("name")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rows, err := conn(ctx, t.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	teams := make([]domain.Team, 0)
+	for rows.Next() {
+		var model TeamModel
+		if err := rows.Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		teams = append(teams, toDomainTeam(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return teams, nil
+}
+
+func (t *TeamRepository) SetParent(ctx context.Context, teamId int, parentId int, maxDepth int) (domain.Team, error) {
+	const op = "TeamRepository.SetParent"
+
+	builder := sq.Update("teams").
+		Set("parent_id", nullableInt(parentId)).
+		Set("max_depth", maxDepth).
+		Where(sq.Eq{"id": teamId}).
+		Suffix("RETURNING id, name, parent_id, max_depth")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	var model TeamModel
+	err = conn(ctx, t.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.ParentId, &model.MaxDepth)
+	if err := checkGetQueryResult(err, e.ErrTeamNotFound); err != nil {
+		return domain.Team{}, e.Wrap(op, err)
+	}
+
+	return toDomainTeam(model), nil
+}
+
+func (t *TeamRepository) Delete(ctx context.Context, teamId int) error {
+	const op = "TeamRepository.Delete"
+
+	res, err := conn(ctx, t.DB).ExecContext(ctx, `DELETE FROM teams WHERE id = ?`, teamId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if affected == 0 {
+		return e.Wrap(op, e.ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+func toDomainTeam(model TeamModel) domain.Team {
+	return domain.Team{
+		Id:       model.Id,
+		Name:     model.Name,
+		ParentId: derefInt(model.ParentId),
+		MaxDepth: model.MaxDepth,
+	}
+}
+
+func toTeamModel(team domain.Team) TeamModel {
+	return TeamModel{
+		Id:       team.Id,
+		Name:     team.Name,
+		ParentId: nullableInt(team.ParentId),
+		MaxDepth: team.MaxDepth,
+	}
+}
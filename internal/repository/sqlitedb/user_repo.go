@@ -0,0 +1,586 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type UserRepository struct {
+	DB *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{DB: db}
+}
+
+func (u *UserRepository) UpdateIsActive(ctx context.Context, userId string, isActive bool) (domain.User, error) {
+	const op = "UserRepository.UpdateIsActive"
+
+	queryBuilder := sq.Update("users").
+		Set("is_active", isActive).
+		Where(sq.Eq{"id": userId}).
+		Suffix("RETURNING id, name, is_active, team_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	var updModel UserModel
+	err = conn(ctx, u.DB).QueryRowContext(ctx, query, args...).Scan(&updModel.Id, &updModel.Name, &updModel.IsActive, &updModel.TeamId)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(updModel), nil
+}
+
+func (u *UserRepository) GetById(ctx context.Context, userId string) (domain.User, error) {
+	const op = "UserRepository.GetById"
+
+	builder := sq.Select("id, name, is_active, team_id").
+		From("users").
+		Where(sq.Eq{"id": userId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	var model UserModel
+	err = conn(ctx, u.DB).QueryRowContext(ctx, query, args...).Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+	if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+func (u *UserRepository) List(ctx context.Context, filter r.UserFilter) ([]domain.User, int, error) {
+	const op = "UserRepository.List"
+
+	where := sq.And{}
+	if filter.Query != "" {
+		where = append(where, sq.Or{
+			sq.Expr(`users.id LIKE ? ESCAPE '\'`, escapeLike(filter.Query)+"%"),
+			sq.Expr(`users.name LIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Query)+"%"),
+		})
+	}
+	if filter.TeamId != 0 {
+		where = append(where, sq.Expr("EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = users.id AND tm.team_id = ?)", filter.TeamId))
+	}
+	if filter.IsActive != nil {
+		where = append(where, sq.Eq{"users.is_active": *filter.IsActive})
+	}
+
+	countQuery, args, err := sq.Select("COUNT(*)").From("users").Where(where).ToSql()
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	var total int
+	if err := conn(ctx, u.DB).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	builder := sq.Select("users.id", "users.name", "users.is_active", "users.team_id").
+		From("users").
+		Where(where).
+		OrderBy("users.id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	models, err := queryUsers(ctx, conn(ctx, u.DB), query, args...)
+	if err != nil {
+		return nil, 0, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(models), total, nil
+}
+
+func (u *UserRepository) GetStats(ctx context.Context, userId string, openStatusId int) (r.UserStatsDTO, error) {
+	const op = "UserRepository.GetStats"
+
+	query := `
+       SELECT
+           (SELECT COUNT(*) FROM pr_reviewers r
+            JOIN pull_requests pr ON pr.id = r.pr_id
+            WHERE r.reviewer_id = ?1 AND pr.status_id = ?2),
+           (SELECT COUNT(*) FROM pull_requests WHERE author_id = ?1)
+    `
+
+	var stats r.UserStatsDTO
+	err := conn(ctx, u.DB).QueryRowContext(ctx, query, userId, openStatusId).Scan(&stats.OpenReviews, &stats.AuthoredPrs)
+	if err != nil {
+		return r.UserStatsDTO{}, e.Wrap(op, err)
+	}
+
+	return stats, nil
+}
+
+func (u *UserRepository) GetReviewCandidates(ctx context.Context, teamId int, authorId string, maxReviewers int) ([]domain.User, error) {
+	const op = "UserRepository.GetReviewCandidates"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	query := `
+       SELECT users.id, users.name, users.is_active, users.team_id
+       FROM users
+       JOIN team_members tm ON tm.user_id = users.id
+       WHERE
+           tm.team_id = ?1
+           AND users.is_active = TRUE
+           AND users.id != ?2
+       ORDER BY RANDOM()
+       LIMIT ?3
+    `
+
+	models, err := queryUsers(ctx, tx, query, teamId, authorId, maxReviewers)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(models), nil
+}
+
+func (u *UserRepository) GetReassignCandidates(ctx context.Context, teamId int, excludeIds []string, maxCandidates int) ([]domain.User, error) {
+	const op = "UserRepository.GetReassignCandidates"
+
+	builder := sq.Select("users.id", "users.name", "users.is_active", "users.team_id").
+		From("users").
+		Join("team_members tm ON tm.user_id = users.id").
+		Where(sq.Eq{"tm.team_id": teamId}).
+		Where(sq.Eq{"users.is_active": true}).
+		Where(sq.NotEq{"users.id": excludeIds}).
+		OrderBy("RANDOM()").
+		Limit(uint64(maxCandidates))
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	candidates, err := queryUsers(ctx, conn(ctx, u.DB), query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(candidates), nil
+}
+
+func (u *UserRepository) AddUsersToTeam(ctx context.Context, teamId int, users []domain.User) ([]domain.User, error) {
+	const op = "UserRepository.AddUsersToTeam"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	builder := sq.Insert("users").
+		Columns("id", "name", "is_active", "team_id").
+		Suffix(`ON CONFLICT (id) DO UPDATE
+		SET
+			name = excluded.name,
+			is_active = excluded.is_active,
+			team_id = COALESCE(users.team_id, excluded.team_id)
+		WHERE users.team_id IS NULL OR users.team_id = excluded.team_id
+			OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = excluded.team_id AND tm.user_id = users.id)
+		RETURNING id, name, is_active, team_id`)
+
+	for _, user := range users {
+		builder = builder.Values(user.Id, user.Name, user.IsActive, teamId)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	models, err := queryUsers(ctx, tx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if len(models) > 0 {
+		memberships := sq.Insert("team_members").
+			Columns("team_id", "user_id").
+			Suffix("ON CONFLICT DO NOTHING")
+		for _, model := range models {
+			memberships = memberships.Values(teamId, model.Id)
+		}
+
+		query, args, err := memberships.ToSql()
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	return toArrDomainUser(models), nil
+}
+
+func (u *UserRepository) AddMembership(ctx context.Context, teamId int, userId string) (domain.User, error) {
+	const op = "UserRepository.AddMembership"
+
+	var model UserModel
+	err := withTx(ctx, u.DB, func(q querier) error {
+		err := q.QueryRowContext(ctx, `
+			UPDATE users SET team_id = COALESCE(team_id, ?)
+			WHERE id = ?
+			RETURNING id, name, is_active, team_id`, teamId, userId).
+			Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+		if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx, `INSERT INTO team_members (team_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, teamId, userId)
+		return sqliteForeignKeyViolation(err, e.ErrTeamNotFound)
+	})
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+func (u *UserRepository) DeactivateUsers(ctx context.Context, ids []string) ([]domain.User, error) {
+	const op = "UserRepository.DeactivateTeamMembers"
+
+	tx, err := transaction.SqlTxFromCtx(ctx)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	queryBuilder := sq.Update("users").
+		Set("is_active", false).
+		Where(sq.Eq{"id": ids}).
+		Suffix("RETURNING id, name, is_active, team_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	users, err := queryUsers(ctx, tx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(users), nil
+}
+
+func (u *UserRepository) MoveToTeam(ctx context.Context, userId string, teamId int) (domain.User, error) {
+	const op = "UserRepository.MoveToTeam"
+
+	var model UserModel
+	err := withTx(ctx, u.DB, func(q querier) error {
+		_, err := q.ExecContext(ctx, `
+			DELETE FROM team_members
+			WHERE user_id = ?1 AND team_id = (SELECT team_id FROM users WHERE id = ?1)`, userId)
+		if err != nil {
+			return err
+		}
+
+		err = q.QueryRowContext(ctx, `UPDATE users SET team_id = ? WHERE id = ? RETURNING id, name, is_active, team_id`, teamId, userId).
+			Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+		if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx, `INSERT INTO team_members (team_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, teamId, userId)
+		return err
+	})
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+func (u *UserRepository) MoveTeamMembers(ctx context.Context, fromTeamId, toTeamId int) ([]domain.User, error) {
+	const op = "UserRepository.MoveTeamMembers"
+
+	var users []UserModel
+	err := withTx(ctx, u.DB, func(q querier) error {
+		_, err := q.ExecContext(ctx, `
+			INSERT INTO team_members (team_id, user_id)
+			SELECT ?, user_id FROM team_members WHERE team_id = ?
+			ON CONFLICT DO NOTHING`, toTeamId, fromTeamId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, `UPDATE users SET team_id = ? WHERE team_id = ?`, toTeamId, fromTeamId); err != nil {
+			return err
+		}
+
+		users, err = queryUsers(ctx, q, `
+			SELECT users.id, users.name, users.is_active, users.team_id
+			FROM users
+			JOIN team_members tm ON tm.user_id = users.id
+			WHERE tm.team_id = ?`, fromTeamId)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = ?`, fromTeamId)
+		return err
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(users), nil
+}
+
+func (u *UserRepository) RemoveUsersFromTeam(ctx context.Context, teamId int, ids []string) ([]domain.User, error) {
+	const op = "UserRepository.RemoveUsersFromTeam"
+
+	members := sq.Select("users.id", "users.name", "users.is_active", "users.team_id").
+		From("users").
+		Join("team_members tm ON tm.user_id = users.id").
+		Where(sq.Eq{"tm.team_id": teamId, "users.id": ids})
+
+	leave := sq.Delete("team_members").
+		Where(sq.Eq{"team_id": teamId, "user_id": ids})
+
+	// Users leaving their primary team fall back to another membership, if any.
+	primary := sq.Update("users").
+		Set("team_id", sq.Expr("(SELECT MIN(tm.team_id) FROM team_members tm WHERE tm.user_id = users.id)")).
+		Where(sq.Eq{"id": ids, "team_id": teamId}).
+		Suffix("RETURNING id, name, is_active, team_id")
+
+	var users []UserModel
+	err := withTx(ctx, u.DB, func(q querier) error {
+		query, args, err := members.ToSql()
+		if err != nil {
+			return err
+		}
+
+		if users, err = queryUsers(ctx, q, query, args...); err != nil {
+			return err
+		}
+
+		query, args, err = leave.ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+
+		query, args, err = primary.ToSql()
+		if err != nil {
+			return err
+		}
+
+		updated, err := queryUsers(ctx, q, query, args...)
+		if err != nil {
+			return err
+		}
+
+		for _, upd := range updated {
+			for i := range users {
+				if users[i].Id == upd.Id {
+					users[i].TeamId = upd.TeamId
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArrDomainUser(users), nil
+}
+
+// queryUsers scans rows of (id, name, is_active, team_id).
+func queryUsers(ctx context.Context, q querier, query string, args ...any) ([]UserModel, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]UserModel, 0)
+	for rows.Next() {
+		var m UserModel
+		if err := rows.Scan(&m.Id, &m.Name, &m.IsActive, &m.TeamId); err != nil {
+			return nil, err
+		}
+		users = append(users, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (u *UserRepository) IsTombstone(ctx context.Context, userId string) (bool, error) {
+	const op = "UserRepository.IsTombstone"
+
+	var exists bool
+	err := conn(ctx, u.DB).QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_tombstones WHERE user_id = ?)`, userId).Scan(&exists)
+	if err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return exists, nil
+}
+
+func (u *UserRepository) Anonymize(ctx context.Context, userId, tombstoneId, pseudonym string) (domain.User, error) {
+	const op = "UserRepository.Anonymize"
+
+	var model UserModel
+	err := withTx(ctx, u.DB, func(q querier) error {
+		err := q.QueryRowContext(ctx, `
+			INSERT INTO users (id, name, team_id, is_active)
+			SELECT ?2, ?3, team_id, false FROM users WHERE id = ?1
+			RETURNING id, name, is_active, team_id`, userId, tombstoneId, pseudonym).
+			Scan(&model.Id, &model.Name, &model.IsActive, &model.TeamId)
+		if err := checkGetQueryResult(err, e.ErrUserNotFound); err != nil {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, `INSERT INTO user_tombstones (user_id) VALUES (?)`, tombstoneId); err != nil {
+			return err
+		}
+
+		// The foreign keys have no ON UPDATE CASCADE, so the references move to
+		// the tombstone before the user's row goes.
+		for _, query := range []string{
+			`UPDATE team_members SET user_id = ?2 WHERE user_id = ?1`,
+			`UPDATE pull_requests SET author_id = ?2 WHERE author_id = ?1`,
+			`UPDATE pr_reviewers SET reviewer_id = ?2 WHERE reviewer_id = ?1`,
+		} {
+			if _, err := q.ExecContext(ctx, query, userId, tombstoneId); err != nil {
+				return err
+			}
+		}
+
+		if _, err := q.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userId); err != nil {
+			return err
+		}
+
+		// Stored events and webhook deliveries name the user by id and now name
+		// the tombstone. Stored responses may carry the name as well, so they are
+		// dropped.
+		for _, queries := range [][2]string{
+			{`SELECT id, payload FROM outbox_events WHERE instr(payload, ?) > 0`,
+				`UPDATE outbox_events SET payload = ?2 WHERE id = ?1`},
+			{`SELECT id, body FROM webhook_deliveries WHERE instr(body, ?) > 0`,
+				`UPDATE webhook_deliveries SET body = ?2 WHERE id = ?1`},
+		} {
+			if err := replaceUserId(ctx, q, queries[0], queries[1], userId, tombstoneId); err != nil {
+				return err
+			}
+		}
+
+		_, err = q.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE instr(response_body, ?) > 0`, jsonString(userId))
+		return err
+	})
+	if err != nil {
+		return domain.User{}, e.Wrap(op, err)
+	}
+
+	return toDomainUser(model), nil
+}
+
+// replaceUserId renames the user in the JSON documents that selectQuery returns
+// as (id, document) and writes the changed ones back with updateQuery.
+func replaceUserId(ctx context.Context, q querier, selectQuery, updateQuery, oldId, newId string) error {
+	docs, err := jsonDocuments(ctx, q, selectQuery, jsonString(oldId))
+	if err != nil {
+		return err
+	}
+
+	for id, doc := range docs {
+		res, changed, err := domain.ReplaceUserId([]byte(doc), oldId, newId)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		if _, err := q.ExecContext(ctx, updateQuery, id, string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func jsonDocuments(ctx context.Context, q querier, query string, args ...any) (map[int64]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id  int64
+			doc string
+		)
+		if err := rows.Scan(&id, &doc); err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+
+	return docs, rows.Err()
+}
+
+// jsonString quotes s the way ids appear in stored JSON.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func toDomainUser(u UserModel) domain.User {
+	user := domain.User{
+		Id:       u.Id,
+		Name:     u.Name,
+		IsActive: u.IsActive,
+	}
+	if u.TeamId != nil {
+		user.TeamId = *u.TeamId
+	}
+
+	return user
+}
+
+func toArrDomainUser(u []UserModel) []domain.User {
+	users := make([]domain.User, 0, len(u))
+	for _, user := range u {
+		users = append(users, toDomainUser(user))
+	}
+
+	return users
+}
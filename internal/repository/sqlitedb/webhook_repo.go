@@ -0,0 +1,482 @@
+package sqlitedb
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, body, status, attempts,
+	response_code, last_error, created_at, next_attempt_at, delivered_at`
+
+type WebhookRepository struct {
+	DB *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+func (w *WebhookRepository) CreateSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.CreateSubscription"
+
+	var model WebhookSubscriptionModel
+	err := withTx(ctx, w.DB, func(q querier) error {
+		query := `
+			INSERT INTO webhook_subscriptions (url, secret, is_active, created_at)
+			VALUES (?, ?, ?, ?)
+			RETURNING id, url, secret, is_active, consecutive_failures, created_at, disabled_at
+		`
+
+		err := q.QueryRowContext(ctx, query, sub.URL, sub.Secret, sub.IsActive, formatTime(time.Now())).
+			Scan(&model.Id, &model.URL, &model.Secret, &model.IsActive, &model.ConsecutiveFailures, &model.CreatedAt, &model.DisabledAt)
+		if err != nil {
+			return err
+		}
+
+		builder := sq.Insert("webhook_subscription_events").
+			Columns("subscription_id", "event_type")
+		for _, eventType := range sub.Events {
+			builder = builder.Values(model.Id, string(eventType))
+		}
+
+		insertQuery, args, err := builder.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx, insertQuery, args...)
+		return err
+	})
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	return toDomainSubscription(model, sub.Events), nil
+}
+
+func (w *WebhookRepository) GetSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.GetSubscription"
+
+	subs, err := w.getSubscriptions(ctx, sq.Eq{"id": id})
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	if len(subs) == 0 {
+		return domain.WebhookSubscription{}, e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	return subs[0], nil
+}
+
+func (w *WebhookRepository) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.GetSubscriptions"
+
+	subs, err := w.getSubscriptions(ctx, nil)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return subs, nil
+}
+
+func (w *WebhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	const op = "WebhookRepository.DeleteSubscription"
+
+	res, err := conn(ctx, w.DB).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if affected == 0 {
+		return e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) EnableSubscription(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	const op = "WebhookRepository.EnableSubscription"
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET is_active = TRUE, consecutive_failures = 0, disabled_at = NULL
+		WHERE id = ?
+	`
+
+	res, err := conn(ctx, w.DB).ExecContext(ctx, query, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	if affected == 0 {
+		return domain.WebhookSubscription{}, e.Wrap(op, e.ErrWebhookNotFound)
+	}
+
+	sub, err := w.GetSubscription(ctx, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, e.Wrap(op, err)
+	}
+
+	return sub, nil
+}
+
+func (w *WebhookRepository) EnqueueDeliveries(ctx context.Context, event domain.Event, body []byte) (int, error) {
+	const op = "WebhookRepository.EnqueueDeliveries"
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, body, created_at, next_attempt_at)
+		SELECT s.id, ?1, ?2, ?3, ?4, ?4
+		FROM webhook_subscriptions s
+		JOIN webhook_subscription_events se ON se.subscription_id = s.id
+		WHERE s.is_active AND se.event_type = ?2
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	res, err := conn(ctx, w.DB).ExecContext(ctx, query, event.Id, string(event.Type), string(body), formatTime(time.Now()))
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return int(affected), nil
+}
+
+// ClaimDeliveries skips deliveries of disabled subscriptions; they stay pending
+// and go out once the subscription is enabled again.
+func (w *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]repository.ClaimedDeliveryDTO, error) {
+	const op = "WebhookRepository.ClaimDeliveries"
+
+	claimed := make([]repository.ClaimedDeliveryDTO, 0)
+	// RETURNING cannot read joined tables in SQLite, so the due deliveries and
+	// their subscriptions are selected first within the same transaction.
+	err := withTx(ctx, w.DB, func(q querier) error {
+		query := `
+			SELECT d.id, s.url, s.secret FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'PENDING' AND d.next_attempt_at <= ? AND s.is_active
+			ORDER BY d.id
+			LIMIT ?
+		`
+
+		rows, err := q.QueryContext(ctx, query, formatTime(time.Now()), limit)
+		if err != nil {
+			return err
+		}
+
+		targets := make(map[int64]repository.ClaimedDeliveryDTO)
+		var ids []int64
+		for rows.Next() {
+			var id int64
+			var dto repository.ClaimedDeliveryDTO
+			if err := rows.Scan(&id, &dto.URL, &dto.Secret); err != nil {
+				rows.Close()
+				return err
+			}
+			targets[id] = dto
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		updateQuery, args, err := sq.Update("webhook_deliveries").
+			Set("attempts", sq.Expr("attempts + 1")).
+			Set("next_attempt_at", formatTime(leaseUntil)).
+			Where(sq.Eq{"id": ids}).
+			Suffix("RETURNING " + deliveryColumns).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		rows, err = q.QueryContext(ctx, updateQuery, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var model WebhookDeliveryModel
+			if err := rows.Scan(deliveryDest(&model)...); err != nil {
+				return err
+			}
+			dto := targets[model.Id]
+			dto.Delivery = toDomainDelivery(model)
+			claimed = append(claimed, dto)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	slices.SortFunc(claimed, func(a, b repository.ClaimedDeliveryDTO) int {
+		return cmp.Compare(a.Delivery.Id, b.Delivery.Id)
+	})
+
+	return claimed, nil
+}
+
+func (w *WebhookRepository) MarkDelivered(ctx context.Context, id int64, responseCode int) error {
+	const op = "WebhookRepository.MarkDelivered"
+
+	err := withTx(ctx, w.DB, func(q querier) error {
+		query := `
+			UPDATE webhook_deliveries
+			SET status = 'DELIVERED', delivered_at = ?, response_code = ?, last_error = NULL
+			WHERE id = ?
+			RETURNING subscription_id
+		`
+
+		var subscriptionId int
+		if err := q.QueryRowContext(ctx, query, formatTime(time.Now()), responseCode, id).Scan(&subscriptionId); err != nil {
+			return err
+		}
+
+		_, err := q.ExecContext(ctx, `UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = ?`, subscriptionId)
+		return err
+	})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepository) MarkAttemptFailed(ctx context.Context, attempt repository.FailedAttemptDTO, disableAfter int) (bool, error) {
+	const op = "WebhookRepository.MarkAttemptFailed"
+
+	status := string(domain.DeliveryPending)
+	var nextAttemptAt *string
+	if attempt.NextAttemptAt != nil {
+		next := formatTime(*attempt.NextAttemptAt)
+		nextAttemptAt = &next
+	} else {
+		status = string(domain.DeliveryFailed)
+	}
+
+	var disabled bool
+	err := withTx(ctx, w.DB, func(q querier) error {
+		query := `
+			UPDATE webhook_deliveries
+			SET status = ?, next_attempt_at = COALESCE(?, next_attempt_at), response_code = ?, last_error = ?
+			WHERE id = ?
+			RETURNING subscription_id
+		`
+
+		var subscriptionId int
+		err := q.QueryRowContext(ctx, query, status, nextAttemptAt, attempt.ResponseCode, attempt.LastError, attempt.DeliveryId).
+			Scan(&subscriptionId)
+		if err != nil {
+			return checkGetQueryResult(err, e.ErrWebhookNotFound)
+		}
+
+		var isActive bool
+		var failures int
+		err = q.QueryRowContext(ctx, `SELECT is_active, consecutive_failures FROM webhook_subscriptions WHERE id = ?`, subscriptionId).
+			Scan(&isActive, &failures)
+		if err != nil {
+			return err
+		}
+
+		failures++
+		disabled = isActive && failures >= disableAfter
+		if !disabled {
+			_, err = q.ExecContext(ctx, `UPDATE webhook_subscriptions SET consecutive_failures = ? WHERE id = ?`, failures, subscriptionId)
+			return err
+		}
+
+		query = `
+			UPDATE webhook_subscriptions
+			SET consecutive_failures = ?, is_active = FALSE, disabled_at = ?
+			WHERE id = ?
+		`
+		_, err = q.ExecContext(ctx, query, failures, formatTime(time.Now()), subscriptionId)
+		return err
+	})
+	if err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return disabled, nil
+}
+
+func (w *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionId int, limit int) ([]domain.WebhookDelivery, error) {
+	const op = "WebhookRepository.GetDeliveries"
+
+	query := `SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := conn(ctx, w.DB).QueryContext(ctx, query, subscriptionId, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var model WebhookDeliveryModel
+		if err := rows.Scan(deliveryDest(&model)...); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		deliveries = append(deliveries, toDomainDelivery(model))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (w *WebhookRepository) getSubscriptions(ctx context.Context, where sq.Sqlizer) ([]domain.WebhookSubscription, error) {
+	builder := sq.Select("id", "url", "secret", "is_active", "consecutive_failures", "created_at", "disabled_at").
+		From("webhook_subscriptions").
+		OrderBy("id")
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, w.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []WebhookSubscriptionModel
+	var ids []int
+	for rows.Next() {
+		var model WebhookSubscriptionModel
+		if err := rows.Scan(&model.Id, &model.URL, &model.Secret, &model.IsActive, &model.ConsecutiveFailures, &model.CreatedAt, &model.DisabledAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		models = append(models, model)
+		ids = append(ids, model.Id)
+	}
+
+	// The rows must be closed before the next query: the pool holds a single connection.
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events, err := w.getSubscriptionEvents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]domain.WebhookSubscription, 0, len(models))
+	for _, model := range models {
+		subs = append(subs, toDomainSubscription(model, events[model.Id]))
+	}
+
+	return subs, nil
+}
+
+func (w *WebhookRepository) getSubscriptionEvents(ctx context.Context, ids []int) (map[int][]domain.EventType, error) {
+	events := make(map[int][]domain.EventType, len(ids))
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	query, args, err := sq.Select("subscription_id", "event_type").
+		From("webhook_subscription_events").
+		Where(sq.Eq{"subscription_id": ids}).
+		OrderBy("subscription_id", "event_type").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, w.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var eventType domain.EventType
+		if err := rows.Scan(&id, &eventType); err != nil {
+			return nil, err
+		}
+		events[id] = append(events[id], eventType)
+	}
+
+	return events, rows.Err()
+}
+
+func deliveryDest(model *WebhookDeliveryModel) []any {
+	return []any{
+		&model.Id, &model.SubscriptionId, &model.EventId, &model.EventType, &model.Body, &model.Status, &model.Attempts,
+		&model.ResponseCode, &model.LastError, &model.CreatedAt, &model.NextAttemptAt, &model.DeliveredAt,
+	}
+}
+
+func toDomainSubscription(model WebhookSubscriptionModel, events []domain.EventType) domain.WebhookSubscription {
+	return domain.WebhookSubscription{
+		Id:                  model.Id,
+		URL:                 model.URL,
+		Events:              events,
+		Secret:              model.Secret,
+		IsActive:            model.IsActive,
+		ConsecutiveFailures: model.ConsecutiveFailures,
+		CreatedAt:           model.CreatedAt,
+		DisabledAt:          model.DisabledAt,
+	}
+}
+
+func toDomainDelivery(model WebhookDeliveryModel) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		Id:             model.Id,
+		SubscriptionId: model.SubscriptionId,
+		EventId:        model.EventId,
+		EventType:      model.EventType,
+		Body:           []byte(model.Body),
+		Status:         domain.DeliveryStatus(model.Status),
+		Attempts:       model.Attempts,
+		ResponseCode:   model.ResponseCode,
+		LastError:      model.LastError,
+		CreatedAt:      model.CreatedAt,
+		NextAttemptAt:  model.NextAttemptAt,
+		DeliveredAt:    model.DeliveredAt,
+	}
+}
@@ -1,78 +1,61 @@
 package server
 
 import (
-	"avito-internship/pkg/logger"
 	"context"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 )
 
-const (
-	defaultPort         = "8080"
-	defaultReadTimeout  = 5 * time.Second
-	defaultWriteTimeout = 10 * time.Second
-	defaultKeepAlive    = 60 * time.Second
-)
-
 type Config struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	KeepAlive    time.Duration
+	Port         int           `yaml:"port" env:"HTTP_PORT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	KeepAlive    time.Duration `yaml:"keep_alive" env:"KEEP_ALIVE"`
+	// DrainDelay is how long Stop keeps serving after reporting draining, so that
+	// load balancers notice the failing readiness probe before connections are closed.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HTTP_DRAIN_DELAY"`
 }
 
 type Server struct {
 	httpServer *http.Server
-}
-
-func LoadHttpServerConfig(logger logger.Logger) Config {
-	port := os.Getenv("HTTP_PORT")
-	if port == "" {
-		logger.Warnf("the environment variable HTTP_PORT is not set. Using default value %s.", defaultPort)
-		port = defaultPort
-	}
-
-	return Config{
-		Port:         port,
-		ReadTimeout:  getEnvAsDuration("HTTP_READ_TIMEOUT", defaultReadTimeout, logger),
-		WriteTimeout: getEnvAsDuration("HTTP_WRITE_TIMEOUT", defaultWriteTimeout, logger),
-		KeepAlive:    getEnvAsDuration("KEEP_ALIVE", defaultKeepAlive, logger),
-	}
+	drainDelay time.Duration
+	onStop     []func()
 }
 
 func NewServer(handler http.Handler, httpServer Config) *Server {
 	return &Server{
 		httpServer: &http.Server{
-			Addr:         ":" + httpServer.Port,
+			Addr:         ":" + strconv.Itoa(httpServer.Port),
 			Handler:      handler,
 			ReadTimeout:  httpServer.ReadTimeout,
 			WriteTimeout: httpServer.WriteTimeout,
 			IdleTimeout:  httpServer.KeepAlive,
 		},
+		drainDelay: httpServer.DrainDelay,
 	}
 }
 
+// OnStop registers fn to be called when Stop starts, before the server stops accepting requests.
+func (s *Server) OnStop(fn func()) {
+	s.onStop = append(s.onStop, fn)
+}
+
 func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
 }
 
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
-}
-
-func getEnvAsDuration(key string, fallback time.Duration, logger logger.Logger) time.Duration {
-	valStr := os.Getenv(key)
-	if valStr == "" {
-		logger.Warnf("the environment variable %s is not set. Using default value %s.", key, fallback)
-		return fallback
+	for _, fn := range s.onStop {
+		fn()
 	}
 
-	duration, err := time.ParseDuration(valStr)
-	if err != nil {
-		logger.Warnf("invalid duration value for %s: '%s'. Using default %v\n", key, valStr, fallback)
-		return fallback
+	if s.drainDelay > 0 {
+		select {
+		case <-time.After(s.drainDelay):
+		case <-ctx.Done():
+		}
 	}
 
-	return duration
+	return s.httpServer.Shutdown(ctx)
 }
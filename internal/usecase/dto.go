@@ -17,10 +17,12 @@ type TeamDTO struct {
 	Members  []TeamMemberDTO
 }
 
+// UserDTO names the primary team in TeamName and every membership in Teams.
 type UserDTO struct {
 	Id       string
 	Username string
 	TeamName string
+	Teams    []string
 	IsActive bool
 }
 
@@ -70,6 +72,83 @@ type DeactivateMembersRes struct {
 	UpdPrs             []PullRequestDTO
 }
 
+type AddMembersReq struct {
+	TeamName string
+	Members  []TeamMemberDTO
+}
+
+type AddMembersRes struct {
+	Team TeamDTO
+}
+
+type RemoveMembersReq struct {
+	TeamName string
+	Members  []string
+}
+
+type RemoveMembersRes struct {
+	TeamName       string
+	RemovedMembers []TeamMemberDTO
+	UpdPrs         []PullRequestDTO
+}
+
+type SyncTeamReq struct {
+	TeamName string
+	Members  []TeamMemberDTO
+	DryRun   bool
+}
+
+type RenamedMemberDTO struct {
+	Id          string
+	OldUsername string
+	NewUsername string
+}
+
+type SyncTeamRes struct {
+	TeamName    string
+	DryRun      bool
+	Added       []TeamMemberDTO
+	Reactivated []TeamMemberDTO
+	Renamed     []RenamedMemberDTO
+	Deactivated []TeamMemberDTO
+	Removed     []TeamMemberDTO
+	UpdPrs      []PullRequestDTO
+}
+
+type RenameTeamReq struct {
+	TeamName    string
+	NewTeamName string
+}
+
+type RenameTeamRes struct {
+	Team TeamDTO
+}
+
+type SetTeamParentReq struct {
+	TeamName       string
+	ParentTeamName string
+	MaxDepth       int
+}
+
+type SetTeamParentRes struct {
+	TeamName       string
+	ParentTeamName string
+	MaxDepth       int
+}
+
+type DeleteTeamReq struct {
+	TeamName        string
+	TargetTeamName  string
+	ReassignReviews bool
+}
+
+type DeleteTeamRes struct {
+	TeamName       string
+	TargetTeamName string
+	MovedMembers   []TeamMemberDTO
+	UpdPrs         []PullRequestDTO
+}
+
 type SetIsActiveReq struct {
 	UserId   string
 	IsActive bool
@@ -79,14 +158,76 @@ type SetIsActiveRes struct {
 	User UserDTO
 }
 
+type TransferUserReq struct {
+	UserId   string
+	TeamName string
+}
+
+type TransferUserRes struct {
+	User          UserDTO
+	FromTeam      string
+	ReassignedPrs []PullRequestDTO
+	AuthoredPrs   []PullRequestDTO
+}
+
+type AnonymizeUserReq struct {
+	UserId string
+}
+
+type AnonymizeUserRes struct {
+	User          UserDTO
+	ReassignedPrs []PullRequestDTO
+}
+
+type GetUserRes struct {
+	User        UserDTO
+	OpenReviews int
+	AuthoredPrs int
+}
+
+type ListUsersReq struct {
+	Query    string
+	TeamName string
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
+
+type ListUsersRes struct {
+	Users  []UserDTO
+	Total  int
+	Limit  int
+	Offset int
+}
+
+type JoinTeamReq struct {
+	UserId   string
+	TeamName string
+}
+
+type JoinTeamRes struct {
+	User UserDTO
+}
+
 type CreatePullRequestReq struct {
 	Id       string
 	Name     string
 	AuthorId string
+	TeamName string
 }
 
 type CreatePullRequestRes struct {
-	PullRequest PullRequestDTO
+	PullRequest     PullRequestDTO
+	ReviewerSources []ReviewerSourceDTO
+}
+
+// ReviewerSourceDTO tells which team a reviewer was drawn from and how many
+// levels above the PR team it sits.
+type ReviewerSourceDTO struct {
+	UserId   string
+	TeamName string
+	Source   domain.ReviewerSource
+	Level    int
 }
 
 type PullRequestMergeReq struct {
@@ -103,8 +244,9 @@ type PullRequestReassignReq struct {
 }
 
 type PullRequestReassignRes struct {
-	Pr         PullRequestDTO
-	ReplacedBy string
+	Pr               PullRequestDTO
+	ReplacedBy       string
+	ReplacedBySource ReviewerSourceDTO
 }
 
 type GetReviewQueryReq struct {
@@ -116,14 +258,42 @@ type GetReviewRes struct {
 	PullRequests []PullRequestShort
 }
 
-func NewSetIsActiveRes(id, username, teamName string, isActive bool) SetIsActiveRes {
+func NewUserDTO(user domain.User, teams []domain.Team) UserDTO {
+	dto := UserDTO{
+		Id:       user.Id,
+		Username: user.Name,
+		Teams:    make([]string, 0, len(teams)),
+		IsActive: user.IsActive,
+	}
+	for _, team := range teams {
+		if team.Id == user.TeamId {
+			dto.TeamName = team.Name
+		}
+		dto.Teams = append(dto.Teams, team.Name)
+	}
+
+	return dto
+}
+
+func NewSetIsActiveRes(user domain.User, teams []domain.Team) SetIsActiveRes {
 	return SetIsActiveRes{
-		User: UserDTO{
-			Id:       id,
-			Username: username,
-			TeamName: teamName,
-			IsActive: isActive,
-		},
+		User: NewUserDTO(user, teams),
+	}
+}
+
+func NewTransferUserRes(user domain.User, teams []domain.Team, fromTeam string, reassigned, authored []PullRequestDTO) TransferUserRes {
+	return TransferUserRes{
+		User:          NewUserDTO(user, teams),
+		FromTeam:      fromTeam,
+		ReassignedPrs: reassigned,
+		AuthoredPrs:   authored,
+	}
+}
+
+func NewAnonymizeUserRes(user domain.User, teams []domain.Team, reassigned []PullRequestDTO) AnonymizeUserRes {
+	return AnonymizeUserRes{
+		User:          NewUserDTO(user, teams),
+		ReassignedPrs: reassigned,
 	}
 }
 
@@ -213,16 +383,18 @@ func NewPullRequestDTO(pr domain.PullRequest, reviewers []string, statusName dom
 	return result
 }
 
-func NewCreatePullRequestRes(prDTO PullRequestDTO) CreatePullRequestRes {
+func NewCreatePullRequestRes(prDTO PullRequestDTO, sources []ReviewerSourceDTO) CreatePullRequestRes {
 	return CreatePullRequestRes{
-		PullRequest: prDTO,
+		PullRequest:     prDTO,
+		ReviewerSources: sources,
 	}
 }
 
-func NewPullRequestReassignRes(pr PullRequestDTO, replacedBy string) PullRequestReassignRes {
+func NewPullRequestReassignRes(pr PullRequestDTO, replacedBy ReviewerSourceDTO) PullRequestReassignRes {
 	return PullRequestReassignRes{
-		Pr:         pr,
-		ReplacedBy: replacedBy,
+		Pr:               pr,
+		ReplacedBy:       replacedBy.UserId,
+		ReplacedBySource: replacedBy,
 	}
 }
 
@@ -243,3 +415,112 @@ func NewDeactivateMembersRes(teamName string, members []TeamMemberDTO, prs []Pul
 		UpdPrs:             prs,
 	}
 }
+
+func NewAddMembersRes(teamDTO TeamDTO) AddMembersRes {
+	return AddMembersRes{
+		Team: teamDTO,
+	}
+}
+
+func NewRemoveMembersRes(teamName string, members []TeamMemberDTO, prs []PullRequestDTO) RemoveMembersRes {
+	return RemoveMembersRes{
+		TeamName:       teamName,
+		RemovedMembers: members,
+		UpdPrs:         prs,
+	}
+}
+
+type WebhookSubscriptionDTO struct {
+	Id                  int
+	URL                 string
+	Events              []domain.EventType
+	IsActive            bool
+	ConsecutiveFailures int
+	CreatedAt           string
+	DisabledAt          *string
+}
+
+type WebhookDeliveryDTO struct {
+	Id            int64
+	EventId       int64
+	EventType     domain.EventType
+	Status        domain.DeliveryStatus
+	Attempts      int
+	ResponseCode  *int
+	LastError     *string
+	CreatedAt     string
+	NextAttemptAt *string
+	DeliveredAt   *string
+}
+
+type CreateWebhookReq struct {
+	URL    string
+	Events []domain.EventType
+	Secret string
+}
+
+type CreateWebhookRes struct {
+	Subscription WebhookSubscriptionDTO
+	Secret       string
+}
+
+type ListWebhooksRes struct {
+	Subscriptions []WebhookSubscriptionDTO
+}
+
+type EnableWebhookRes struct {
+	Subscription WebhookSubscriptionDTO
+}
+
+type GetWebhookDeliveriesReq struct {
+	SubscriptionId int
+	Limit          int
+}
+
+type GetWebhookDeliveriesRes struct {
+	SubscriptionId int
+	Deliveries     []WebhookDeliveryDTO
+}
+
+func NewWebhookSubscriptionDTO(sub domain.WebhookSubscription) WebhookSubscriptionDTO {
+	var disabledAt *string
+	if sub.DisabledAt != nil {
+		s := sub.DisabledAt.Format(time.RFC3339)
+		disabledAt = &s
+	}
+
+	return WebhookSubscriptionDTO{
+		Id:                  sub.Id,
+		URL:                 sub.URL,
+		Events:              sub.Events,
+		IsActive:            sub.IsActive,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		CreatedAt:           sub.CreatedAt.Format(time.RFC3339),
+		DisabledAt:          disabledAt,
+	}
+}
+
+func NewWebhookDeliveryDTO(d domain.WebhookDelivery) WebhookDeliveryDTO {
+	var nextAttemptAt, deliveredAt *string
+	if d.Status == domain.DeliveryPending {
+		s := d.NextAttemptAt.Format(time.RFC3339)
+		nextAttemptAt = &s
+	}
+	if d.DeliveredAt != nil {
+		s := d.DeliveredAt.Format(time.RFC3339)
+		deliveredAt = &s
+	}
+
+	return WebhookDeliveryDTO{
+		Id:            d.Id,
+		EventId:       d.EventId,
+		EventType:     d.EventType,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339),
+		NextAttemptAt: nextAttemptAt,
+		DeliveredAt:   deliveredAt,
+	}
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"avito-internship/internal/domain"
+	"encoding/json"
+)
+
+// newEvent encodes payload for the outbox. Payloads are plain structs of
+// strings and times, so encoding cannot fail.
+func newEvent(eventType domain.EventType, payload any) domain.Event {
+	raw, _ := json.Marshal(payload)
+	return domain.NewEvent(eventType, raw)
+}
@@ -0,0 +1,16 @@
+package usecase
+
+const (
+	defaultMaxReviewers = 2
+)
+
+// ReviewPolicy configures how reviewers are assigned to pull requests.
+type ReviewPolicy struct {
+	MaxReviewers int `yaml:"max_reviewers" env:"REVIEW_MAX_REVIEWERS"`
+}
+
+func DefaultReviewPolicy() ReviewPolicy {
+	return ReviewPolicy{
+		MaxReviewers: defaultMaxReviewers,
+	}
+}
@@ -6,85 +6,169 @@ import (
 	"avito-internship/pkg/e"
 	"avito-internship/pkg/transaction"
 	"context"
+	"math/rand"
 	"slices"
 	"time"
-
-	"github.com/jackc/pgx/v5"
-)
-
-const (
-	maxReviewers = 2
 )
 
 type PullRequestUseCase struct {
 	prRepo       r.PullRequestRepository
 	reviewerRepo r.PrReviewerRepository
 	userRepo     r.UserRepository
+	teamRepo     r.TeamRepository
 	statusRepo   r.StatusRepository
-	dbPool       transaction.Transactional
+	outboxRepo   r.OutboxRepository
+	txManager    transaction.Manager
+	policy       ReviewPolicy
 }
 
 func NewPullRequestUseCase(prRepo r.PullRequestRepository, reviewerRepo r.PrReviewerRepository,
-	userRepo r.UserRepository, statusRepo r.StatusRepository, dbPool transaction.Transactional) *PullRequestUseCase {
+	userRepo r.UserRepository, teamRepo r.TeamRepository, statusRepo r.StatusRepository, outboxRepo r.OutboxRepository,
+	txManager transaction.Manager, policy ReviewPolicy) *PullRequestUseCase {
 	return &PullRequestUseCase{
 		prRepo:       prRepo,
 		reviewerRepo: reviewerRepo,
 		userRepo:     userRepo,
+		teamRepo:     teamRepo,
 		statusRepo:   statusRepo,
-		dbPool:       dbPool,
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
+		policy:       policy,
 	}
 }
 
+// PullRequestCreate assigns reviewers from req.TeamName, which the author must
+// be a member of, or from the author's primary team when it is empty. A team
+// short of active members borrows reviewers from its siblings and parents, see
+// widenCandidates. The id is generated when req.Id is empty.
 func (p *PullRequestUseCase) PullRequestCreate(ctx context.Context, req CreatePullRequestReq) (CreatePullRequestRes, error) {
 	const op = "PullRequestUseCase.PullRequestCreate"
 
-	ctx, tx, err := transaction.NewTransaction(ctx, pgx.TxOptions{}, p.dbPool)
+	ctx, tx, err := p.txManager.Begin(ctx)
 	if err != nil {
 		return CreatePullRequestRes{}, e.Wrap(op, err)
 	}
 	defer tx.Rollback(ctx)
 	ctx = context.WithValue(ctx, "tx", tx.Transaction())
 
-	reviewers, err := p.userRepo.GetReviewCandidates(ctx, req.AuthorId, maxReviewers)
+	author, err := p.userRepo.GetById(ctx, req.AuthorId)
 	if err != nil {
 		return CreatePullRequestRes{}, e.Wrap(op, err)
 	}
 
-	reviewersIds := make([]string, 0, len(reviewers))
+	var team domain.Team
+	if req.TeamName != "" {
+		team, err = p.memberTeam(ctx, author.Id, req.TeamName)
+		if err != nil {
+			return CreatePullRequestRes{}, e.Wrap(op, err)
+		}
+	}
+
+	reviewTeam := team
+	if team.Id == 0 && author.TeamId != 0 {
+		reviewTeam, err = p.teamRepo.GetById(ctx, author.TeamId)
+		if err != nil {
+			return CreatePullRequestRes{}, e.Wrap(op, err)
+		}
+	}
+
+	reviewers, err := p.userRepo.GetReviewCandidates(ctx, reviewTeam.Id, author.Id, p.policy.MaxReviewers)
+	if err != nil {
+		return CreatePullRequestRes{}, e.Wrap(op, err)
+	}
+
+	sources := make([]ReviewerSourceDTO, 0, p.policy.MaxReviewers)
+	reviewersIds := make([]string, 0, p.policy.MaxReviewers)
 	for i := range reviewers {
+		sources = append(sources, ReviewerSourceDTO{UserId: reviewers[i].Id, TeamName: reviewTeam.Name, Source: domain.SourceTeam})
 		reviewersIds = append(reviewersIds, reviewers[i].Id)
 	}
 
+	if missing := p.policy.MaxReviewers - len(reviewersIds); missing > 0 {
+		more, err := p.widenCandidates(ctx, reviewTeam, append([]string{author.Id}, reviewersIds...), missing)
+		if err != nil {
+			return CreatePullRequestRes{}, e.Wrap(op, err)
+		}
+
+		for _, source := range more {
+			sources = append(sources, source)
+			reviewersIds = append(reviewersIds, source.UserId)
+		}
+	}
+
 	status, err := p.statusRepo.GetByName(ctx, string(domain.OPEN))
 	if err != nil {
 		return CreatePullRequestRes{}, e.Wrap(op, err)
 	}
 
-	needMoreReviewers := len(reviewersIds) < maxReviewers
-	pr := domain.NewPoolRequest(req.Id, req.Name, req.AuthorId, status.Id, needMoreReviewers, time.Now())
+	needMoreReviewers := len(reviewersIds) < p.policy.MaxReviewers
+	prId := req.Id
+	if prId == "" {
+		prId = domain.NewPullRequestId()
+	}
+
+	pr := domain.NewPoolRequest(prId, req.Name, req.AuthorId, status.Id, needMoreReviewers, time.Now())
+	pr.TeamId = team.Id
 	newPr, err := p.prRepo.Create(ctx, *pr)
 	if err != nil {
 		return CreatePullRequestRes{}, e.Wrap(op, err)
 	}
 
-	if len(reviewers) > 0 {
+	if len(reviewersIds) > 0 {
 		err := p.reviewerRepo.AddReviewers(ctx, newPr.Id, reviewersIds)
 		if err != nil {
 			return CreatePullRequestRes{}, e.Wrap(op, err)
 		}
 	}
 
+	events := make([]domain.Event, 0, len(reviewersIds)+1)
+	events = append(events, newEvent(domain.EventPRCreated, domain.PRCreatedPayload{
+		PullRequestId: newPr.Id,
+		Name:          newPr.Name,
+		AuthorId:      newPr.AuthorId,
+		Reviewers:     reviewersIds,
+		CreatedAt:     newPr.CreatedAt,
+	}))
+	for _, reviewerId := range reviewersIds {
+		events = append(events, newEvent(domain.EventReviewerAssigned, domain.ReviewerAssignedPayload{
+			PullRequestId: newPr.Id,
+			ReviewerId:    reviewerId,
+		}))
+	}
+
+	if err := p.outboxRepo.Add(ctx, events...); err != nil {
+		return CreatePullRequestRes{}, e.Wrap(op, err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return CreatePullRequestRes{}, e.Wrap(op, err)
 	}
 
 	prDTO := NewPullRequestDTO(*pr, reviewersIds, status.Name)
-	return NewCreatePullRequestRes(prDTO), nil
+	return NewCreatePullRequestRes(prDTO, sources), nil
 }
 
 func (p *PullRequestUseCase) PullRequestMerge(ctx context.Context, req PullRequestMergeReq) (PullRequestMergeRes, error) {
 	const op = "PullRequestUseCase.PullRequestMerge"
 
+	ctx, tx, err := p.txManager.Begin(ctx)
+	if err != nil {
+		return PullRequestMergeRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	current, err := p.prRepo.GetByPrIdWithReviewersIds(ctx, req.Id)
+	if err != nil {
+		return PullRequestMergeRes{}, e.Wrap(op, err)
+	}
+
+	// Merging again returns the PR as is and emits nothing.
+	if current.StatusName == domain.MERGED {
+		prDTO := NewPullRequestDTO(current.Pr, current.ReviewersIds, current.StatusName)
+		return NewPullRequestMergeRes(prDTO), nil
+	}
+
 	status, err := p.statusRepo.GetByName(ctx, string(domain.MERGED))
 	if err != nil {
 		return PullRequestMergeRes{}, e.Wrap(op, err)
@@ -95,6 +179,23 @@ func (p *PullRequestUseCase) PullRequestMerge(ctx context.Context, req PullReque
 		return PullRequestMergeRes{}, e.Wrap(op, err)
 	}
 
+	mergedAt := time.Now()
+	if dto.Pr.MergedAt != nil {
+		mergedAt = *dto.Pr.MergedAt
+	}
+
+	err = p.outboxRepo.Add(ctx, newEvent(domain.EventPRMerged, domain.PRMergedPayload{
+		PullRequestId: dto.Pr.Id,
+		MergedAt:      mergedAt,
+	}))
+	if err != nil {
+		return PullRequestMergeRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PullRequestMergeRes{}, e.Wrap(op, err)
+	}
+
 	prDTO := NewPullRequestDTO(dto.Pr, dto.ReviewersIds, status.Name)
 	return NewPullRequestMergeRes(prDTO), nil
 }
@@ -102,7 +203,14 @@ func (p *PullRequestUseCase) PullRequestMerge(ctx context.Context, req PullReque
 func (p *PullRequestUseCase) ReviewerReassign(ctx context.Context, req PullRequestReassignReq) (PullRequestReassignRes, error) {
 	const op = "PullRequestUseCase.PullRequestReassign"
 
-	_, err := p.userRepo.GetById(ctx, req.OldReviewerId)
+	ctx, tx, err := p.txManager.Begin(ctx)
+	if err != nil {
+		return PullRequestReassignRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	_, err = p.userRepo.GetById(ctx, req.OldReviewerId)
 	if err != nil {
 		return PullRequestReassignRes{}, e.Wrap(op, err)
 	}
@@ -121,29 +229,126 @@ func (p *PullRequestUseCase) ReviewerReassign(ctx context.Context, req PullReque
 		return PullRequestReassignRes{}, e.Wrap(op, e.ErrPrMerged)
 	}
 
-	excludeIds := dto.ReviewersIds
-	excludeIds = append(excludeIds, dto.Pr.AuthorId)
-	candidates, err := p.userRepo.GetReassignCandidates(ctx, dto.Pr.AuthorId, excludeIds, maxReviewers)
+	var team domain.Team
+	if dto.ReviewTeamId != 0 {
+		team, err = p.teamRepo.GetById(ctx, dto.ReviewTeamId)
+		if err != nil {
+			return PullRequestReassignRes{}, e.Wrap(op, err)
+		}
+	}
+
+	excludeIds := append(slices.Clone(dto.ReviewersIds), dto.Pr.AuthorId)
+	candidates, err := p.userRepo.GetReassignCandidates(ctx, team.Id, excludeIds, p.policy.MaxReviewers)
 	if err != nil {
 		return PullRequestReassignRes{}, e.Wrap(op, err)
 	}
 
-	if len(candidates) == 0 {
-		return PullRequestReassignRes{}, e.Wrap(op, e.ErrPrNoCandidate)
+	var replacement ReviewerSourceDTO
+	if len(candidates) > 0 {
+		replacement = ReviewerSourceDTO{UserId: candidates[0].Id, TeamName: team.Name, Source: domain.SourceTeam}
+	} else {
+		more, err := p.widenCandidates(ctx, team, excludeIds, 1)
+		if err != nil {
+			return PullRequestReassignRes{}, e.Wrap(op, err)
+		}
+
+		if len(more) == 0 {
+			return PullRequestReassignRes{}, e.Wrap(op, e.ErrPrNoCandidate)
+		}
+		replacement = more[0]
 	}
 
-	candidatesIds := make([]string, len(candidates))
-	for i := range candidates {
-		candidatesIds[i] = candidates[i].Id
+	newReviewerId, err := p.reviewerRepo.UpdateReviewer(ctx, req.OldReviewerId, replacement.UserId, dto.Pr.Id)
+	if err != nil {
+		return PullRequestReassignRes{}, e.Wrap(op, err)
 	}
 
-	newReviewerId, err := p.reviewerRepo.UpdateReviewer(ctx, req.OldReviewerId, candidatesIds[0], dto.Pr.Id)
+	err = p.outboxRepo.Add(ctx, newEvent(domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{
+		PullRequestId: dto.Pr.Id,
+		OldReviewerId: req.OldReviewerId,
+		NewReviewerId: newReviewerId,
+	}))
 	if err != nil {
 		return PullRequestReassignRes{}, e.Wrap(op, err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return PullRequestReassignRes{}, e.Wrap(op, err)
+	}
+
 	prDTO := NewPullRequestDTO(dto.Pr, dto.ReviewersIds, dto.StatusName)
 	prDTO.AssignedReviewers[oldReviewerIndex] = newReviewerId
 
-	return NewPullRequestReassignRes(prDTO, newReviewerId), nil
+	replacement.UserId = newReviewerId
+	return NewPullRequestReassignRes(prDTO, replacement), nil
+}
+
+// memberTeam returns the named team, failing with ErrNotTeamMember when the user
+// does not belong to it.
+func (p *PullRequestUseCase) memberTeam(ctx context.Context, userId, teamName string) (domain.Team, error) {
+	team, err := p.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	teams, err := p.teamRepo.GetTeamsByUserId(ctx, userId)
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	if !slices.ContainsFunc(teams, func(t domain.Team) bool { return t.Id == team.Id }) {
+		return domain.Team{}, e.ErrNotTeamMember
+	}
+
+	return team, nil
+}
+
+// widenCandidates finds up to n active reviewers outside a team that is short of
+// them. Each level looks at the siblings of the current team in random order and
+// then at its parent, which becomes the current team for the next level. The
+// search stops after team.MaxDepth levels or at a top-level team.
+func (p *PullRequestUseCase) widenCandidates(ctx context.Context, team domain.Team, excludeIds []string,
+	n int) ([]ReviewerSourceDTO, error) {
+	found := make([]ReviewerSourceDTO, 0, n)
+	excludeIds = slices.Clone(excludeIds)
+
+	current := team
+	for level := 1; level <= team.MaxDepth && current.ParentId != 0 && len(found) < n; level++ {
+		siblings, err := p.teamRepo.GetChildren(ctx, current.ParentId)
+		if err != nil {
+			return nil, err
+		}
+		siblings = slices.DeleteFunc(siblings, func(t domain.Team) bool { return t.Id == current.Id })
+		rand.Shuffle(len(siblings), func(i, j int) { siblings[i], siblings[j] = siblings[j], siblings[i] })
+
+		parent, err := p.teamRepo.GetById(ctx, current.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pool := range append(siblings, parent) {
+			if len(found) == n {
+				break
+			}
+
+			source := domain.SourceSibling
+			if pool.Id == parent.Id {
+				source = domain.SourceParent
+			}
+
+			users, err := p.userRepo.GetReassignCandidates(ctx, pool.Id, excludeIds, n-len(found))
+			if err != nil {
+				return nil, err
+			}
+
+			for i := range users {
+				found = append(found, ReviewerSourceDTO{UserId: users[i].Id, TeamName: pool.Name, Source: source, Level: level})
+				excludeIds = append(excludeIds, users[i].Id)
+			}
+		}
+
+		current = parent
+	}
+
+	return found, nil
 }
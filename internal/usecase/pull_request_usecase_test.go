@@ -5,6 +5,7 @@ import (
 	r "avito-internship/internal/repository"
 	repoMocks "avito-internship/internal/repository/mocks"
 	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
 	trMock "avito-internship/pkg/transaction/mocks"
 	"context"
 	"errors"
@@ -25,8 +26,10 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 		statusRepoSetup   func(*repoMocks.MockStatusRepository)
 		prRepoSetup       func(*repoMocks.MockPullRequestRepository)
 		userRepoSetup     func(*repoMocks.MockUserRepository)
+		teamRepoSetup     func(*repoMocks.MockTeamRepository)
 		reviewerRepoSetup func(repository *repoMocks.MockPrReviewerRepository)
 		expectedRes       CreatePullRequestRes
+		expectedEvents    []domain.EventType
 		expectedErr       error
 	}{
 		{
@@ -38,7 +41,7 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 			},
 			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {
 				repo.EXPECT().GetByName(gomock.Any(), "OPEN").
-					Return(domain.Status{1, "OPEN"}, nil)
+					Return(domain.Status{Id: 1, Name: "OPEN"}, nil)
 			},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
 				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -49,8 +52,9 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 				)
 			},
 			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.EXPECT().GetById(gomock.Any(), "u1").Return(domain.User{Id: "u1", IsActive: true, TeamId: 1}, nil)
 				repo.EXPECT().
-					GetReviewCandidates(gomock.Any(), "u1", 2).
+					GetReviewCandidates(gomock.Any(), 1, "u1", 2).
 					Return([]domain.User{
 						{
 							Id:       "u2",
@@ -66,6 +70,9 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 						},
 					}, nil)
 			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil)
+			},
 			reviewerRepoSetup: func(repository *repoMocks.MockPrReviewerRepository) {
 				repository.EXPECT().AddReviewers(gomock.Any(), "pr-1001", []string{"u2", "u3"}).
 					Return(nil)
@@ -80,8 +87,13 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 					CreatedAt:         &createdAtStr,
 					MergedAt:          nil,
 				},
+				ReviewerSources: []ReviewerSourceDTO{
+					{UserId: "u2", TeamName: "backend", Source: domain.SourceTeam},
+					{UserId: "u3", TeamName: "backend", Source: domain.SourceTeam},
+				},
 			},
-			expectedErr: nil,
+			expectedEvents: []domain.EventType{domain.EventPRCreated, domain.EventReviewerAssigned, domain.EventReviewerAssigned},
+			expectedErr:    nil,
 		},
 		{
 			name: "pr already exists",
@@ -92,14 +104,18 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 			},
 			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {
 				repo.EXPECT().GetByName(gomock.Any(), "OPEN").
-					Return(domain.Status{1, "OPEN"}, nil)
+					Return(domain.Status{Id: 1, Name: "OPEN"}, nil)
 			},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
 				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
 					Return(domain.PullRequest{}, e.ErrPRIsExists)
 			},
 			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
-				repo.EXPECT().GetReviewCandidates(gomock.Any(), "u1", 2).Return([]domain.User{}, nil)
+				repo.EXPECT().GetById(gomock.Any(), "u1").Return(domain.User{Id: "u1", IsActive: true, TeamId: 1}, nil)
+				repo.EXPECT().GetReviewCandidates(gomock.Any(), 1, "u1", 2).Return([]domain.User{}, nil)
+			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil)
 			},
 			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {},
 			expectedRes:       CreatePullRequestRes{},
@@ -115,12 +131,131 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {},
 			prRepoSetup:     func(repo *repoMocks.MockPullRequestRepository) {},
 			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
-				repo.EXPECT().GetReviewCandidates(gomock.Any(), "u888", 2).Return([]domain.User{}, e.ErrUserNotFound)
+				repo.EXPECT().GetById(gomock.Any(), "u888").Return(domain.User{}, e.ErrUserNotFound)
 			},
+			teamRepoSetup:     func(repo *repoMocks.MockTeamRepository) {},
 			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {},
 			expectedRes:       CreatePullRequestRes{},
 			expectedErr:       e.ErrUserNotFound,
 		},
+		{
+			name: "owning team",
+			req: CreatePullRequestReq{
+				Id:       "pr-1002",
+				Name:     "Platform PR",
+				AuthorId: "u1",
+				TeamName: "platform",
+			},
+			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {
+				repo.EXPECT().GetByName(gomock.Any(), "OPEN").
+					Return(domain.Status{Id: 1, Name: "OPEN"}, nil)
+			},
+			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
+						require.Equal(t, 2, pr.TeamId)
+						return pr, nil
+					},
+				)
+			},
+			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.EXPECT().GetById(gomock.Any(), "u1").Return(domain.User{Id: "u1", IsActive: true, TeamId: 1}, nil)
+				repo.EXPECT().GetReviewCandidates(gomock.Any(), 2, "u1", 2).
+					Return([]domain.User{{Id: "u5", IsActive: true, TeamId: 2}}, nil)
+			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetByName(gomock.Any(), "platform").Return(domain.Team{Id: 2, Name: "platform"}, nil)
+				repo.EXPECT().GetTeamsByUserId(gomock.Any(), "u1").
+					Return([]domain.Team{{Id: 1, Name: "backend"}, {Id: 2, Name: "platform"}}, nil)
+			},
+			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {
+				repo.EXPECT().AddReviewers(gomock.Any(), "pr-1002", []string{"u5"}).Return(nil)
+			},
+			expectedRes: CreatePullRequestRes{
+				PullRequest: PullRequestDTO{
+					Id:                "pr-1002",
+					Name:              "Platform PR",
+					AuthorId:          "u1",
+					Status:            domain.OPEN,
+					AssignedReviewers: []string{"u5"},
+				},
+				ReviewerSources: []ReviewerSourceDTO{{UserId: "u5", TeamName: "platform", Source: domain.SourceTeam}},
+			},
+			expectedEvents: []domain.EventType{domain.EventPRCreated, domain.EventReviewerAssigned},
+		},
+		{
+			name: "widens to sibling and parent teams",
+			req: CreatePullRequestReq{
+				Id:       "pr-1004",
+				Name:     "Mobile PR",
+				AuthorId: "u1",
+			},
+			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {
+				repo.EXPECT().GetByName(gomock.Any(), "OPEN").
+					Return(domain.Status{Id: 1, Name: "OPEN"}, nil)
+			},
+			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
+						require.False(t, pr.NeedMoreReviewers)
+						require.Zero(t, pr.TeamId)
+						return pr, nil
+					},
+				)
+			},
+			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.EXPECT().GetById(gomock.Any(), "u1").Return(domain.User{Id: "u1", IsActive: true, TeamId: 3}, nil)
+				repo.EXPECT().GetReviewCandidates(gomock.Any(), 3, "u1", 2).Return([]domain.User{}, nil)
+				repo.EXPECT().GetReassignCandidates(gomock.Any(), 2, []string{"u1"}, 2).
+					Return([]domain.User{{Id: "u5", IsActive: true, TeamId: 2}}, nil)
+				repo.EXPECT().GetReassignCandidates(gomock.Any(), 1, []string{"u1", "u5"}, 1).
+					Return([]domain.User{{Id: "u6", IsActive: true, TeamId: 1}}, nil)
+			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 3).Return(domain.Team{Id: 3, Name: "android", ParentId: 1, MaxDepth: 1}, nil)
+				repo.EXPECT().GetChildren(gomock.Any(), 1).
+					Return([]domain.Team{{Id: 3, Name: "android", ParentId: 1}, {Id: 2, Name: "ios", ParentId: 1}}, nil)
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "mobile"}, nil)
+			},
+			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {
+				repo.EXPECT().AddReviewers(gomock.Any(), "pr-1004", []string{"u5", "u6"}).Return(nil)
+			},
+			expectedRes: CreatePullRequestRes{
+				PullRequest: PullRequestDTO{
+					Id:                "pr-1004",
+					Name:              "Mobile PR",
+					AuthorId:          "u1",
+					Status:            domain.OPEN,
+					AssignedReviewers: []string{"u5", "u6"},
+				},
+				ReviewerSources: []ReviewerSourceDTO{
+					{UserId: "u5", TeamName: "ios", Source: domain.SourceSibling, Level: 1},
+					{UserId: "u6", TeamName: "mobile", Source: domain.SourceParent, Level: 1},
+				},
+			},
+			expectedEvents: []domain.EventType{domain.EventPRCreated, domain.EventReviewerAssigned, domain.EventReviewerAssigned},
+		},
+		{
+			name: "author not in team",
+			req: CreatePullRequestReq{
+				Id:       "pr-1003",
+				Name:     "Foreign PR",
+				AuthorId: "u1",
+				TeamName: "mobile",
+			},
+			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {},
+			prRepoSetup:     func(repo *repoMocks.MockPullRequestRepository) {},
+			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.EXPECT().GetById(gomock.Any(), "u1").Return(domain.User{Id: "u1", IsActive: true, TeamId: 1}, nil)
+			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetByName(gomock.Any(), "mobile").Return(domain.Team{Id: 3, Name: "mobile"}, nil)
+				repo.EXPECT().GetTeamsByUserId(gomock.Any(), "u1").Return([]domain.Team{{Id: 1, Name: "backend"}}, nil)
+			},
+			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {},
+			expectedRes:       CreatePullRequestRes{},
+			expectedErr:       e.ErrNotTeamMember,
+		},
 	}
 
 	for _, tt := range tests {
@@ -132,6 +267,7 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 			userRepo := repoMocks.NewMockUserRepository(ctrl)
 			prRepo := repoMocks.NewMockPullRequestRepository(ctrl)
 			reviewerRepo := repoMocks.NewMockPrReviewerRepository(ctrl)
+			teamRepo := repoMocks.NewMockTeamRepository(ctrl)
 
 			mockTx := trMock.NewMockTx(ctrl)
 			mockTx.EXPECT().Commit(gomock.Any()).Return(nil).AnyTimes()
@@ -143,17 +279,22 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 				Return(mockTx, nil).
 				AnyTimes()
 
-			prUC := NewPullRequestUseCase(prRepo, reviewerRepo, userRepo, statusRepo, mockTxPool)
+			outboxRepo := repoMocks.NewMockOutboxRepository(ctrl)
+			events := recordEvents(outboxRepo)
+
+			prUC := NewPullRequestUseCase(prRepo, reviewerRepo, userRepo, teamRepo, statusRepo, outboxRepo, transaction.NewPgxManager(mockTxPool), DefaultReviewPolicy())
 
 			tt.statusRepoSetup(statusRepo)
 			tt.prRepoSetup(prRepo)
 			tt.userRepoSetup(userRepo)
+			tt.teamRepoSetup(teamRepo)
 			tt.reviewerRepoSetup(reviewerRepo)
 
 			res, err := prUC.PullRequestCreate(context.Background(), tt.req)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("unexpected error: got %v, want %v", err, tt.expectedErr)
 			}
+			require.Equal(t, tt.expectedEvents, *events)
 
 			require.Equal(t, tt.expectedRes.PullRequest.Id, res.PullRequest.Id)
 			require.Equal(t, tt.expectedRes.PullRequest.Name, res.PullRequest.Name)
@@ -161,6 +302,7 @@ func TestPullRequestUseCase_PullRequestCreate(t *testing.T) {
 			require.Equal(t, tt.expectedRes.PullRequest.Status, res.PullRequest.Status)
 			require.ElementsMatch(t, tt.expectedRes.PullRequest.AssignedReviewers, res.PullRequest.AssignedReviewers)
 			require.Equal(t, tt.expectedRes.PullRequest.MergedAt, res.PullRequest.MergedAt)
+			require.ElementsMatch(t, tt.expectedRes.ReviewerSources, res.ReviewerSources)
 		})
 	}
 }
@@ -175,6 +317,7 @@ func TestPullRequestUseCase_PullRequestMerge(t *testing.T) {
 		statusRepoSetup func(*repoMocks.MockStatusRepository)
 		prRepoSetup     func(*repoMocks.MockPullRequestRepository)
 		expectedRes     PullRequestMergeRes
+		expectedEvents  []domain.EventType
 		expectedErr     error
 	}{
 		{
@@ -187,6 +330,13 @@ func TestPullRequestUseCase_PullRequestMerge(t *testing.T) {
 					Return(domain.Status{Id: 2, Name: "MERGED"}, nil)
 			},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
+				repo.EXPECT().GetByPrIdWithReviewersIds(gomock.Any(), "pr-1001").
+					Return(r.GetByPrIdWithReviewersIdsDTO{
+						Pr:           domain.PullRequest{Id: "pr-1001", Name: "Test PR", AuthorId: "u1", StatusId: 1, CreatedAt: fixedTime},
+						ReviewersIds: []string{"u2", "u3"},
+						StatusName:   domain.OPEN,
+						ReviewTeamId: 1,
+					}, nil)
 				repo.EXPECT().SetMergedStatus(gomock.Any(), 2, "pr-1001").
 					DoAndReturn(func(ctx context.Context, statusId int, prId string) (r.SetMergedStatusDTO, error) {
 						pr := domain.PullRequest{
@@ -215,6 +365,41 @@ func TestPullRequestUseCase_PullRequestMerge(t *testing.T) {
 					MergedAt:          &mergedAtStr,
 				},
 			},
+			expectedEvents: []domain.EventType{domain.EventPRMerged},
+			expectedErr:    nil,
+		},
+		{
+			name: "already merged",
+			req: PullRequestMergeReq{
+				Id: "pr-1001",
+			},
+			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {},
+			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
+				repo.EXPECT().GetByPrIdWithReviewersIds(gomock.Any(), "pr-1001").
+					Return(r.GetByPrIdWithReviewersIdsDTO{
+						Pr: domain.PullRequest{
+							Id:        "pr-1001",
+							Name:      "Test PR",
+							AuthorId:  "u1",
+							StatusId:  2,
+							CreatedAt: fixedTime,
+							MergedAt:  &fixedTime,
+						},
+						ReviewersIds: []string{"u2", "u3"},
+						StatusName:   domain.MERGED,
+					}, nil)
+			},
+			expectedRes: PullRequestMergeRes{
+				PullRequest: PullRequestDTO{
+					Id:                "pr-1001",
+					Name:              "Test PR",
+					AuthorId:          "u1",
+					Status:            domain.MERGED,
+					AssignedReviewers: []string{"u2", "u3"},
+					CreatedAt:         &mergedAtStr,
+					MergedAt:          &mergedAtStr,
+				},
+			},
 			expectedErr: nil,
 		},
 		{
@@ -222,13 +407,10 @@ func TestPullRequestUseCase_PullRequestMerge(t *testing.T) {
 			req: PullRequestMergeReq{
 				Id: "pr-9999",
 			},
-			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {
-				repo.EXPECT().GetByName(gomock.Any(), "MERGED").
-					Return(domain.Status{Id: 2, Name: "MERGED"}, nil)
-			},
+			statusRepoSetup: func(repo *repoMocks.MockStatusRepository) {},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
-				repo.EXPECT().SetMergedStatus(gomock.Any(), 2, "pr-9999").
-					Return(r.SetMergedStatusDTO{}, e.ErrPRNotFound)
+				repo.EXPECT().GetByPrIdWithReviewersIds(gomock.Any(), "pr-9999").
+					Return(r.GetByPrIdWithReviewersIdsDTO{}, e.ErrPRNotFound)
 			},
 			expectedRes: PullRequestMergeRes{},
 			expectedErr: e.ErrPRNotFound,
@@ -246,12 +428,16 @@ func TestPullRequestUseCase_PullRequestMerge(t *testing.T) {
 			tt.statusRepoSetup(statusRepo)
 			tt.prRepoSetup(prRepo)
 
-			prUC := NewPullRequestUseCase(prRepo, nil, nil, statusRepo, nil)
+			outboxRepo := repoMocks.NewMockOutboxRepository(ctrl)
+			events := recordEvents(outboxRepo)
+
+			prUC := NewPullRequestUseCase(prRepo, nil, nil, nil, statusRepo, outboxRepo, newTxManager(ctrl), DefaultReviewPolicy())
 
 			res, err := prUC.PullRequestMerge(context.Background(), tt.req)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("unexpected error: got %v, want %v", err, tt.expectedErr)
 			}
+			require.Equal(t, tt.expectedEvents, *events)
 			require.Equal(t, tt.expectedRes.PullRequest.Id, res.PullRequest.Id)
 			require.Equal(t, tt.expectedRes.PullRequest.Name, res.PullRequest.Name)
 			require.Equal(t, tt.expectedRes.PullRequest.AuthorId, res.PullRequest.AuthorId)
@@ -268,6 +454,7 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 		name              string
 		input             PullRequestReassignReq
 		userRepoSetup     func(*repoMocks.MockUserRepository)
+		teamRepoSetup     func(*repoMocks.MockTeamRepository)
 		prRepoSetup       func(*repoMocks.MockPullRequestRepository)
 		reviewerRepoSetup func(repository *repoMocks.MockPrReviewerRepository)
 		expectedRes       PullRequestReassignRes
@@ -288,11 +475,14 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 				}, nil)
 
 				repo.EXPECT().
-					GetReassignCandidates(gomock.Any(), "u1", gomock.Any(), gomock.Any()).
+					GetReassignCandidates(gomock.Any(), 1, gomock.Any(), gomock.Any()).
 					Return([]domain.User{
 						{Id: "u4", Name: "newReviewer", IsActive: true, TeamId: 1},
 					}, nil)
 			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil)
+			},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
 				repo.EXPECT().
 					GetByPrIdWithReviewersIds(gomock.Any(), "pr-1001").
@@ -308,6 +498,7 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 						},
 						ReviewersIds: []string{"u2", "u3"},
 						StatusName:   domain.OPEN,
+						ReviewTeamId: 1,
 					}, nil)
 			},
 			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {
@@ -323,7 +514,8 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 					Status:            domain.OPEN,
 					AssignedReviewers: []string{"u2", "u4"},
 				},
-				ReplacedBy: "u4",
+				ReplacedBy:       "u4",
+				ReplacedBySource: ReviewerSourceDTO{UserId: "u4", TeamName: "backend", Source: domain.SourceTeam},
 			},
 			expectedErr: nil,
 		},
@@ -453,9 +645,12 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 				}, nil)
 
 				repo.EXPECT().
-					GetReassignCandidates(gomock.Any(), "u1", gomock.Any(), gomock.Any()).
+					GetReassignCandidates(gomock.Any(), 1, gomock.Any(), gomock.Any()).
 					Return([]domain.User{}, nil)
 			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil)
+			},
 			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
 				repo.EXPECT().
 					GetByPrIdWithReviewersIds(gomock.Any(), "pr-2001").
@@ -471,12 +666,58 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 						},
 						ReviewersIds: []string{"u2", "u3"},
 						StatusName:   domain.OPEN,
+						ReviewTeamId: 1,
 					}, nil)
 			},
 			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {},
 			expectedRes:       PullRequestReassignRes{},
 			expectedErr:       e.ErrPrNoCandidate,
 		},
+		{
+			name: "replacement from parent team",
+			input: PullRequestReassignReq{
+				PullRequestId: "pr-2002",
+				OldReviewerId: "u3",
+			},
+			userRepoSetup: func(repo *repoMocks.MockUserRepository) {
+				repo.EXPECT().GetById(gomock.Any(), "u3").Return(domain.User{Id: "u3", IsActive: true, TeamId: 1}, nil)
+				repo.EXPECT().
+					GetReassignCandidates(gomock.Any(), 1, gomock.Any(), gomock.Any()).
+					Return([]domain.User{}, nil)
+				repo.EXPECT().
+					GetReassignCandidates(gomock.Any(), 5, []string{"u2", "u3", "u1"}, 1).
+					Return([]domain.User{{Id: "u7", IsActive: true, TeamId: 5}}, nil)
+			},
+			teamRepoSetup: func(repo *repoMocks.MockTeamRepository) {
+				repo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend", ParentId: 5, MaxDepth: 2}, nil)
+				repo.EXPECT().GetChildren(gomock.Any(), 5).Return([]domain.Team{{Id: 1, Name: "backend", ParentId: 5}}, nil)
+				repo.EXPECT().GetById(gomock.Any(), 5).Return(domain.Team{Id: 5, Name: "platform"}, nil)
+			},
+			prRepoSetup: func(repo *repoMocks.MockPullRequestRepository) {
+				repo.EXPECT().
+					GetByPrIdWithReviewersIds(gomock.Any(), "pr-2002").
+					Return(r.GetByPrIdWithReviewersIdsDTO{
+						Pr:           domain.PullRequest{Id: "pr-2002", Name: "My PR", AuthorId: "u1", StatusId: 1},
+						ReviewersIds: []string{"u2", "u3"},
+						StatusName:   domain.OPEN,
+						ReviewTeamId: 1,
+					}, nil)
+			},
+			reviewerRepoSetup: func(repo *repoMocks.MockPrReviewerRepository) {
+				repo.EXPECT().UpdateReviewer(gomock.Any(), "u3", "u7", "pr-2002").Return("u7", nil)
+			},
+			expectedRes: PullRequestReassignRes{
+				Pr: PullRequestDTO{
+					Id:                "pr-2002",
+					Name:              "My PR",
+					AuthorId:          "u1",
+					Status:            domain.OPEN,
+					AssignedReviewers: []string{"u2", "u7"},
+				},
+				ReplacedBy:       "u7",
+				ReplacedBySource: ReviewerSourceDTO{UserId: "u7", TeamName: "platform", Source: domain.SourceParent, Level: 1},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -487,15 +728,26 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 			userRepoMock := repoMocks.NewMockUserRepository(ctrl)
 			prRepoMock := repoMocks.NewMockPullRequestRepository(ctrl)
 			reviewerRepoMock := repoMocks.NewMockPrReviewerRepository(ctrl)
+			teamRepoMock := repoMocks.NewMockTeamRepository(ctrl)
 
 			tt.userRepoSetup(userRepoMock)
+			if tt.teamRepoSetup != nil {
+				tt.teamRepoSetup(teamRepoMock)
+			}
 			tt.prRepoSetup(prRepoMock)
 			tt.reviewerRepoSetup(reviewerRepoMock)
 
+			outboxRepoMock := repoMocks.NewMockOutboxRepository(ctrl)
+			events := recordEvents(outboxRepoMock)
+
 			uc := PullRequestUseCase{
 				userRepo:     userRepoMock,
+				teamRepo:     teamRepoMock,
 				prRepo:       prRepoMock,
 				reviewerRepo: reviewerRepoMock,
+				outboxRepo:   outboxRepoMock,
+				txManager:    newTxManager(ctrl),
+				policy:       DefaultReviewPolicy(),
 			}
 
 			res, err := uc.ReviewerReassign(context.Background(), tt.input)
@@ -509,7 +761,11 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 			tt.expectedRes.Pr.MergedAt = nil
 
 			require.Equal(t, tt.expectedRes, res)
-
+			if tt.expectedErr == nil {
+				require.Equal(t, []domain.EventType{domain.EventReviewerReassigned}, *events)
+			} else {
+				require.Empty(t, *events)
+			}
 		})
 	}
 }
@@ -517,3 +773,32 @@ func TestPullRequestUseCase_ReviewerReassign(t *testing.T) {
 func ptr(s time.Time) *time.Time {
 	return &s
 }
+
+func newTxManager(ctrl *gomock.Controller) transaction.Manager {
+	mockTx := trMock.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).AnyTimes()
+	mockTx.EXPECT().Rollback(gomock.Any()).Return(nil).AnyTimes()
+
+	mockTxPool := trMock.NewMockTransactional(ctrl)
+	mockTxPool.EXPECT().
+		BeginTx(gomock.Any(), gomock.Any()).
+		Return(mockTx, nil).
+		AnyTimes()
+
+	return transaction.NewPgxManager(mockTxPool)
+}
+
+// recordEvents makes the outbox mock accept any events and collects their types.
+func recordEvents(repo *repoMocks.MockOutboxRepository) *[]domain.EventType {
+	var types []domain.EventType
+	repo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, events ...domain.Event) error {
+			for _, event := range events {
+				types = append(types, event.Type)
+			}
+			return nil
+		},
+	).AnyTimes()
+
+	return &types
+}
@@ -6,10 +6,11 @@ import (
 	"avito-internship/pkg/e"
 	"avito-internship/pkg/transaction"
 	"context"
+	"maps"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
-
-	"github.com/jackc/pgx/v5"
 )
 
 type TeamUseCase struct {
@@ -18,19 +19,21 @@ type TeamUseCase struct {
 	prRepo       r.PullRequestRepository
 	statusRepo   r.StatusRepository
 	reviewerRepo r.PrReviewerRepository
-	dbPool       transaction.Transactional
+	outboxRepo   r.OutboxRepository
+	txManager    transaction.Manager
 }
 
 func NewTeamUseCase(teamRepo r.TeamRepository, userRepo r.UserRepository,
 	prRepo r.PullRequestRepository, statusRepo r.StatusRepository,
-	dbPool transaction.Transactional, reviewerRepo r.PrReviewerRepository) *TeamUseCase {
+	txManager transaction.Manager, reviewerRepo r.PrReviewerRepository, outboxRepo r.OutboxRepository) *TeamUseCase {
 	return &TeamUseCase{
 		teamRepo:     teamRepo,
 		userRepo:     userRepo,
 		prRepo:       prRepo,
 		statusRepo:   statusRepo,
 		reviewerRepo: reviewerRepo,
-		dbPool:       dbPool,
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
 	}
 }
 
@@ -41,7 +44,7 @@ func (t *TeamUseCase) AddTeam(ctx context.Context, req TeamAddReq) (TeamAddRes,
 		return TeamAddRes{}, e.Wrap(op, e.ErrEmptyMembers)
 	}
 
-	ctx, tx, err := transaction.NewTransaction(ctx, pgx.TxOptions{}, t.dbPool)
+	ctx, tx, err := t.txManager.Begin(ctx)
 	if err != nil {
 		return TeamAddRes{}, e.Wrap(op, err)
 	}
@@ -54,16 +57,19 @@ func (t *TeamUseCase) AddTeam(ctx context.Context, req TeamAddReq) (TeamAddRes,
 		return TeamAddRes{}, e.Wrap(op, err)
 	}
 
-	users := make([]domain.User, 0, len(req.Members))
-	for _, member := range req.Members {
-		users = append(users, TeamMemberDTOtoDomainUser(member))
-	}
+	users := newUsers(req.Members)
 
 	members, err := t.userRepo.AddUsersToTeam(ctx, newTeam.Id, users)
 	if err != nil {
 		return TeamAddRes{}, e.Wrap(op, err)
 	}
 
+	// Users of other teams are moved only by /users/transfer, which also
+	// reassigns their reviews.
+	if len(members) < len(users) {
+		return TeamAddRes{}, e.Wrap(op, e.ErrUserInAnotherTeam)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return TeamAddRes{}, e.Wrap(op, err)
 	}
@@ -91,7 +97,12 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 		return DeactivateMembersRes{}, e.Wrap(op, e.ErrEmptyMembers)
 	}
 
-	allMembers, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, req.TeamName)
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return DeactivateMembersRes{}, e.Wrap(op, err)
+	}
+
+	allMembers, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
 	if err != nil {
 		return DeactivateMembersRes{}, e.Wrap(op, err)
 	}
@@ -132,18 +143,642 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 		return DeactivateMembersRes{}, e.Wrap(op, e.ErrPrNoCandidate)
 	}
 
-	ctx, tx, err := transaction.NewTransaction(ctx, pgx.TxOptions{}, t.dbPool)
+	ctx, tx, err := t.txManager.Begin(ctx)
 	if err != nil {
 		return DeactivateMembersRes{}, e.Wrap(op, err)
 	}
 	defer tx.Rollback(ctx)
 	ctx = context.WithValue(ctx, "tx", tx.Transaction())
 
-	prMap, err := t.prRepo.GetOpenPRsByReviewerIDs(ctx, deactivateMembersIds, status.Id)
+	reassigned, err := t.reassignReviews(ctx, status.Id, team.Id, deactivateMembersIds, idSet, idSet, globalCandidatePool)
 	if err != nil {
 		return DeactivateMembersRes{}, e.Wrap(op, err)
 	}
 
+	updUsers, err := t.userRepo.DeactivateUsers(ctx, deactivateMembersIds)
+	if err != nil {
+		return DeactivateMembersRes{}, e.Wrap(op, err)
+	}
+
+	events := deactivationEvents(req.TeamName, allMembers, idSet, reassigned.changes)
+	if err := t.outboxRepo.Add(ctx, events...); err != nil {
+		return DeactivateMembersRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DeactivateMembersRes{}, e.Wrap(op, err)
+	}
+
+	return NewDeactivateMembersRes(req.TeamName, toArrTeamMemberDTO(updUsers), reassigned.updatedPRs()), nil
+}
+
+// AddMembers adds users to an existing team. Known users without a team are
+// updated and joined; members of other teams are rejected.
+func (t *TeamUseCase) AddMembers(ctx context.Context, req AddMembersReq) (AddMembersRes, error) {
+	const op = "TeamUseCase.AddMembers"
+
+	if len(req.Members) == 0 {
+		return AddMembersRes{}, e.Wrap(op, e.ErrEmptyMembers)
+	}
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return AddMembersRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return AddMembersRes{}, e.Wrap(op, err)
+	}
+
+	users := newUsers(req.Members)
+
+	added, err := t.userRepo.AddUsersToTeam(ctx, team.Id, users)
+	if err != nil {
+		return AddMembersRes{}, e.Wrap(op, err)
+	}
+
+	if len(added) < len(users) {
+		return AddMembersRes{}, e.Wrap(op, e.ErrUserInAnotherTeam)
+	}
+
+	members, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+	if err != nil {
+		return AddMembersRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return AddMembersRes{}, e.Wrap(op, err)
+	}
+
+	return NewAddMembersRes(NewTeamDTO(team.Name, members)), nil
+}
+
+// RemoveMembers takes users out of a team. Their open reviews of the team go to
+// other active members first, as in DeactivateMembers; the users keep their
+// accounts and other memberships.
+func (t *TeamUseCase) RemoveMembers(ctx context.Context, req RemoveMembersReq) (RemoveMembersRes, error) {
+	const op = "TeamUseCase.RemoveMembers"
+
+	if len(req.Members) == 0 {
+		return RemoveMembersRes{}, e.Wrap(op, e.ErrEmptyMembers)
+	}
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	allMembers, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	teamMembers := make(map[string]domain.User, len(allMembers))
+	for _, m := range allMembers {
+		teamMembers[m.Id] = m
+	}
+
+	leaving := make(map[string]struct{}, len(req.Members))
+	for _, id := range req.Members {
+		if _, ok := teamMembers[id]; !ok {
+			return RemoveMembersRes{}, e.Wrap(op, e.ErrInvalidMember)
+		}
+		leaving[id] = struct{}{}
+	}
+
+	candidates := make(map[string]struct{})
+	for _, member := range allMembers {
+		if _, ok := leaving[member.Id]; member.IsActive && !ok {
+			candidates[member.Id] = struct{}{}
+		}
+	}
+
+	status, err := t.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	reassigned, err := t.reassignReviews(ctx, status.Id, team.Id, req.Members, leaving, nil, candidates)
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	removed, err := t.userRepo.RemoveUsersFromTeam(ctx, team.Id, req.Members)
+	if err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	if err := t.outboxRepo.Add(ctx, reassignmentEvents(reassigned.changes)...); err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RemoveMembersRes{}, e.Wrap(op, err)
+	}
+
+	return NewRemoveMembersRes(req.TeamName, toArrTeamMemberDTO(removed), reassigned.updatedPRs()), nil
+}
+
+// SyncTeam makes the team roster match req.Members: new users are added, known
+// members are renamed, reactivated or deactivated, and members missing from the
+// list are removed. Open reviews of deactivated and removed members are
+// reassigned as in DeactivateMembers. A dry run only reads the current roster
+// and reports the diff: it writes nothing, so UpdPrs stays empty and conflicts
+// found while applying the diff are not reported.
+func (t *TeamUseCase) SyncTeam(ctx context.Context, req SyncTeamReq) (SyncTeamRes, error) {
+	const op = "TeamUseCase.SyncTeam"
+
+	if len(req.Members) == 0 {
+		return SyncTeamRes{}, e.Wrap(op, e.ErrEmptyMembers)
+	}
+
+	desired := make(map[string]TeamMemberDTO, len(req.Members))
+	for _, member := range req.Members {
+		// Members are matched by id, so the server cannot generate one here.
+		if member.Id == "" {
+			return SyncTeamRes{}, e.Wrap(op, e.ErrMissingMemberId)
+		}
+		if _, ok := desired[member.Id]; ok {
+			return SyncTeamRes{}, e.Wrap(op, e.ErrDuplicateMembers)
+		}
+		desired[member.Id] = member
+	}
+
+	if req.DryRun {
+		team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return SyncTeamRes{}, e.Wrap(op, err)
+		}
+
+		current, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+		if err != nil {
+			return SyncTeamRes{}, e.Wrap(op, err)
+		}
+
+		return newSyncTeamRes(team.Name, true, current, req.Members), nil
+	}
+
+	status, err := t.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	current, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	res := newSyncTeamRes(team.Name, false, current, req.Members)
+
+	users := newUsers(req.Members)
+
+	synced, err := t.userRepo.AddUsersToTeam(ctx, team.Id, users)
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	if len(synced) < len(users) {
+		return SyncTeamRes{}, e.Wrap(op, e.ErrUserInAnotherTeam)
+	}
+
+	leaving := make(map[string]struct{})
+	leavingIds := make([]string, 0)
+	for _, member := range slices.Concat(res.Deactivated, res.Removed) {
+		leaving[member.Id] = struct{}{}
+		leavingIds = append(leavingIds, member.Id)
+	}
+
+	deactivated := make(map[string]struct{}, len(res.Deactivated))
+	for _, member := range res.Deactivated {
+		deactivated[member.Id] = struct{}{}
+	}
+
+	candidates := make(map[string]struct{})
+	for _, member := range req.Members {
+		if member.IsActive {
+			candidates[member.Id] = struct{}{}
+		}
+	}
+
+	reassigned, err := t.reassignReviews(ctx, status.Id, team.Id, leavingIds, leaving, deactivated, candidates)
+	if err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+	res.UpdPrs = reassigned.updatedPRs()
+
+	if len(res.Removed) > 0 {
+		removedIds := make([]string, 0, len(res.Removed))
+		for _, member := range res.Removed {
+			removedIds = append(removedIds, member.Id)
+		}
+
+		if _, err := t.userRepo.RemoveUsersFromTeam(ctx, team.Id, removedIds); err != nil {
+			return SyncTeamRes{}, e.Wrap(op, err)
+		}
+	}
+
+	events := make([]domain.Event, 0)
+	for _, member := range res.Deactivated {
+		events = append(events, newEvent(domain.EventUserDeactivated, domain.UserDeactivatedPayload{
+			UserId:   member.Id,
+			TeamName: team.Name,
+		}))
+	}
+	events = append(events, reassignmentEvents(reassigned.changes)...)
+	if err := t.outboxRepo.Add(ctx, events...); err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return SyncTeamRes{}, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+// RenameTeam changes the team name; the id and members stay.
+func (t *TeamUseCase) RenameTeam(ctx context.Context, req RenameTeamReq) (RenameTeamRes, error) {
+	const op = "TeamUseCase.RenameTeam"
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return RenameTeamRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return RenameTeamRes{}, e.Wrap(op, err)
+	}
+
+	renamed, err := t.teamRepo.Rename(ctx, team.Id, req.NewTeamName)
+	if err != nil {
+		return RenameTeamRes{}, e.Wrap(op, err)
+	}
+
+	members, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, renamed.Name)
+	if err != nil {
+		return RenameTeamRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RenameTeamRes{}, e.Wrap(op, err)
+	}
+
+	return RenameTeamRes{Team: NewTeamDTO(renamed.Name, members)}, nil
+}
+
+// SetParent places the team under the parent team, or makes it top-level when
+// req.ParentTeamName is empty. req.MaxDepth limits how many levels up reviewer
+// search may widen for PRs of the team.
+func (t *TeamUseCase) SetParent(ctx context.Context, req SetTeamParentReq) (SetTeamParentRes, error) {
+	const op = "TeamUseCase.SetParent"
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return SetTeamParentRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return SetTeamParentRes{}, e.Wrap(op, err)
+	}
+
+	var parent domain.Team
+	if req.ParentTeamName != "" {
+		parent, err = t.teamRepo.GetByName(ctx, req.ParentTeamName)
+		if err != nil {
+			return SetTeamParentRes{}, e.Wrap(op, err)
+		}
+
+		// The team must not be among the ancestors of its new parent.
+		for ancestor := parent; ; {
+			if ancestor.Id == team.Id {
+				return SetTeamParentRes{}, e.Wrap(op, e.ErrTeamCycle)
+			}
+			if ancestor.ParentId == 0 {
+				break
+			}
+
+			ancestor, err = t.teamRepo.GetById(ctx, ancestor.ParentId)
+			if err != nil {
+				return SetTeamParentRes{}, e.Wrap(op, err)
+			}
+		}
+	}
+
+	updated, err := t.teamRepo.SetParent(ctx, team.Id, parent.Id, req.MaxDepth)
+	if err != nil {
+		return SetTeamParentRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return SetTeamParentRes{}, e.Wrap(op, err)
+	}
+
+	return SetTeamParentRes{
+		TeamName:       updated.Name,
+		ParentTeamName: parent.Name,
+		MaxDepth:       updated.MaxDepth,
+	}, nil
+}
+
+// DeleteTeam deletes an empty team, or moves its members to the target team
+// first. Reviews and authored PRs move along with the members. Reviews they hold
+// on open PRs of other teams fail the request with ErrOpenReviews, unless
+// req.ReassignReviews is set; then they go to active members of each PR's team.
+func (t *TeamUseCase) DeleteTeam(ctx context.Context, req DeleteTeamReq) (DeleteTeamRes, error) {
+	const op = "TeamUseCase.DeleteTeam"
+
+	if req.TargetTeamName == req.TeamName {
+		return DeleteTeamRes{}, e.Wrap(op, e.ErrSameTeam)
+	}
+
+	status, err := t.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	ctx, tx, err := t.txManager.Begin(ctx)
+	if err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := t.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	members, err := t.teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+	if err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	var (
+		moved      = make([]domain.User, 0)
+		reassigned = reassignment{}
+	)
+	if len(members) > 0 {
+		if req.TargetTeamName == "" {
+			return DeleteTeamRes{}, e.Wrap(op, e.ErrTeamNotEmpty)
+		}
+
+		target, err := t.teamRepo.GetByName(ctx, req.TargetTeamName)
+		if err != nil {
+			return DeleteTeamRes{}, e.Wrap(op, err)
+		}
+
+		reassigned, err = t.reassignForeignReviews(ctx, status.Id, members, team, target, req.ReassignReviews)
+		if err != nil {
+			return DeleteTeamRes{}, e.Wrap(op, err)
+		}
+
+		moved, err = t.userRepo.MoveTeamMembers(ctx, team.Id, target.Id)
+		if err != nil {
+			return DeleteTeamRes{}, e.Wrap(op, err)
+		}
+	}
+
+	if err := t.teamRepo.Delete(ctx, team.Id); err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	if err := t.outboxRepo.Add(ctx, reassignmentEvents(reassigned.changes)...); err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DeleteTeamRes{}, e.Wrap(op, err)
+	}
+
+	return DeleteTeamRes{
+		TeamName:       team.Name,
+		TargetTeamName: req.TargetTeamName,
+		MovedMembers:   toArrTeamMemberDTO(moved),
+		UpdPrs:         reassigned.updatedPRs(),
+	}, nil
+}
+
+// reassignForeignReviews handles reviews the members hold on open PRs of teams
+// other than the deleted and the target one.
+func (t *TeamUseCase) reassignForeignReviews(ctx context.Context, openStatusId int, members []domain.User,
+	team, target domain.Team, reassign bool) (reassignment, error) {
+	memberSet := make(map[string]struct{}, len(members))
+	memberIds := make([]string, 0, len(members))
+	for _, member := range members {
+		memberSet[member.Id] = struct{}{}
+		memberIds = append(memberIds, member.Id)
+	}
+
+	prMap, err := t.prRepo.GetOpenPRsByReviewerIDs(ctx, memberIds, openStatusId)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	maps.DeleteFunc(prMap, func(_ string, pr r.GetOpenPRsByReviewerIDsDTO) bool {
+		return pr.ReviewTeamId == team.Id || pr.ReviewTeamId == target.Id
+	})
+
+	if len(prMap) == 0 {
+		return reassignment{}, nil
+	}
+	if !reassign {
+		return reassignment{}, e.ErrOpenReviews
+	}
+
+	return reassignWithinTeams(ctx, t.teamRepo, t.reviewerRepo, prMap, memberSet, false)
+}
+
+// newUsers builds the users to add, generating ids for members without one.
+func newUsers(members []TeamMemberDTO) []domain.User {
+	users := make([]domain.User, 0, len(members))
+	for _, member := range members {
+		user := TeamMemberDTOtoDomainUser(member)
+		if user.Id == "" {
+			user.Id = domain.NewUserId()
+		}
+		users = append(users, user)
+	}
+
+	return users
+}
+
+// newSyncTeamRes computes the diff between the current members and the desired
+// roster. A member can be both renamed and reactivated or deactivated.
+func newSyncTeamRes(teamName string, dryRun bool, current []domain.User, desired []TeamMemberDTO) SyncTeamRes {
+	res := SyncTeamRes{
+		TeamName:    teamName,
+		DryRun:      dryRun,
+		Added:       make([]TeamMemberDTO, 0),
+		Reactivated: make([]TeamMemberDTO, 0),
+		Renamed:     make([]RenamedMemberDTO, 0),
+		Deactivated: make([]TeamMemberDTO, 0),
+		Removed:     make([]TeamMemberDTO, 0),
+		UpdPrs:      make([]PullRequestDTO, 0),
+	}
+
+	members := make(map[string]domain.User, len(current))
+	for _, member := range current {
+		members[member.Id] = member
+	}
+
+	wanted := make(map[string]struct{}, len(desired))
+	for _, member := range desired {
+		wanted[member.Id] = struct{}{}
+
+		was, ok := members[member.Id]
+		if !ok {
+			res.Added = append(res.Added, member)
+			continue
+		}
+
+		if was.Name != member.Username {
+			res.Renamed = append(res.Renamed, RenamedMemberDTO{
+				Id:          member.Id,
+				OldUsername: was.Name,
+				NewUsername: member.Username,
+			})
+		}
+
+		switch {
+		case !was.IsActive && member.IsActive:
+			res.Reactivated = append(res.Reactivated, member)
+		case was.IsActive && !member.IsActive:
+			res.Deactivated = append(res.Deactivated, member)
+		}
+	}
+
+	for _, member := range current {
+		if _, ok := wanted[member.Id]; !ok {
+			res.Removed = append(res.Removed, toTeamMemberDTO(member))
+		}
+	}
+	slices.SortFunc(res.Removed, func(a, b TeamMemberDTO) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return res
+}
+
+// reassignment is the result of reassignReviews.
+type reassignment struct {
+	prs       map[string]r.GetOpenPRsByReviewerIDsDTO
+	reviewers map[string][]string
+	changes   map[string]r.PrReviewerChange
+}
+
+// reassignReviews replaces the leaving members on every open PR of the team
+// they review with random candidates who are neither the author nor already
+// reviewing it. Deactivated members are replaced on PRs of other teams too, by
+// active members of those teams. It fails with ErrPrNoCandidate when a PR
+// cannot be fully reassigned.
+func (t *TeamUseCase) reassignReviews(ctx context.Context, openStatusId, teamId int, leavingIds []string,
+	leaving, deactivated, candidates map[string]struct{}) (reassignment, error) {
+	prMap, err := t.prRepo.GetOpenPRsByReviewerIDs(ctx, leavingIds, openStatusId)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	others := maps.Clone(prMap)
+	maps.DeleteFunc(prMap, func(_ string, pr r.GetOpenPRsByReviewerIDsDTO) bool {
+		return pr.ReviewTeamId != teamId
+	})
+	maps.DeleteFunc(others, func(_ string, pr r.GetOpenPRsByReviewerIDsDTO) bool {
+		return pr.ReviewTeamId == teamId
+	})
+
+	res, err := replaceReviewers(ctx, t.reviewerRepo, prMap, leaving, candidates, false)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	if len(deactivated) == 0 || len(others) == 0 {
+		return res, nil
+	}
+
+	rest, err := reassignWithinTeams(ctx, t.teamRepo, t.reviewerRepo, others, deactivated, false)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	return res.merge(rest), nil
+}
+
+// reassignWithinTeams replaces the leaving reviewers of prMap with active
+// members of each PR's team. PRs without a team have no candidates. partial is
+// passed on to replaceReviewers.
+func reassignWithinTeams(ctx context.Context, teamRepo r.TeamRepository, reviewerRepo r.PrReviewerRepository,
+	prMap map[string]r.GetOpenPRsByReviewerIDsDTO, leaving map[string]struct{}, partial bool) (reassignment, error) {
+	byTeam := make(map[int]map[string]r.GetOpenPRsByReviewerIDsDTO)
+	for prId, pr := range prMap {
+		if byTeam[pr.ReviewTeamId] == nil {
+			byTeam[pr.ReviewTeamId] = make(map[string]r.GetOpenPRsByReviewerIDsDTO)
+		}
+		byTeam[pr.ReviewTeamId][prId] = pr
+	}
+
+	res := reassignment{}
+	for teamId, prs := range byTeam {
+		candidates := make(map[string]struct{})
+		if teamId != 0 {
+			team, err := teamRepo.GetById(ctx, teamId)
+			if err != nil {
+				return reassignment{}, err
+			}
+
+			members, err := teamRepo.GetMembersByTeamNameWithUsers(ctx, team.Name)
+			if err != nil {
+				return reassignment{}, err
+			}
+			for _, user := range members {
+				if _, ok := leaving[user.Id]; user.IsActive && !ok {
+					candidates[user.Id] = struct{}{}
+				}
+			}
+		}
+
+		part, err := replaceReviewers(ctx, reviewerRepo, prs, leaving, candidates, partial)
+		if err != nil {
+			return reassignment{}, err
+		}
+		res = res.merge(part)
+	}
+
+	return res, nil
+}
+
+// replaceReviewers swaps the leaving reviewers of prMap for random candidates and
+// stores the changes. When a PR lacks candidates it fails with ErrPrNoCandidate,
+// or with partial set replaces as many reviewers as it can.
+func replaceReviewers(ctx context.Context, reviewerRepo r.PrReviewerRepository, prMap map[string]r.GetOpenPRsByReviewerIDsDTO,
+	leaving map[string]struct{}, candidates map[string]struct{}, partial bool) (reassignment, error) {
 	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	prUpdates := make(map[string][]string)
@@ -157,7 +792,7 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 		activeReviewersOnPR := make([]string, 0)
 
 		for _, reviewerId := range allReviewersForThisPR {
-			if _, isDeactivating := idSet[reviewerId]; isDeactivating {
+			if _, isLeaving := leaving[reviewerId]; isLeaving {
 				reviewersToReplace = append(reviewersToReplace, reviewerId)
 			} else {
 				activeReviewersOnPR = append(activeReviewersOnPR, reviewerId)
@@ -168,29 +803,31 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 			continue
 		}
 
-		prCandidatePool := make([]string, 0)
-		for id := range globalCandidatePool {
-			if id != pr.AuthorId {
-				prCandidatePool = append(prCandidatePool, id)
-			}
-		}
-
 		existingSet := make(map[string]struct{})
 		for _, r := range allReviewersForThisPR {
 			existingSet[r] = struct{}{}
 		}
 
 		cleanCandidates := make([]string, 0)
-		for _, id := range prCandidatePool {
-			if _, exists := existingSet[id]; !exists {
+		for id := range candidates {
+			if _, exists := existingSet[id]; !exists && id != pr.AuthorId {
 				cleanCandidates = append(cleanCandidates, id)
 			}
 		}
 
 		if len(cleanCandidates) < len(reviewersToReplace) {
-			return DeactivateMembersRes{}, e.Wrap(op, e.ErrPrNoCandidate)
+			if !partial {
+				return reassignment{}, e.ErrPrNoCandidate
+			}
+			activeReviewersOnPR = append(activeReviewersOnPR, reviewersToReplace[len(cleanCandidates):]...)
+			reviewersToReplace = reviewersToReplace[:len(cleanCandidates)]
+			if len(reviewersToReplace) == 0 {
+				continue
+			}
 		}
 
+		// Map iteration order is random, so sort before shuffling with rd.
+		slices.Sort(cleanCandidates)
 		rd.Shuffle(len(cleanCandidates), func(i, j int) {
 			cleanCandidates[i], cleanCandidates[j] = cleanCandidates[j], cleanCandidates[i]
 		})
@@ -204,24 +841,34 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 	}
 
 	if len(prChanges) > 0 {
-		err = t.reviewerRepo.UpdateReviewers(ctx, prChanges)
-		if err != nil {
-			return DeactivateMembersRes{}, e.Wrap(op, err)
+		if err := reviewerRepo.UpdateReviewers(ctx, prChanges); err != nil {
+			return reassignment{}, err
 		}
 	}
 
-	updUsers, err := t.userRepo.DeactivateUsers(ctx, deactivateMembersIds)
-	if err != nil {
-		return DeactivateMembersRes{}, e.Wrap(op, err)
-	}
+	return reassignment{prs: prMap, reviewers: prUpdates, changes: prChanges}, nil
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return DeactivateMembersRes{}, e.Wrap(op, err)
+// merge returns the union of both reassignments, which cover different PRs.
+func (a reassignment) merge(b reassignment) reassignment {
+	res := reassignment{
+		prs:       make(map[string]r.GetOpenPRsByReviewerIDsDTO, len(a.prs)+len(b.prs)),
+		reviewers: make(map[string][]string, len(a.reviewers)+len(b.reviewers)),
+		changes:   make(map[string]r.PrReviewerChange, len(a.changes)+len(b.changes)),
+	}
+	for _, part := range []reassignment{a, b} {
+		maps.Copy(res.prs, part.prs)
+		maps.Copy(res.reviewers, part.reviewers)
+		maps.Copy(res.changes, part.changes)
 	}
 
-	updatedPRs := make([]PullRequestDTO, 0, len(prUpdates))
-	for prId, reviewers := range prUpdates {
-		data, exists := prMap[prId]
+	return res
+}
+
+func (a reassignment) updatedPRs() []PullRequestDTO {
+	updatedPRs := make([]PullRequestDTO, 0, len(a.reviewers))
+	for prId, reviewers := range a.reviewers {
+		data, exists := a.prs[prId]
 		if !exists {
 			continue
 		}
@@ -251,5 +898,49 @@ func (t *TeamUseCase) DeactivateMembers(ctx context.Context, req DeactivateMembe
 		updatedPRs = append(updatedPRs, dto)
 	}
 
-	return NewDeactivateMembersRes(req.TeamName, toArrTeamMemberDTO(updUsers), updatedPRs), nil
+	return updatedPRs
+}
+
+// deactivationEvents reports members that were active before and every replaced
+// reviewer.
+func deactivationEvents(teamName string, members []domain.User, deactivated map[string]struct{},
+	changes map[string]r.PrReviewerChange) []domain.Event {
+	events := make([]domain.Event, 0)
+
+	for _, member := range members {
+		if _, ok := deactivated[member.Id]; ok && member.IsActive {
+			events = append(events, newEvent(domain.EventUserDeactivated, domain.UserDeactivatedPayload{
+				UserId:   member.Id,
+				TeamName: teamName,
+			}))
+		}
+	}
+
+	return append(events, reassignmentEvents(changes)...)
+}
+
+// reassignmentEvents reports every replaced reviewer and every reviewer removed
+// without a replacement, visiting PRs in id order.
+func reassignmentEvents(changes map[string]r.PrReviewerChange) []domain.Event {
+	events := make([]domain.Event, 0)
+
+	for _, prId := range slices.Sorted(maps.Keys(changes)) {
+		change := changes[prId]
+		for i := range change.ToRemove {
+			if i >= len(change.ToAdd) {
+				events = append(events, newEvent(domain.EventReviewerUnassigned, domain.ReviewerUnassignedPayload{
+					PullRequestId: prId,
+					ReviewerId:    change.ToRemove[i],
+				}))
+				continue
+			}
+			events = append(events, newEvent(domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{
+				PullRequestId: prId,
+				OldReviewerId: change.ToRemove[i],
+				NewReviewerId: change.ToAdd[i],
+			}))
+		}
+	}
+
+	return events
 }
@@ -7,7 +7,9 @@ import (
 	"errors"
 	"testing"
 
+	r "avito-internship/internal/repository"
 	repoMocks "avito-internship/internal/repository/mocks"
+	"avito-internship/pkg/transaction"
 	trMock "avito-internship/pkg/transaction/mocks"
 
 	"github.com/stretchr/testify/require"
@@ -42,8 +44,8 @@ func TestTeamUseCase_AddTeam(t *testing.T) {
 						domain.NewTeam("test"),
 					).
 					Return(domain.Team{
-						1,
-						"test",
+						Id:   1,
+						Name: "test",
 					}, nil)
 			},
 			userRepoSetup: func(userRepo *repoMocks.MockUserRepository) {
@@ -80,6 +82,31 @@ func TestTeamUseCase_AddTeam(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "user in another team",
+			input: TeamAddReq{
+				TeamName: "test",
+				Members: []TeamMemberDTO{
+					{
+						Id:       "u1",
+						Username: "test1",
+						IsActive: true,
+					},
+				},
+			},
+			teamRepoSetup: func(teamRepo *repoMocks.MockTeamRepository) {
+				teamRepo.EXPECT().
+					Create(gomock.Any(), domain.NewTeam("test")).
+					Return(domain.Team{Id: 1, Name: "test"}, nil)
+			},
+			userRepoSetup: func(userRepo *repoMocks.MockUserRepository) {
+				userRepo.EXPECT().
+					AddUsersToTeam(gomock.Any(), 1, gomock.Any()).
+					Return([]domain.User{}, nil)
+			},
+			expectedRes: TeamAddRes{},
+			expectedErr: e.ErrUserInAnotherTeam,
+		},
 		{
 			name: "team exists",
 			input: TeamAddReq{
@@ -135,7 +162,7 @@ func TestTeamUseCase_AddTeam(t *testing.T) {
 				Return(mockTx, nil).
 				AnyTimes()
 
-			teamUC := NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, mockTxPool, reviewerRepo)
+			teamUC := NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, transaction.NewPgxManager(mockTxPool), reviewerRepo, nil)
 			tt.teamRepoSetup(teamRepo)
 			tt.userRepoSetup(userRepo)
 
@@ -229,7 +256,7 @@ func TestTeamUseCase_GetTeam(t *testing.T) {
 				Return(mockTx, nil).
 				AnyTimes()
 
-			teamUC := NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, mockTxPool, reviewerRepo)
+			teamUC := NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, transaction.NewPgxManager(mockTxPool), reviewerRepo, nil)
 			tt.teamRepoSetup(teamRepo)
 
 			res, err := teamUC.GetTeam(context.Background(), tt.input)
@@ -241,3 +268,116 @@ func TestTeamUseCase_GetTeam(t *testing.T) {
 		})
 	}
 }
+
+func TestDeactivationEvents(t *testing.T) {
+	members := []domain.User{
+		{Id: "u1", IsActive: true},
+		{Id: "u2", IsActive: true},
+		{Id: "u3", IsActive: false},
+		{Id: "u4", IsActive: true},
+	}
+	deactivated := map[string]struct{}{"u2": {}, "u3": {}}
+	changes := map[string]r.PrReviewerChange{
+		"pr-2": {ToAdd: []string{"u4"}, ToRemove: []string{"u2"}},
+		"pr-1": {ToAdd: []string{"u1"}, ToRemove: []string{"u2"}},
+	}
+
+	events := deactivationEvents("backend", members, deactivated, changes)
+
+	expected := []domain.Event{
+		newEvent(domain.EventUserDeactivated, domain.UserDeactivatedPayload{UserId: "u2", TeamName: "backend"}),
+		newEvent(domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{PullRequestId: "pr-1", OldReviewerId: "u2", NewReviewerId: "u1"}),
+		newEvent(domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{PullRequestId: "pr-2", OldReviewerId: "u2", NewReviewerId: "u4"}),
+	}
+	require.Equal(t, expected, events)
+}
+
+func TestReassignmentEvents(t *testing.T) {
+	changes := map[string]r.PrReviewerChange{
+		"pr-2": {ToRemove: []string{"u2"}},
+		"pr-1": {ToAdd: []string{"u4"}, ToRemove: []string{"u2", "u3"}},
+	}
+
+	events := reassignmentEvents(changes)
+
+	expected := []domain.Event{
+		newEvent(domain.EventReviewerReassigned, domain.ReviewerReassignedPayload{PullRequestId: "pr-1", OldReviewerId: "u2", NewReviewerId: "u4"}),
+		newEvent(domain.EventReviewerUnassigned, domain.ReviewerUnassignedPayload{PullRequestId: "pr-1", ReviewerId: "u3"}),
+		newEvent(domain.EventReviewerUnassigned, domain.ReviewerUnassignedPayload{PullRequestId: "pr-2", ReviewerId: "u2"}),
+	}
+	require.Equal(t, expected, events)
+}
+
+func TestNewSyncTeamRes(t *testing.T) {
+	current := []domain.User{
+		{Id: "u1", Name: "Alice", IsActive: true},
+		{Id: "u2", Name: "Bob", IsActive: false},
+		{Id: "u3", Name: "Carol", IsActive: true},
+		{Id: "u5", Name: "Eve", IsActive: true},
+		{Id: "u4", Name: "Dave", IsActive: true},
+	}
+	desired := []TeamMemberDTO{
+		{Id: "u1", Username: "Alicia", IsActive: true},
+		{Id: "u2", Username: "Robert", IsActive: true},
+		{Id: "u3", Username: "Carol", IsActive: false},
+		{Id: "u6", Username: "Frank", IsActive: true},
+	}
+
+	res := newSyncTeamRes("backend", true, current, desired)
+
+	require.Equal(t, SyncTeamRes{
+		TeamName:    "backend",
+		DryRun:      true,
+		Added:       []TeamMemberDTO{{Id: "u6", Username: "Frank", IsActive: true}},
+		Reactivated: []TeamMemberDTO{{Id: "u2", Username: "Robert", IsActive: true}},
+		Renamed: []RenamedMemberDTO{
+			{Id: "u1", OldUsername: "Alice", NewUsername: "Alicia"},
+			{Id: "u2", OldUsername: "Bob", NewUsername: "Robert"},
+		},
+		Deactivated: []TeamMemberDTO{{Id: "u3", Username: "Carol", IsActive: false}},
+		Removed: []TeamMemberDTO{
+			{Id: "u4", Username: "Dave", IsActive: true},
+			{Id: "u5", Username: "Eve", IsActive: true},
+		},
+		UpdPrs: []PullRequestDTO{},
+	}, res)
+}
+
+func TestTeamUseCase_SyncTeamDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamRepo := repoMocks.NewMockTeamRepository(ctrl)
+	userRepo := repoMocks.NewMockUserRepository(ctrl)
+	statusRepo := repoMocks.NewMockStatusRepository(ctrl)
+	reviewerRepo := repoMocks.NewMockPrReviewerRepository(ctrl)
+	prRepo := repoMocks.NewMockPullRequestRepository(ctrl)
+
+	// A dry run opens no transaction and writes nothing.
+	mockTxPool := trMock.NewMockTransactional(ctrl)
+
+	teamRepo.EXPECT().GetByName(gomock.Any(), "backend").Return(domain.Team{Id: 1, Name: "backend"}, nil)
+	teamRepo.EXPECT().GetMembersByTeamNameWithUsers(gomock.Any(), "backend").Return([]domain.User{
+		{Id: "u1", Name: "Alice", IsActive: true},
+		{Id: "u2", Name: "Bob", IsActive: true},
+	}, nil)
+
+	teamUC := NewTeamUseCase(teamRepo, userRepo, prRepo, statusRepo, transaction.NewPgxManager(mockTxPool), reviewerRepo, nil)
+	res, err := teamUC.SyncTeam(context.Background(), SyncTeamReq{
+		TeamName: "backend",
+		Members:  []TeamMemberDTO{{Id: "u1", Username: "Alice", IsActive: false}},
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, SyncTeamRes{
+		TeamName:    "backend",
+		DryRun:      true,
+		Added:       []TeamMemberDTO{},
+		Reactivated: []TeamMemberDTO{},
+		Renamed:     []RenamedMemberDTO{},
+		Deactivated: []TeamMemberDTO{{Id: "u1", Username: "Alice", IsActive: false}},
+		Removed:     []TeamMemberDTO{{Id: "u2", Username: "Bob", IsActive: true}},
+		UpdPrs:      []PullRequestDTO{},
+	}, res)
+}
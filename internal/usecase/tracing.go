@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The traced decorators wrap every usecase method in a span. They are only
+// installed when tracing is enabled, so the plain usecases pay nothing for it.
+
+type tracedUserUC struct {
+	next   UserUC
+	tracer trace.Tracer
+}
+
+func NewTracedUserUC(next UserUC, tracer trace.Tracer) UserUC {
+	return &tracedUserUC{next: next, tracer: tracer}
+}
+
+func (t *tracedUserUC) SetIsActive(ctx context.Context, req SetIsActiveReq) (SetIsActiveRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.SetIsActive", func(ctx context.Context) (SetIsActiveRes, error) {
+		return t.next.SetIsActive(ctx, req)
+	})
+}
+
+func (t *tracedUserUC) GetReview(ctx context.Context, userId string) (GetReviewRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.GetReview", func(ctx context.Context) (GetReviewRes, error) {
+		return t.next.GetReview(ctx, userId)
+	})
+}
+
+func (t *tracedUserUC) Transfer(ctx context.Context, req TransferUserReq) (TransferUserRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.Transfer", func(ctx context.Context) (TransferUserRes, error) {
+		return t.next.Transfer(ctx, req)
+	})
+}
+
+func (t *tracedUserUC) Anonymize(ctx context.Context, req AnonymizeUserReq) (AnonymizeUserRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.Anonymize", func(ctx context.Context) (AnonymizeUserRes, error) {
+		return t.next.Anonymize(ctx, req)
+	})
+}
+
+func (t *tracedUserUC) JoinTeam(ctx context.Context, req JoinTeamReq) (JoinTeamRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.JoinTeam", func(ctx context.Context) (JoinTeamRes, error) {
+		return t.next.JoinTeam(ctx, req)
+	})
+}
+
+func (t *tracedUserUC) GetUser(ctx context.Context, userId string) (GetUserRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.GetUser", func(ctx context.Context) (GetUserRes, error) {
+		return t.next.GetUser(ctx, userId)
+	})
+}
+
+func (t *tracedUserUC) ListUsers(ctx context.Context, req ListUsersReq) (ListUsersRes, error) {
+	return traced(ctx, t.tracer, "UserUseCase.ListUsers", func(ctx context.Context) (ListUsersRes, error) {
+		return t.next.ListUsers(ctx, req)
+	})
+}
+
+type tracedTeamUC struct {
+	next   TeamUC
+	tracer trace.Tracer
+}
+
+func NewTracedTeamUC(next TeamUC, tracer trace.Tracer) TeamUC {
+	return &tracedTeamUC{next: next, tracer: tracer}
+}
+
+func (t *tracedTeamUC) AddTeam(ctx context.Context, req TeamAddReq) (TeamAddRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.AddTeam", func(ctx context.Context) (TeamAddRes, error) {
+		return t.next.AddTeam(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) GetTeam(ctx context.Context, teamName string) (GetTeamRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.GetTeam", func(ctx context.Context) (GetTeamRes, error) {
+		return t.next.GetTeam(ctx, teamName)
+	})
+}
+
+func (t *tracedTeamUC) DeactivateMembers(ctx context.Context, req DeactivateMembersReq) (DeactivateMembersRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.DeactivateMembers", func(ctx context.Context) (DeactivateMembersRes, error) {
+		return t.next.DeactivateMembers(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) AddMembers(ctx context.Context, req AddMembersReq) (AddMembersRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.AddMembers", func(ctx context.Context) (AddMembersRes, error) {
+		return t.next.AddMembers(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) RemoveMembers(ctx context.Context, req RemoveMembersReq) (RemoveMembersRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.RemoveMembers", func(ctx context.Context) (RemoveMembersRes, error) {
+		return t.next.RemoveMembers(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) SyncTeam(ctx context.Context, req SyncTeamReq) (SyncTeamRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.SyncTeam", func(ctx context.Context) (SyncTeamRes, error) {
+		return t.next.SyncTeam(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) RenameTeam(ctx context.Context, req RenameTeamReq) (RenameTeamRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.RenameTeam", func(ctx context.Context) (RenameTeamRes, error) {
+		return t.next.RenameTeam(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) SetParent(ctx context.Context, req SetTeamParentReq) (SetTeamParentRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.SetParent", func(ctx context.Context) (SetTeamParentRes, error) {
+		return t.next.SetParent(ctx, req)
+	})
+}
+
+func (t *tracedTeamUC) DeleteTeam(ctx context.Context, req DeleteTeamReq) (DeleteTeamRes, error) {
+	return traced(ctx, t.tracer, "TeamUseCase.DeleteTeam", func(ctx context.Context) (DeleteTeamRes, error) {
+		return t.next.DeleteTeam(ctx, req)
+	})
+}
+
+type tracedPullRequestUC struct {
+	next   PullRequestUC
+	tracer trace.Tracer
+}
+
+func NewTracedPullRequestUC(next PullRequestUC, tracer trace.Tracer) PullRequestUC {
+	return &tracedPullRequestUC{next: next, tracer: tracer}
+}
+
+func (t *tracedPullRequestUC) PullRequestCreate(ctx context.Context, req CreatePullRequestReq) (CreatePullRequestRes, error) {
+	return traced(ctx, t.tracer, "PullRequestUseCase.PullRequestCreate", func(ctx context.Context) (CreatePullRequestRes, error) {
+		return t.next.PullRequestCreate(ctx, req)
+	})
+}
+
+func (t *tracedPullRequestUC) PullRequestMerge(ctx context.Context, req PullRequestMergeReq) (PullRequestMergeRes, error) {
+	return traced(ctx, t.tracer, "PullRequestUseCase.PullRequestMerge", func(ctx context.Context) (PullRequestMergeRes, error) {
+		return t.next.PullRequestMerge(ctx, req)
+	})
+}
+
+func (t *tracedPullRequestUC) ReviewerReassign(ctx context.Context, req PullRequestReassignReq) (PullRequestReassignRes, error) {
+	return traced(ctx, t.tracer, "PullRequestUseCase.ReviewerReassign", func(ctx context.Context) (PullRequestReassignRes, error) {
+		return t.next.ReviewerReassign(ctx, req)
+	})
+}
+
+type tracedWebhookUC struct {
+	next   WebhookUC
+	tracer trace.Tracer
+}
+
+func NewTracedWebhookUC(next WebhookUC, tracer trace.Tracer) WebhookUC {
+	return &tracedWebhookUC{next: next, tracer: tracer}
+}
+
+func (t *tracedWebhookUC) CreateWebhook(ctx context.Context, req CreateWebhookReq) (CreateWebhookRes, error) {
+	return traced(ctx, t.tracer, "WebhookUseCase.CreateWebhook", func(ctx context.Context) (CreateWebhookRes, error) {
+		return t.next.CreateWebhook(ctx, req)
+	})
+}
+
+func (t *tracedWebhookUC) ListWebhooks(ctx context.Context) (ListWebhooksRes, error) {
+	return traced(ctx, t.tracer, "WebhookUseCase.ListWebhooks", func(ctx context.Context) (ListWebhooksRes, error) {
+		return t.next.ListWebhooks(ctx)
+	})
+}
+
+func (t *tracedWebhookUC) DeleteWebhook(ctx context.Context, id int) error {
+	_, err := traced(ctx, t.tracer, "WebhookUseCase.DeleteWebhook", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, t.next.DeleteWebhook(ctx, id)
+	})
+	return err
+}
+
+func (t *tracedWebhookUC) EnableWebhook(ctx context.Context, id int) (EnableWebhookRes, error) {
+	return traced(ctx, t.tracer, "WebhookUseCase.EnableWebhook", func(ctx context.Context) (EnableWebhookRes, error) {
+		return t.next.EnableWebhook(ctx, id)
+	})
+}
+
+func (t *tracedWebhookUC) GetWebhookDeliveries(ctx context.Context, req GetWebhookDeliveriesReq) (GetWebhookDeliveriesRes, error) {
+	return traced(ctx, t.tracer, "WebhookUseCase.GetWebhookDeliveries", func(ctx context.Context) (GetWebhookDeliveriesRes, error) {
+		return t.next.GetWebhookDeliveries(ctx, req)
+	})
+}
+
+func traced[T any](ctx context.Context, tracer trace.Tracer, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := tracer.Start(ctx, name)
+	defer span.End()
+
+	res, err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/repository/mocks"
+	"avito-internship/pkg/e"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracedTeamUC_GetTeam(t *testing.T) {
+	tests := []struct {
+		name           string
+		teamRepoSetup  func(*mocks.MockTeamRepository)
+		expectedStatus codes.Code
+		expectedErr    error
+	}{
+		{
+			name: "success",
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().
+					GetMembersByTeamNameWithUsers(gomock.Any(), "backend").
+					Return([]domain.User{{Id: "u1", Name: "Alice", IsActive: true}}, nil)
+			},
+			expectedStatus: codes.Unset,
+		},
+		{
+			name: "error is recorded on the span",
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().
+					GetMembersByTeamNameWithUsers(gomock.Any(), "backend").
+					Return(nil, e.ErrTeamNotFound)
+			},
+			expectedStatus: codes.Error,
+			expectedErr:    e.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			teamRepo := mocks.NewMockTeamRepository(ctrl)
+			tt.teamRepoSetup(teamRepo)
+
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			teamUC := NewTracedTeamUC(NewTeamUseCase(teamRepo, nil, nil, nil, nil, nil, nil), provider.Tracer("usecase"))
+
+			_, err := teamUC.GetTeam(context.Background(), "backend")
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			require.Equal(t, "TeamUseCase.GetTeam", spans[0].Name())
+			require.Equal(t, tt.expectedStatus, spans[0].Status().Code)
+		})
+	}
+}
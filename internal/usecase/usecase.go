@@ -5,12 +5,23 @@ import "context"
 type UserUC interface {
 	SetIsActive(ctx context.Context, req SetIsActiveReq) (SetIsActiveRes, error)
 	GetReview(ctx context.Context, userId string) (GetReviewRes, error)
+	Transfer(ctx context.Context, req TransferUserReq) (TransferUserRes, error)
+	JoinTeam(ctx context.Context, req JoinTeamReq) (JoinTeamRes, error)
+	GetUser(ctx context.Context, userId string) (GetUserRes, error)
+	ListUsers(ctx context.Context, req ListUsersReq) (ListUsersRes, error)
+	Anonymize(ctx context.Context, req AnonymizeUserReq) (AnonymizeUserRes, error)
 }
 
 type TeamUC interface {
 	AddTeam(ctx context.Context, req TeamAddReq) (TeamAddRes, error)
 	GetTeam(ctx context.Context, teamName string) (GetTeamRes, error)
 	DeactivateMembers(ctx context.Context, req DeactivateMembersReq) (DeactivateMembersRes, error)
+	AddMembers(ctx context.Context, req AddMembersReq) (AddMembersRes, error)
+	RemoveMembers(ctx context.Context, req RemoveMembersReq) (RemoveMembersRes, error)
+	SyncTeam(ctx context.Context, req SyncTeamReq) (SyncTeamRes, error)
+	RenameTeam(ctx context.Context, req RenameTeamReq) (RenameTeamRes, error)
+	SetParent(ctx context.Context, req SetTeamParentReq) (SetTeamParentRes, error)
+	DeleteTeam(ctx context.Context, req DeleteTeamReq) (DeleteTeamRes, error)
 }
 
 type PullRequestUC interface {
@@ -18,3 +29,11 @@ type PullRequestUC interface {
 	PullRequestMerge(ctx context.Context, req PullRequestMergeReq) (PullRequestMergeRes, error)
 	ReviewerReassign(ctx context.Context, req PullRequestReassignReq) (PullRequestReassignRes, error)
 }
+
+type WebhookUC interface {
+	CreateWebhook(ctx context.Context, req CreateWebhookReq) (CreateWebhookRes, error)
+	ListWebhooks(ctx context.Context) (ListWebhooksRes, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	EnableWebhook(ctx context.Context, id int) (EnableWebhookRes, error)
+	GetWebhookDeliveries(ctx context.Context, req GetWebhookDeliveriesReq) (GetWebhookDeliveriesRes, error)
+}
@@ -1,39 +1,83 @@
 package usecase
 
 import (
+	"avito-internship/internal/domain"
 	r "avito-internship/internal/repository"
 	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
 	"context"
+	"maps"
+	"slices"
 )
 
+const defaultUsersLimit = 50
+
 type UserUseCase struct {
 	reviewerRepo r.PrReviewerRepository
 	userRepo     r.UserRepository
 	teamRepo     r.TeamRepository
+	prRepo       r.PullRequestRepository
+	statusRepo   r.StatusRepository
+	outboxRepo   r.OutboxRepository
+	txManager    transaction.Manager
 }
 
-func NewUserUseCase(reviewerRepo r.PrReviewerRepository, userRepo r.UserRepository, teamRepo r.TeamRepository) *UserUseCase {
+func NewUserUseCase(reviewerRepo r.PrReviewerRepository, userRepo r.UserRepository, teamRepo r.TeamRepository,
+	prRepo r.PullRequestRepository, statusRepo r.StatusRepository, outboxRepo r.OutboxRepository,
+	txManager transaction.Manager) *UserUseCase {
 	return &UserUseCase{
 		reviewerRepo: reviewerRepo,
 		userRepo:     userRepo,
 		teamRepo:     teamRepo,
+		prRepo:       prRepo,
+		statusRepo:   statusRepo,
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
 	}
 }
 
 func (u *UserUseCase) SetIsActive(ctx context.Context, req SetIsActiveReq) (SetIsActiveRes, error) {
 	const op = "UserUseCase.SetIsActive"
 
+	ctx, tx, err := u.txManager.Begin(ctx)
+	if err != nil {
+		return SetIsActiveRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	user, err := u.userRepo.GetById(ctx, req.UserId)
+	if err != nil {
+		return SetIsActiveRes{}, e.Wrap(op, err)
+	}
+
 	updUser, err := u.userRepo.UpdateIsActive(ctx, req.UserId, req.IsActive)
 	if err != nil {
 		return SetIsActiveRes{}, e.Wrap(op, err)
 	}
 
-	team, err := u.teamRepo.GetTeamByUserId(ctx, updUser.Id)
+	// Users removed from every team keep their account but have no team name.
+	teams, err := u.teamRepo.GetTeamsByUserId(ctx, updUser.Id)
 	if err != nil {
 		return SetIsActiveRes{}, e.Wrap(op, err)
 	}
+	res := NewSetIsActiveRes(updUser, teams)
+
+	if user.IsActive && !updUser.IsActive {
+		err := u.outboxRepo.Add(ctx, newEvent(domain.EventUserDeactivated, domain.UserDeactivatedPayload{
+			UserId:   updUser.Id,
+			TeamName: res.User.TeamName,
+		}))
+		if err != nil {
+			return SetIsActiveRes{}, e.Wrap(op, err)
+		}
+	}
 
-	return NewSetIsActiveRes(updUser.Id, updUser.Name, team.Name, updUser.IsActive), nil
+	if err := tx.Commit(ctx); err != nil {
+		return SetIsActiveRes{}, e.Wrap(op, err)
+	}
+
+	return res, nil
 }
 
 func (u *UserUseCase) GetReview(ctx context.Context, userId string) (GetReviewRes, error) {
@@ -51,3 +95,391 @@ func (u *UserUseCase) GetReview(ctx context.Context, userId string) (GetReviewRe
 
 	return NewGetReviewRes(userId, dto), nil
 }
+
+// Transfer moves a user to another primary team; other memberships stay. Their
+// reviews on open PRs of the old team go to its remaining active members,
+// failing with ErrPrNoCandidate when there is nobody left. Reviewers of the
+// user's own open PRs without an owning team who are not in the new team are
+// replaced by new teammates while there are enough of them.
+func (u *UserUseCase) Transfer(ctx context.Context, req TransferUserReq) (TransferUserRes, error) {
+	const op = "UserUseCase.Transfer"
+
+	ctx, tx, err := u.txManager.Begin(ctx)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	user, err := u.userRepo.GetById(ctx, req.UserId)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	target, err := u.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	if user.TeamId == target.Id {
+		teams, err := u.teamRepo.GetTeamsByUserId(ctx, user.Id)
+		if err != nil {
+			return TransferUserRes{}, e.Wrap(op, err)
+		}
+
+		return NewTransferUserRes(user, teams, target.Name, []PullRequestDTO{}, []PullRequestDTO{}), nil
+	}
+
+	var (
+		from       domain.Team
+		oldMembers []domain.User
+	)
+	if user.TeamId != 0 {
+		from, err = u.teamRepo.GetTeamByUserId(ctx, user.Id)
+		if err != nil {
+			return TransferUserRes{}, e.Wrap(op, err)
+		}
+
+		oldMembers, err = u.teamRepo.GetMembersByTeamNameWithUsers(ctx, from.Name)
+		if err != nil {
+			return TransferUserRes{}, e.Wrap(op, err)
+		}
+	}
+
+	newMembers, err := u.teamRepo.GetMembersByTeamNameWithUsers(ctx, target.Name)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	status, err := u.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	reviews, err := u.handOverReviews(ctx, status.Id, user.Id, from.Id, oldMembers)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	authored, err := u.refreshReviewers(ctx, status.Id, user.Id, newMembers)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	moved, err := u.userRepo.MoveToTeam(ctx, user.Id, target.Id)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	teams, err := u.teamRepo.GetTeamsByUserId(ctx, moved.Id)
+	if err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	events := []domain.Event{newEvent(domain.EventUserTransferred, domain.UserTransferredPayload{
+		UserId:   moved.Id,
+		FromTeam: from.Name,
+		ToTeam:   target.Name,
+	})}
+	events = append(events, reassignmentEvents(reviews.changes)...)
+	events = append(events, reassignmentEvents(authored.changes)...)
+	if err := u.outboxRepo.Add(ctx, events...); err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TransferUserRes{}, e.Wrap(op, err)
+	}
+
+	return NewTransferUserRes(moved, teams, from.Name, reviews.updatedPRs(), authored.updatedPRs()), nil
+}
+
+// JoinTeam adds a membership in one more team. Users without a team get it as
+// their primary one.
+func (u *UserUseCase) JoinTeam(ctx context.Context, req JoinTeamReq) (JoinTeamRes, error) {
+	const op = "UserUseCase.JoinTeam"
+
+	ctx, tx, err := u.txManager.Begin(ctx)
+	if err != nil {
+		return JoinTeamRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	team, err := u.teamRepo.GetByName(ctx, req.TeamName)
+	if err != nil {
+		return JoinTeamRes{}, e.Wrap(op, err)
+	}
+
+	user, err := u.userRepo.AddMembership(ctx, team.Id, req.UserId)
+	if err != nil {
+		return JoinTeamRes{}, e.Wrap(op, err)
+	}
+
+	teams, err := u.teamRepo.GetTeamsByUserId(ctx, user.Id)
+	if err != nil {
+		return JoinTeamRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return JoinTeamRes{}, e.Wrap(op, err)
+	}
+
+	return JoinTeamRes{User: NewUserDTO(user, teams)}, nil
+}
+
+// GetUser returns the user with their teams and review load.
+func (u *UserUseCase) GetUser(ctx context.Context, userId string) (GetUserRes, error) {
+	const op = "UserUseCase.GetUser"
+
+	user, err := u.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return GetUserRes{}, e.Wrap(op, err)
+	}
+
+	teams, err := u.teamRepo.GetTeamsByUserId(ctx, user.Id)
+	if err != nil {
+		return GetUserRes{}, e.Wrap(op, err)
+	}
+
+	status, err := u.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return GetUserRes{}, e.Wrap(op, err)
+	}
+
+	stats, err := u.userRepo.GetStats(ctx, user.Id, status.Id)
+	if err != nil {
+		return GetUserRes{}, e.Wrap(op, err)
+	}
+
+	return GetUserRes{
+		User:        NewUserDTO(user, teams),
+		OpenReviews: stats.OpenReviews,
+		AuthoredPrs: stats.AuthoredPrs,
+	}, nil
+}
+
+// ListUsers returns a page of users ordered by id. Only the primary team of
+// each user is filled in.
+func (u *UserUseCase) ListUsers(ctx context.Context, req ListUsersReq) (ListUsersRes, error) {
+	const op = "UserUseCase.ListUsers"
+
+	filter := r.UserFilter{
+		Query:    req.Query,
+		IsActive: req.IsActive,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUsersLimit
+	}
+
+	if req.TeamName != "" {
+		team, err := u.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			return ListUsersRes{}, e.Wrap(op, err)
+		}
+		filter.TeamId = team.Id
+	}
+
+	users, total, err := u.userRepo.List(ctx, filter)
+	if err != nil {
+		return ListUsersRes{}, e.Wrap(op, err)
+	}
+
+	teamNames := make(map[int]string)
+	res := ListUsersRes{
+		Users:  make([]UserDTO, 0, len(users)),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for _, user := range users {
+		if _, ok := teamNames[user.TeamId]; !ok && user.TeamId != 0 {
+			team, err := u.teamRepo.GetById(ctx, user.TeamId)
+			if err != nil {
+				return ListUsersRes{}, e.Wrap(op, err)
+			}
+			teamNames[team.Id] = team.Name
+		}
+
+		res.Users = append(res.Users, UserDTO{
+			Id:       user.Id,
+			Username: user.Name,
+			TeamName: teamNames[user.TeamId],
+			IsActive: user.IsActive,
+		})
+	}
+
+	return res, nil
+}
+
+// Anonymize erases a departed user. Their open reviews go to active members of
+// each PR's team, or are dropped where the team has nobody left. The user is
+// then replaced by an inactive tombstone with a pseudonym, which keeps their
+// memberships, authored PRs and past reviews. Stored events and webhook
+// deliveries are rewritten to the tombstone id, and stored idempotent responses
+// that mention the user are dropped. Tombstones are returned as is.
+func (u *UserUseCase) Anonymize(ctx context.Context, req AnonymizeUserReq) (AnonymizeUserRes, error) {
+	const op = "UserUseCase.Anonymize"
+
+	ctx, tx, err := u.txManager.Begin(ctx)
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	user, err := u.userRepo.GetById(ctx, req.UserId)
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	isTombstone, err := u.userRepo.IsTombstone(ctx, user.Id)
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	if isTombstone {
+		teams, err := u.teamRepo.GetTeamsByUserId(ctx, user.Id)
+		if err != nil {
+			return AnonymizeUserRes{}, e.Wrap(op, err)
+		}
+
+		return NewAnonymizeUserRes(user, teams, []PullRequestDTO{}), nil
+	}
+
+	status, err := u.statusRepo.GetByName(ctx, string(domain.OPEN))
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	reviews, err := u.releaseReviews(ctx, status.Id, user.Id)
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	tombstoneId := domain.NewTombstoneId()
+	tombstone, err := u.userRepo.Anonymize(ctx, user.Id, tombstoneId, domain.Pseudonym(tombstoneId))
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	teams, err := u.teamRepo.GetTeamsByUserId(ctx, tombstone.Id)
+	if err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	// Stored events now name the tombstone, and so do the new ones.
+	for prId, change := range reviews.changes {
+		change.ToRemove = []string{tombstone.Id}
+		reviews.changes[prId] = change
+	}
+	events := reassignmentEvents(reviews.changes)
+	events = append(events, newEvent(domain.EventUserAnonymized, domain.UserAnonymizedPayload{
+		TombstoneId: tombstone.Id,
+	}))
+	if err := u.outboxRepo.Add(ctx, events...); err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return AnonymizeUserRes{}, e.Wrap(op, err)
+	}
+
+	return NewAnonymizeUserRes(tombstone, teams, reviews.updatedPRs()), nil
+}
+
+// releaseReviews replaces the user on every open PR they review with an active
+// member of the PR's team and drops them where there is no candidate. Dropped
+// reviews are recorded as changes without a reviewer to add.
+func (u *UserUseCase) releaseReviews(ctx context.Context, openStatusId int, userId string) (reassignment, error) {
+	prMap, err := u.prRepo.GetOpenPRsByReviewerIDs(ctx, []string{userId}, openStatusId)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	leaving := map[string]struct{}{userId: {}}
+	res, err := reassignWithinTeams(ctx, u.teamRepo, u.reviewerRepo, prMap, leaving, true)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	dropped := make(map[string]r.PrReviewerChange)
+	for prId, pr := range prMap {
+		if _, ok := res.changes[prId]; ok {
+			continue
+		}
+
+		dropped[prId] = r.PrReviewerChange{ToRemove: []string{userId}}
+		res.reviewers[prId] = slices.DeleteFunc(slices.Clone(pr.ReviewersIds), func(id string) bool {
+			return id == userId
+		})
+	}
+
+	if len(dropped) > 0 {
+		if err := u.reviewerRepo.UpdateReviewers(ctx, dropped); err != nil {
+			return reassignment{}, err
+		}
+		maps.Copy(res.changes, dropped)
+	}
+
+	return res, nil
+}
+
+// handOverReviews replaces the user on open PRs reviewed by the old team.
+func (u *UserUseCase) handOverReviews(ctx context.Context, openStatusId int, userId string, oldTeamId int,
+	oldMembers []domain.User) (reassignment, error) {
+	prMap, err := u.prRepo.GetOpenPRsByReviewerIDs(ctx, []string{userId}, openStatusId)
+	if err != nil {
+		return reassignment{}, err
+	}
+
+	candidates := make(map[string]struct{}, len(oldMembers))
+	for _, member := range oldMembers {
+		if member.IsActive && member.Id != userId {
+			candidates[member.Id] = struct{}{}
+		}
+	}
+
+	maps.DeleteFunc(prMap, func(_ string, pr r.GetOpenPRsByReviewerIDsDTO) bool {
+		return pr.ReviewTeamId != oldTeamId
+	})
+
+	leaving := map[string]struct{}{userId: {}}
+	return replaceReviewers(ctx, u.reviewerRepo, prMap, leaving, candidates, false)
+}
+
+// refreshReviewers replaces reviewers of the user's open PRs who are not in the
+// new team with its active members, as far as there are candidates. PRs with an
+// owning team keep their reviewers.
+func (u *UserUseCase) refreshReviewers(ctx context.Context, openStatusId int, userId string,
+	newMembers []domain.User) (reassignment, error) {
+	prMap, err := u.prRepo.GetOpenPRsByAuthorID(ctx, userId, openStatusId)
+	if err != nil {
+		return reassignment{}, err
+	}
+	maps.DeleteFunc(prMap, func(_ string, pr r.GetOpenPRsByReviewerIDsDTO) bool {
+		return pr.Pr.TeamId != 0
+	})
+
+	teammates := make(map[string]struct{}, len(newMembers))
+	candidates := make(map[string]struct{}, len(newMembers))
+	for _, member := range newMembers {
+		teammates[member.Id] = struct{}{}
+		if member.IsActive {
+			candidates[member.Id] = struct{}{}
+		}
+	}
+
+	outsiders := make(map[string]struct{})
+	for _, pr := range prMap {
+		for _, reviewerId := range pr.ReviewersIds {
+			if _, ok := teammates[reviewerId]; !ok {
+				outsiders[reviewerId] = struct{}{}
+			}
+		}
+	}
+
+	return replaceReviewers(ctx, u.reviewerRepo, prMap, outsiders, candidates, true)
+}
@@ -21,15 +21,14 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 	userRepo := mocks.NewMockUserRepository(ctrl)
 	teamRepo := mocks.NewMockTeamRepository(ctrl)
 
-	userUC := NewUserUseCase(reviewerRepo, userRepo, teamRepo)
-
 	tests := []struct {
-		name          string
-		input         SetIsActiveReq
-		userRepoSetup func(*mocks.MockUserRepository)
-		teamRepoSetup func(*mocks.MockTeamRepository)
-		expectedRes   SetIsActiveRes
-		expectedErr   error
+		name           string
+		input          SetIsActiveReq
+		userRepoSetup  func(*mocks.MockUserRepository)
+		teamRepoSetup  func(*mocks.MockTeamRepository)
+		expectedRes    SetIsActiveRes
+		expectedEvents []domain.EventType
+		expectedErr    error
 	}{
 		{
 			name: "success",
@@ -38,6 +37,9 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 				IsActive: false,
 			},
 			userRepoSetup: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().
+					GetById(gomock.Any(), "u2").
+					Return(domain.User{Id: "u2", Name: "Test User", IsActive: true, TeamId: 1}, nil)
 				userRepo.EXPECT().
 					UpdateIsActive(gomock.Any(), "u2", false).
 					Return(domain.User{
@@ -49,10 +51,10 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 			},
 			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
 				teamRepo.EXPECT().
-					GetTeamByUserId(gomock.Any(), "u2").
-					Return(domain.Team{
-						Id:   1,
-						Name: "Test Team",
+					GetTeamsByUserId(gomock.Any(), "u2").
+					Return([]domain.Team{
+						{Id: 2, Name: "Platform"},
+						{Id: 1, Name: "Test Team"},
 					}, nil)
 			},
 			expectedRes: SetIsActiveRes{
@@ -61,6 +63,38 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 					Username: "Test User",
 					IsActive: false,
 					TeamName: "Test Team",
+					Teams:    []string{"Platform", "Test Team"},
+				},
+			},
+			expectedEvents: []domain.EventType{domain.EventUserDeactivated},
+			expectedErr:    nil,
+		},
+		{
+			name: "already inactive",
+			input: SetIsActiveReq{
+				UserId:   "u3",
+				IsActive: false,
+			},
+			userRepoSetup: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().
+					GetById(gomock.Any(), "u3").
+					Return(domain.User{Id: "u3", Name: "Idle User", IsActive: false, TeamId: 1}, nil)
+				userRepo.EXPECT().
+					UpdateIsActive(gomock.Any(), "u3", false).
+					Return(domain.User{Id: "u3", Name: "Idle User", IsActive: false, TeamId: 1}, nil)
+			},
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().
+					GetTeamsByUserId(gomock.Any(), "u3").
+					Return([]domain.Team{{Id: 1, Name: "Test Team"}}, nil)
+			},
+			expectedRes: SetIsActiveRes{
+				User: UserDTO{
+					Id:       "u3",
+					Username: "Idle User",
+					IsActive: false,
+					TeamName: "Test Team",
+					Teams:    []string{"Test Team"},
 				},
 			},
 			expectedErr: nil,
@@ -73,7 +107,7 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 			},
 			userRepoSetup: func(userRepo *mocks.MockUserRepository) {
 				userRepo.EXPECT().
-					UpdateIsActive(gomock.Any(), "u888", false).
+					GetById(gomock.Any(), "u888").
 					Return(domain.User{}, e.ErrUserNotFound)
 			},
 			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
@@ -88,12 +122,17 @@ func TestUserUseCase_SetIsActive(t *testing.T) {
 			tt.userRepoSetup(userRepo)
 			tt.teamRepoSetup(teamRepo)
 
+			outboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			events := recordEvents(outboxRepo)
+			userUC := NewUserUseCase(reviewerRepo, userRepo, teamRepo, nil, nil, outboxRepo, newTxManager(ctrl))
+
 			res, err := userUC.SetIsActive(context.Background(), tt.input)
 			if !errors.Is(err, tt.expectedErr) {
 				t.Errorf("unexpected error: got %v, want %v", err, tt.expectedErr)
 			}
 
 			require.Equal(t, tt.expectedRes, res)
+			require.Equal(t, tt.expectedEvents, *events)
 		})
 	}
 }
@@ -153,7 +192,7 @@ func TestUserUseCase_GetReview(t *testing.T) {
 			userRepo := mocks.NewMockUserRepository(ctrl)
 			teamRepo := mocks.NewMockTeamRepository(ctrl)
 
-			userUC := NewUserUseCase(reviewerRepo, userRepo, teamRepo)
+			userUC := NewUserUseCase(reviewerRepo, userRepo, teamRepo, nil, nil, nil, nil)
 
 			tt.userRepoSetup(userRepo)
 			tt.reviewerRepoSetup(reviewerRepo)
@@ -167,3 +206,132 @@ func TestUserUseCase_GetReview(t *testing.T) {
 		})
 	}
 }
+
+func TestUserUseCase_ListUsers(t *testing.T) {
+	active := true
+
+	tests := []struct {
+		name          string
+		input         ListUsersReq
+		userRepoSetup func(*mocks.MockUserRepository)
+		teamRepoSetup func(*mocks.MockTeamRepository)
+		expectedRes   ListUsersRes
+		expectedErr   error
+	}{
+		{
+			name:  "default limit and team names",
+			input: ListUsersReq{Query: "u", IsActive: &active},
+			userRepoSetup: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().List(gomock.Any(), r.UserFilter{Query: "u", IsActive: &active, Limit: defaultUsersLimit}).
+					Return([]domain.User{
+						{Id: "u1", Name: "Alice", IsActive: true, TeamId: 1},
+						{Id: "u2", Name: "Bob", IsActive: true, TeamId: 1},
+						{Id: "u3", Name: "Carol", IsActive: true},
+					}, 3, nil)
+			},
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil).Times(1)
+			},
+			expectedRes: ListUsersRes{
+				Users: []UserDTO{
+					{Id: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+					{Id: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+					{Id: "u3", Username: "Carol", IsActive: true},
+				},
+				Total: 3,
+				Limit: defaultUsersLimit,
+			},
+		},
+		{
+			name:  "team filter",
+			input: ListUsersReq{TeamName: "platform", Limit: 10, Offset: 20},
+			userRepoSetup: func(userRepo *mocks.MockUserRepository) {
+				userRepo.EXPECT().List(gomock.Any(), r.UserFilter{TeamId: 2, Limit: 10, Offset: 20}).
+					Return([]domain.User{}, 20, nil)
+			},
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().GetByName(gomock.Any(), "platform").Return(domain.Team{Id: 2, Name: "platform"}, nil)
+			},
+			expectedRes: ListUsersRes{Users: []UserDTO{}, Total: 20, Limit: 10, Offset: 20},
+		},
+		{
+			name:          "team not found",
+			input:         ListUsersReq{TeamName: "unknown"},
+			userRepoSetup: func(userRepo *mocks.MockUserRepository) {},
+			teamRepoSetup: func(teamRepo *mocks.MockTeamRepository) {
+				teamRepo.EXPECT().GetByName(gomock.Any(), "unknown").Return(domain.Team{}, e.ErrTeamNotFound)
+			},
+			expectedRes: ListUsersRes{},
+			expectedErr: e.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			teamRepo := mocks.NewMockTeamRepository(ctrl)
+
+			userUC := NewUserUseCase(nil, userRepo, teamRepo, nil, nil, nil, nil)
+
+			tt.userRepoSetup(userRepo)
+			tt.teamRepoSetup(teamRepo)
+
+			res, err := userUC.ListUsers(context.Background(), tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("unexpected error: got %v, want %v", err, tt.expectedErr)
+			}
+
+			require.Equal(t, tt.expectedRes, res)
+		})
+	}
+}
+
+func TestUserUseCase_releaseReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prRepo := mocks.NewMockPullRequestRepository(ctrl)
+	teamRepo := mocks.NewMockTeamRepository(ctrl)
+	reviewerRepo := mocks.NewMockPrReviewerRepository(ctrl)
+
+	prRepo.EXPECT().GetOpenPRsByReviewerIDs(gomock.Any(), []string{"u1"}, 1).
+		Return(map[string]r.GetOpenPRsByReviewerIDsDTO{
+			"pr-1001": {Pr: domain.PullRequest{Id: "pr-1001", AuthorId: "u2"}, ReviewersIds: []string{"u1", "u3"}, ReviewTeamId: 1},
+			"pr-1002": {Pr: domain.PullRequest{Id: "pr-1002", AuthorId: "u5"}, ReviewersIds: []string{"u1", "u6"}, ReviewTeamId: 2},
+			"pr-1003": {Pr: domain.PullRequest{Id: "pr-1003", AuthorId: "u7"}, ReviewersIds: []string{"u1"}},
+		}, nil)
+	teamRepo.EXPECT().GetById(gomock.Any(), 1).Return(domain.Team{Id: 1, Name: "backend"}, nil)
+	teamRepo.EXPECT().GetMembersByTeamNameWithUsers(gomock.Any(), "backend").Return([]domain.User{
+		{Id: "u1", IsActive: true}, {Id: "u2", IsActive: true}, {Id: "u3", IsActive: true}, {Id: "u4", IsActive: true},
+	}, nil)
+	teamRepo.EXPECT().GetById(gomock.Any(), 2).Return(domain.Team{Id: 2, Name: "ops"}, nil)
+	teamRepo.EXPECT().GetMembersByTeamNameWithUsers(gomock.Any(), "ops").Return([]domain.User{
+		{Id: "u5", IsActive: true}, {Id: "u6", IsActive: true}, {Id: "u8", IsActive: false},
+	}, nil)
+	reviewerRepo.EXPECT().UpdateReviewers(gomock.Any(), map[string]r.PrReviewerChange{
+		"pr-1001": {ToAdd: []string{"u4"}, ToRemove: []string{"u1"}},
+	}).Return(nil)
+	reviewerRepo.EXPECT().UpdateReviewers(gomock.Any(), map[string]r.PrReviewerChange{
+		"pr-1002": {ToRemove: []string{"u1"}},
+		"pr-1003": {ToRemove: []string{"u1"}},
+	}).Return(nil)
+
+	userUC := NewUserUseCase(reviewerRepo, nil, teamRepo, prRepo, nil, nil, nil)
+
+	res, err := userUC.releaseReviews(context.Background(), 1, "u1")
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]r.PrReviewerChange{
+		"pr-1001": {ToAdd: []string{"u4"}, ToRemove: []string{"u1"}},
+		"pr-1002": {ToRemove: []string{"u1"}},
+		"pr-1003": {ToRemove: []string{"u1"}},
+	}, res.changes)
+	require.Equal(t, map[string][]string{
+		"pr-1001": {"u3", "u4"},
+		"pr-1002": {"u6"},
+		"pr-1003": {},
+	}, res.reviewers)
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/transaction"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	secretBytes            = 32
+)
+
+type WebhookUseCase struct {
+	webhookRepo r.WebhookRepository
+	txManager   transaction.Manager
+}
+
+func NewWebhookUseCase(webhookRepo r.WebhookRepository, txManager transaction.Manager) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookRepo: webhookRepo,
+		txManager:   txManager,
+	}
+}
+
+// CreateWebhook subscribes url to the given event types. The secret is generated
+// when none is given and is returned only here.
+func (w *WebhookUseCase) CreateWebhook(ctx context.Context, req CreateWebhookReq) (CreateWebhookRes, error) {
+	const op = "WebhookUseCase.CreateWebhook"
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return CreateWebhookRes{}, e.Wrap(op, e.ErrInvalidWebhookURL)
+	}
+
+	var events []domain.EventType
+	for _, eventType := range req.Events {
+		if !eventType.Known() {
+			return CreateWebhookRes{}, e.Wrap(op, e.Wrap(string(eventType), e.ErrUnknownEventType))
+		}
+		if !slices.Contains(events, eventType) {
+			events = append(events, eventType)
+		}
+	}
+	slices.Sort(events)
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return CreateWebhookRes{}, e.Wrap(op, err)
+		}
+	}
+
+	ctx, tx, err := w.txManager.Begin(ctx)
+	if err != nil {
+		return CreateWebhookRes{}, e.Wrap(op, err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = context.WithValue(ctx, "tx", tx.Transaction())
+
+	sub, err := w.webhookRepo.CreateSubscription(ctx, domain.NewWebhookSubscription(req.URL, events, secret))
+	if err != nil {
+		return CreateWebhookRes{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return CreateWebhookRes{}, e.Wrap(op, err)
+	}
+
+	return CreateWebhookRes{
+		Subscription: NewWebhookSubscriptionDTO(sub),
+		Secret:       sub.Secret,
+	}, nil
+}
+
+func (w *WebhookUseCase) ListWebhooks(ctx context.Context) (ListWebhooksRes, error) {
+	const op = "WebhookUseCase.ListWebhooks"
+
+	subs, err := w.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		return ListWebhooksRes{}, e.Wrap(op, err)
+	}
+
+	res := ListWebhooksRes{Subscriptions: make([]WebhookSubscriptionDTO, 0, len(subs))}
+	for _, sub := range subs {
+		res.Subscriptions = append(res.Subscriptions, NewWebhookSubscriptionDTO(sub))
+	}
+
+	return res, nil
+}
+
+func (w *WebhookUseCase) DeleteWebhook(ctx context.Context, id int) error {
+	const op = "WebhookUseCase.DeleteWebhook"
+
+	if err := w.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// EnableWebhook reactivates a subscription disabled after repeated failures.
+// Its pending deliveries are sent again.
+func (w *WebhookUseCase) EnableWebhook(ctx context.Context, id int) (EnableWebhookRes, error) {
+	const op = "WebhookUseCase.EnableWebhook"
+
+	sub, err := w.webhookRepo.EnableSubscription(ctx, id)
+	if err != nil {
+		return EnableWebhookRes{}, e.Wrap(op, err)
+	}
+
+	return EnableWebhookRes{Subscription: NewWebhookSubscriptionDTO(sub)}, nil
+}
+
+// GetWebhookDeliveries returns the latest deliveries of a subscription, newest first.
+func (w *WebhookUseCase) GetWebhookDeliveries(ctx context.Context, req GetWebhookDeliveriesReq) (GetWebhookDeliveriesRes, error) {
+	const op = "WebhookUseCase.GetWebhookDeliveries"
+
+	if _, err := w.webhookRepo.GetSubscription(ctx, req.SubscriptionId); err != nil {
+		return GetWebhookDeliveriesRes{}, e.Wrap(op, err)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	deliveries, err := w.webhookRepo.GetDeliveries(ctx, req.SubscriptionId, limit)
+	if err != nil {
+		return GetWebhookDeliveriesRes{}, e.Wrap(op, err)
+	}
+
+	res := GetWebhookDeliveriesRes{
+		SubscriptionId: req.SubscriptionId,
+		Deliveries:     make([]WebhookDeliveryDTO, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		res.Deliveries = append(res.Deliveries, NewWebhookDeliveryDTO(delivery))
+	}
+
+	return res, nil
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	PollInterval time.Duration `yaml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL"`
+	BatchSize    int           `yaml:"batch_size" env:"WEBHOOK_BATCH_SIZE"`
+	// Timeout bounds a single request to a subscriber.
+	Timeout         time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" env:"WEBHOOK_RETRY_MAX_BACKOFF"`
+	// DisableAfter is the number of failed attempts in a row, across all deliveries
+	// of a subscription, after which the subscription is disabled.
+	DisableAfter int `yaml:"disable_after" env:"WEBHOOK_DISABLE_AFTER"`
+	// LeaseTimeout is how long a claimed batch is hidden from other workers.
+	// It must cover the delivery of a whole batch.
+	LeaseTimeout time.Duration `yaml:"lease_timeout" env:"WEBHOOK_LEASE_TIMEOUT"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval:    time.Second,
+		BatchSize:       20,
+		Timeout:         5 * time.Second,
+		MaxAttempts:     8,
+		RetryBackoff:    5 * time.Second,
+		RetryMaxBackoff: time.Hour,
+		DisableAfter:    20,
+		LeaseTimeout:    5 * time.Minute,
+	}
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.PollInterval > 0, "webhook.poll_interval: must be positive")
+	check(c.BatchSize > 0, "webhook.batch_size: must be positive, got %d", c.BatchSize)
+	check(c.Timeout > 0, "webhook.timeout: must be positive")
+	check(c.MaxAttempts > 0, "webhook.max_attempts: must be positive, got %d", c.MaxAttempts)
+	check(c.RetryBackoff > 0, "webhook.retry_backoff: must be positive")
+	check(c.RetryMaxBackoff >= c.RetryBackoff, "webhook.retry_max_backoff: must not be less than retry_backoff")
+	check(c.DisableAfter > 0, "webhook.disable_after: must be positive, got %d", c.DisableAfter)
+	check(c.LeaseTimeout > c.Timeout, "webhook.lease_timeout: must be greater than timeout")
+
+	return errors.Join(errs...)
+}
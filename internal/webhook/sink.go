@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"avito-internship/internal/domain"
+	"avito-internship/internal/outbox"
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Envelope is the body of every webhook request.
+type Envelope struct {
+	Id        int64            `json:"id"`
+	Type      domain.EventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      json.RawMessage  `json:"data"`
+}
+
+// Sink is an outbox sink that queues a delivery for every subscription to the
+// event type. The worker sends them on its own schedule, so a slow subscriber
+// does not hold up the outbox.
+type Sink struct {
+	repo r.WebhookRepository
+}
+
+func NewSink(repo r.WebhookRepository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string {
+	return outbox.SinkWebhook
+}
+
+func (s *Sink) Deliver(ctx context.Context, event domain.Event) error {
+	const op = "webhook.Sink.Deliver"
+
+	body, err := json.Marshal(Envelope{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt.UTC(),
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := s.repo.EnqueueDeliveries(ctx, event, body); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
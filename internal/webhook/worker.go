@@ -0,0 +1,159 @@
+// Package webhook sends domain events to subscriber URLs. The outbox Sink queues
+// one delivery per matching subscription and the Worker sends them with retries.
+package webhook
+
+import (
+	r "avito-internship/internal/repository"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"avito-internship/pkg/retry"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const userAgent = "reviewer-service-webhooks"
+
+type Worker struct {
+	repo    r.WebhookRepository
+	client  *http.Client
+	cfg     Config
+	backoff retry.Backoff
+	logger  logger.Logger
+}
+
+func NewWorker(repo r.WebhookRepository, cfg Config, logger logger.Logger) *Worker {
+	return &Worker{
+		repo:    repo,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		cfg:     cfg,
+		backoff: retry.Backoff{Initial: cfg.RetryBackoff, Max: cfg.RetryMaxBackoff},
+		logger:  logger,
+	}
+}
+
+// Run sends deliveries until ctx is done. Full batches are followed by the next
+// one right away; otherwise the worker waits for PollInterval.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := w.Dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				w.logger.Errorf(err, "webhook dispatch failed")
+			}
+			if err != nil || n < w.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch claims one batch of due deliveries and sends them. It returns the
+// number of claimed deliveries.
+func (w *Worker) Dispatch(ctx context.Context) (int, error) {
+	const op = "Worker.Dispatch"
+
+	claimed, err := w.repo.ClaimDeliveries(ctx, w.cfg.BatchSize, time.Now().Add(w.cfg.LeaseTimeout))
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	for _, delivery := range claimed {
+		// Deliveries left after shutdown are claimed again when their lease expires.
+		if err := ctx.Err(); err != nil {
+			return len(claimed), e.Wrap(op, err)
+		}
+
+		if err := w.deliver(ctx, delivery); err != nil {
+			return len(claimed), e.Wrap(op, err)
+		}
+	}
+
+	return len(claimed), nil
+}
+
+func (w *Worker) deliver(ctx context.Context, claimed r.ClaimedDeliveryDTO) error {
+	delivery := claimed.Delivery
+
+	code, sendErr := w.send(ctx, claimed)
+	if sendErr == nil {
+		return w.repo.MarkDelivered(ctx, delivery.Id, code)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	attempt := r.FailedAttemptDTO{DeliveryId: delivery.Id, LastError: sendErr.Error()}
+	if code != 0 {
+		attempt.ResponseCode = &code
+	}
+
+	if delivery.Attempts < w.cfg.MaxAttempts {
+		delay := w.backoff.Delay(delivery.Attempts)
+		next := time.Now().Add(delay)
+		attempt.NextAttemptAt = &next
+		w.logger.WarnfContext(ctx, "webhook delivery id=%d subscription=%d attempt %d failed, retrying in %s: %v",
+			delivery.Id, delivery.SubscriptionId, delivery.Attempts, delay, sendErr)
+	} else {
+		w.logger.ErrorfContext(ctx, sendErr, "webhook delivery id=%d subscription=%d failed after %d attempts, giving up",
+			delivery.Id, delivery.SubscriptionId, delivery.Attempts)
+	}
+
+	disabled, err := w.repo.MarkAttemptFailed(ctx, attempt, w.cfg.DisableAfter)
+	if err != nil {
+		return err
+	}
+
+	if disabled {
+		w.logger.WarnfContext(ctx, "webhook subscription id=%d disabled after %d failed attempts in a row",
+			delivery.SubscriptionId, w.cfg.DisableAfter)
+	}
+
+	return nil
+}
+
+// send posts the delivery body and returns the response status code, or zero
+// when no response was received.
+func (w *Worker) send(ctx context.Context, claimed r.ClaimedDeliveryDTO) (int, error) {
+	delivery := claimed.Delivery
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, claimed.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(HeaderSignature, Sign(claimed.Secret, delivery.Body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Draining lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"avito-internship/internal/domain"
+	r "avito-internship/internal/repository"
+	"avito-internship/internal/repository/mocks"
+	"avito-internship/pkg/logger"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", body)
+
+	require.Equal(t, "sha256=03def589620c813f198fd03d7967e292b163ef0435ebf43071ce0e9519763cb7", signature)
+	require.True(t, Verify("secret", body, signature))
+	require.False(t, Verify("other", body, signature))
+	require.False(t, Verify("secret", []byte(`{"id":2}`), signature))
+	require.False(t, Verify("secret", body, signature[len("sha256="):]))
+}
+
+func TestWorker_Dispatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 3
+	cfg.RetryBackoff = time.Minute
+	cfg.RetryMaxBackoff = time.Hour
+	cfg.DisableAfter = 5
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		setup    func(repo *mocks.MockWebhookRepository)
+	}{
+		{
+			name:     "delivered",
+			status:   http.StatusAccepted,
+			attempts: 1,
+			setup: func(repo *mocks.MockWebhookRepository) {
+				repo.EXPECT().MarkDelivered(gomock.Any(), int64(7), http.StatusAccepted).Return(nil)
+			},
+		},
+		{
+			name:     "error status is retried with backoff",
+			status:   http.StatusBadGateway,
+			attempts: 2,
+			setup: func(repo *mocks.MockWebhookRepository) {
+				repo.EXPECT().MarkAttemptFailed(gomock.Any(), gomock.Any(), 5).
+					DoAndReturn(func(_ context.Context, attempt r.FailedAttemptDTO, _ int) (bool, error) {
+						require.Equal(t, int64(7), attempt.DeliveryId)
+						require.Equal(t, http.StatusBadGateway, *attempt.ResponseCode)
+						require.Equal(t, "unexpected status 502", attempt.LastError)
+						require.WithinDuration(t, time.Now().Add(2*time.Minute), *attempt.NextAttemptAt, 5*time.Second)
+						return false, nil
+					})
+			},
+		},
+		{
+			name:     "gives up after max attempts",
+			status:   http.StatusInternalServerError,
+			attempts: 3,
+			setup: func(repo *mocks.MockWebhookRepository) {
+				repo.EXPECT().MarkAttemptFailed(gomock.Any(), gomock.Any(), 5).
+					DoAndReturn(func(_ context.Context, attempt r.FailedAttemptDTO, _ int) (bool, error) {
+						require.Nil(t, attempt.NextAttemptAt)
+						return true, nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(`{"id":42,"type":"pr.merged"}`)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				got, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.Equal(t, body, got)
+				require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+				require.Equal(t, "pr.merged", req.Header.Get(HeaderEvent))
+				require.Equal(t, "7", req.Header.Get(HeaderDelivery))
+				require.True(t, Verify("secret", got, req.Header.Get(HeaderSignature)))
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockWebhookRepository(ctrl)
+
+			claimed := r.ClaimedDeliveryDTO{
+				Delivery: domain.WebhookDelivery{
+					Id:             7,
+					SubscriptionId: 1,
+					EventId:        42,
+					EventType:      domain.EventPRMerged,
+					Body:           body,
+					Attempts:       tt.attempts,
+				},
+				URL:    srv.URL,
+				Secret: "secret",
+			}
+			repo.EXPECT().ClaimDeliveries(gomock.Any(), cfg.BatchSize, gomock.Any()).Return([]r.ClaimedDeliveryDTO{claimed}, nil)
+			tt.setup(repo)
+
+			n, err := NewWorker(repo, cfg, logger.NewSlogLogger()).Dispatch(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
@@ -5,7 +5,14 @@ import "fmt"
 var (
 	ErrTeamIsExists  = fmt.Errorf("team_name already exists")
 	ErrTeamNotFound  = fmt.Errorf("team not found")
+	ErrTeamNotEmpty  = fmt.Errorf("team has members, pass a target team to move them to")
+	ErrSameTeam      = fmt.Errorf("target team must differ from the team")
+	ErrOpenReviews   = fmt.Errorf("members review open PRs of other teams")
 	ErrInvalidMember = fmt.Errorf("not all members are on the team")
+	ErrNotTeamMember = fmt.Errorf("author is not a member of the team")
+	ErrTeamCycle     = fmt.Errorf("parent team is the team itself or one of its subteams")
+
+	ErrUserInAnotherTeam = fmt.Errorf("user belongs to another team, use /users/transfer to move them or /users/joinTeam to add a membership")
 
 	ErrUserNotFound = fmt.Errorf("user not found")
 
@@ -18,10 +25,22 @@ var (
 	ErrStatusNotFound = fmt.Errorf("status not found")
 	ErrInvalidStatus  = fmt.Errorf("invalid status")
 
+	ErrWebhookNotFound   = fmt.Errorf("webhook subscription not found")
+	ErrInvalidWebhookURL = fmt.Errorf("webhook url must be an absolute http or https url")
+	ErrUnknownEventType  = fmt.Errorf("unknown event type")
+
+	ErrUnknownLogin = fmt.Errorf("forge login is not mapped to a user")
+
+	ErrInvalidIdempotencyKey  = fmt.Errorf("idempotency key must be 1 to 255 printable ASCII characters")
+	ErrIdempotencyKeyMismatch = fmt.Errorf("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInUse    = fmt.Errorf("request with this idempotency key is in progress")
+
 	ErrInvalidRequestBody = fmt.Errorf("invalid request body")
 	ErrResourceNotFound   = fmt.Errorf("resource not found")
 	ErrUnauthorized       = fmt.Errorf("unauthorized")
 	ErrEmptyMembers       = fmt.Errorf("member list is empty")
+	ErrDuplicateMembers   = fmt.Errorf("member list has duplicates")
+	ErrMissingMemberId    = fmt.Errorf("every member must have a user_id")
 
 	ErrInternalServerError = fmt.Errorf("internal server error")
 
@@ -31,16 +50,39 @@ var (
 )
 
 const (
-	NOT_FOUND    = "NOT_FOUND"
-	TEAM_EXISTS  = "TEAM_EXISTS"
-	PR_EXISTS    = "PR_EXISTS"
-	PR_MERGED    = "PR_MERGED"
-	NOT_ASSIGNED = "NOT_ASSIGNED"
-	NO_CANDIDATE = "NO_CANDIDATE"
-	SERVER_ERR   = "SERVER_ERR"
-	BAD_REQUEST  = "BAD_REQUEST"
+	NOT_FOUND      = "NOT_FOUND"
+	TEAM_EXISTS    = "TEAM_EXISTS"
+	PR_EXISTS      = "PR_EXISTS"
+	PR_MERGED      = "PR_MERGED"
+	NOT_ASSIGNED   = "NOT_ASSIGNED"
+	NO_CANDIDATE   = "NO_CANDIDATE"
+	SERVER_ERR     = "SERVER_ERR"
+	BAD_REQUEST    = "BAD_REQUEST"
+	UNKNOWN_LOGIN  = "UNKNOWN_LOGIN"
+	USER_IN_TEAM   = "USER_IN_TEAM"
+	TEAM_NOT_EMPTY = "TEAM_NOT_EMPTY"
+	OPEN_REVIEWS   = "OPEN_REVIEWS"
+
+	IDEMPOTENCY_KEY_REUSED = "IDEMPOTENCY_KEY_REUSED"
+	IDEMPOTENCY_KEY_IN_USE = "IDEMPOTENCY_KEY_IN_USE"
 )
 
 func Wrap(msg string, err error) error {
 	return fmt.Errorf("%s: %w", msg, err)
 }
+
+// ValidationError names the request field that failed validation and the rule
+// it broke. It matches ErrInvalidRequestBody.
+type ValidationError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("%s: rule %q failed: %s", v.Field, v.Rule, v.Message)
+}
+
+func (v *ValidationError) Unwrap() error {
+	return ErrInvalidRequestBody
+}
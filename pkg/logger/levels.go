@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Levels holds the default level and per-package overrides. It is shared by all
+// loggers derived from one root, so changes apply immediately without a restart.
+type Levels struct {
+	mu       sync.RWMutex
+	def      slog.Level
+	packages map[string]slog.Level
+}
+
+func NewLevels(def slog.Level) *Levels {
+	return &Levels{
+		def:      def,
+		packages: make(map[string]slog.Level),
+	}
+}
+
+// Level returns the effective level of pkg.
+func (l *Levels) Level(pkg string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level, ok := l.packages[pkg]; ok {
+		return level
+	}
+	return l.def
+}
+
+// Set changes the level of pkg, or the default level when pkg is empty.
+func (l *Levels) Set(pkg string, level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if pkg == "" {
+		l.def = parsed
+	} else {
+		l.packages[pkg] = parsed
+	}
+	return nil
+}
+
+// Reset removes the override of pkg so that it follows the default level again.
+func (l *Levels) Reset(pkg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.packages, pkg)
+}
+
+// Snapshot returns the default level and a copy of the package overrides.
+func (l *Levels) Snapshot() (slog.Level, map[string]slog.Level) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.def, maps.Clone(l.packages)
+}
+
+// ParseLevel accepts debug, info, warn and error in any case.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
+// parsePackageLevels parses "pkg=level,pkg=level".
+func parsePackageLevels(s string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		pkg, level, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(pkg) == "" {
+			return nil, fmt.Errorf("invalid package level %q, expected pkg=level", pair)
+		}
+
+		parsed, err := ParseLevel(strings.TrimSpace(level))
+		if err != nil {
+			return nil, err
+		}
+		levels[strings.TrimSpace(pkg)] = parsed
+	}
+
+	return levels, nil
+}
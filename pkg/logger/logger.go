@@ -1,9 +1,21 @@
 package logger
 
+import "context"
+
 type Logger interface {
 	Infof(format string, args ...any)
 	Warnf(format string, args ...any)
 	Errorf(err error, format string, args ...any)
 	Debugf(format string, args ...any)
+
+	// The Context variants also record request-scoped fields carried by ctx, such as the request ID.
+	InfofContext(ctx context.Context, format string, args ...any)
+	WarnfContext(ctx context.Context, format string, args ...any)
+	ErrorfContext(ctx context.Context, err error, format string, args ...any)
+	DebugfContext(ctx context.Context, format string, args ...any)
+
+	// With returns a logger that adds the key-value pairs to every record.
+	With(fields ...any) Logger
+
 	Track(operationName string, operation func() error) error
 }
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSlogLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	root, err := NewSlogLoggerWithConfig(Config{Format: FormatJSON, Level: "info"}, &buf)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	root.Debugf("hidden")
+	root.With("team", "backend").ErrorfContext(ctx, errors.New("boom"), "failed %d", 1)
+
+	records := decodeLines(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "failed 1", records[0]["msg"])
+	assert.Equal(t, "boom", records[0]["err"])
+	assert.Equal(t, "backend", records[0]["team"])
+	assert.Equal(t, "req-1", records[0]["request_id"])
+}
+
+func TestSlogLogger_PackageLevels(t *testing.T) {
+	var buf bytes.Buffer
+	root, err := NewSlogLoggerWithConfig(Config{Format: FormatJSON, Level: "warn", Packages: "http=debug"}, &buf)
+	require.NoError(t, err)
+
+	httpLogger := root.Package("http")
+	storageLogger := root.Package("storage")
+
+	httpLogger.Debugf("http debug")
+	storageLogger.Infof("storage info")
+	require.NoError(t, root.Levels().Set("storage", "info"))
+	storageLogger.Infof("storage info after change")
+	root.Levels().Reset("http")
+	httpLogger.Debugf("http debug after reset")
+
+	records := decodeLines(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "http debug", records[0]["msg"])
+	assert.Equal(t, "http", records[0]["package"])
+	assert.Equal(t, "storage info after change", records[1]["msg"])
+	assert.Equal(t, "storage", records[1]["package"])
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{Format: FormatText, Level: "DEBUG", Packages: "http=warn, storage=error"}.Validate())
+
+	err := Config{Format: "xml", Level: "loud", Packages: "http"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `log.format: must be text or json, got "xml"`)
+	assert.Contains(t, err.Error(), `log.level: unknown log level "loud"`)
+	assert.Contains(t, err.Error(), `log.packages: invalid package level "http", expected pkg=level`)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warn")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+}
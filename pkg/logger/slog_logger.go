@@ -1,25 +1,111 @@
 package logger
 
 import (
+	"avito-internship/pkg/e"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"time"
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Config struct {
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	// Packages overrides the level of single packages, e.g. "http=debug,storage=warn".
+	Packages string `yaml:"packages" env:"LOG_PACKAGE_LEVELS"`
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	if c.Format != FormatText && c.Format != FormatJSON {
+		errs = append(errs, fmt.Errorf("log.format: must be %s or %s, got %q", FormatText, FormatJSON, c.Format))
+	}
+	if _, err := ParseLevel(c.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	if _, err := parsePackageLevels(c.Packages); err != nil {
+		errs = append(errs, fmt.Errorf("log.packages: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
 type SlogLogger struct {
 	logger *slog.Logger
+	base   slog.Handler
+	pkg    string
+	levels *Levels
 }
 
+// NewSlogLogger returns a text logger to stdout at DEBUG level, used before the
+// configuration is loaded and by tools.
 func NewSlogLogger() *SlogLogger {
 	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	})
+	return newSlogLogger(handler, "", NewLevels(slog.LevelDebug))
+}
+
+func NewSlogLoggerWithConfig(cfg Config, w io.Writer) (*SlogLogger, error) {
+	const op = "logger.NewSlogLoggerWithConfig"
+
+	if err := cfg.Validate(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	def, _ := ParseLevel(cfg.Level)
+	packages, _ := parsePackageLevels(cfg.Packages)
+
+	levels := NewLevels(def)
+	for pkg, level := range packages {
+		levels.packages[pkg] = level
+	}
+
+	// Records are filtered by levelHandler, so the format handler accepts everything.
+	opts := &slog.HandlerOptions{Level: slog.Level(-8)}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return newSlogLogger(handler, "", levels), nil
+}
+
+func newSlogLogger(base slog.Handler, pkg string, levels *Levels) *SlogLogger {
 	return &SlogLogger{
-		logger: slog.New(handler),
+		logger: slog.New(contextHandler{levelHandler{Handler: base, pkg: pkg, levels: levels}}),
+		base:   base,
+		pkg:    pkg,
+		levels: levels,
 	}
 }
 
+// Package returns a logger for the named package. Its level can be changed
+// separately through Levels, and records carry a package field.
+func (l *SlogLogger) Package(name string) *SlogLogger {
+	return newSlogLogger(l.base.WithAttrs([]slog.Attr{slog.String("package", name)}), name, l.levels)
+}
+
+// Levels gives access to the levels shared by this logger and all loggers derived from it.
+func (l *SlogLogger) Levels() *Levels {
+	return l.levels
+}
+
+func (l *SlogLogger) With(fields ...any) Logger {
+	return newSlogLogger(slog.New(l.base).With(fields...).Handler(), l.pkg, l.levels)
+}
+
 func (l *SlogLogger) Infof(format string, args ...any) {
 	l.logger.Info(fmt.Sprintf(format, args...))
 }
@@ -36,6 +122,22 @@ func (l *SlogLogger) Debugf(format string, args ...any) {
 	l.logger.Debug(fmt.Sprintf(format, args...))
 }
 
+func (l *SlogLogger) InfofContext(ctx context.Context, format string, args ...any) {
+	l.logger.InfoContext(ctx, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) WarnfContext(ctx context.Context, format string, args ...any) {
+	l.logger.WarnContext(ctx, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) ErrorfContext(ctx context.Context, err error, format string, args ...any) {
+	l.logger.ErrorContext(ctx, fmt.Sprintf(format, args...), slog.Any("err", err))
+}
+
+func (l *SlogLogger) DebugfContext(ctx context.Context, format string, args ...any) {
+	l.logger.DebugContext(ctx, fmt.Sprintf(format, args...))
+}
+
 func (l *SlogLogger) Track(operationName string, operation func() error) error {
 	start := time.Now()
 
@@ -50,3 +152,22 @@ func (l *SlogLogger) Track(operationName string, operation func() error) error {
 
 	return err
 }
+
+// levelHandler filters records by the current level of its package.
+type levelHandler struct {
+	slog.Handler
+	pkg    string
+	levels *Levels
+}
+
+func (h levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.pkg)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), pkg: h.pkg, levels: h.levels}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), pkg: h.pkg, levels: h.levels}
+}
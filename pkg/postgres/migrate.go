@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	migrations "avito-internship/db"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// migrationLockKey identifies the advisory lock that serializes migration runs
+// across replicas. It must stay the same between releases.
+const migrationLockKey int64 = 0x61766974_6f6d6967
+
+// Migrate runs fn against the embedded migrations while holding a session-level
+// advisory lock, so replicas starting at the same time apply migrations one by one.
+// The lock is waited for until ctx is done.
+func (db *PgDatabase) Migrate(ctx context.Context, logger logger.Logger, fn func(m *migrate.Migrate) error) error {
+	const op = "PgDatabase.Migrate"
+
+	sqlDb, err := sql.Open("pgx", db.Dsn)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer sqlDb.Close()
+
+	conn, err := sqlDb.Conn(ctx)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer conn.Close()
+
+	logger.Infof("waiting for migration lock")
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return e.Wrap(op, err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			logger.Errorf(err, "unable to release migration lock")
+		}
+	}()
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	source, err := iofs.New(migrations.PostgresMigrations, "migrations")
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := fn(m); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (db *PgDatabase) RunMigrations(ctx context.Context, logger logger.Logger) error {
+	return db.Migrate(ctx, logger, func(m *migrate.Migrate) error {
+		err := m.Up()
+		if errors.Is(err, migrate.ErrNoChange) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("migrations applied successfully")
+		return nil
+	})
+}
+
+// ExpectedSchemaVersion is the version of the newest embedded migration.
+func (db *PgDatabase) ExpectedSchemaVersion() (uint, error) {
+	const op = "PgDatabase.ExpectedSchemaVersion"
+
+	version, err := migrations.LatestVersion(migrations.PostgresMigrations, "migrations")
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return version, nil
+}
+
+// SchemaVersion reads the applied schema version from the golang-migrate table.
+func (db *PgDatabase) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	const op = "PgDatabase.SchemaVersion"
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err := db.Pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, e.Wrap(op, err)
+	}
+
+	return uint(version), dirty, nil
+}
@@ -3,20 +3,42 @@ package postgres
 import (
 	"avito-internship/pkg/e"
 	"avito-internship/pkg/logger"
+	"avito-internship/pkg/retry"
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
-	"os"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+type Config struct {
+	Host              string        `yaml:"host" env:"POSTGRES_HOST"`
+	Port              int           `yaml:"port" env:"POSTGRES_PORT"`
+	User              string        `yaml:"user" env:"POSTGRES_USER"`
+	Password          string        `yaml:"password" env:"POSTGRES_PASSWORD" secret:"true"`
+	Database          string        `yaml:"database" env:"POSTGRES_DB"`
+	SSLMode           string        `yaml:"ssl_mode" env:"SSL_MODE"`
+	MaxConns          int32         `yaml:"max_conns" env:"POSTGRES_MAX_CONNS"`
+	MinConns          int32         `yaml:"min_conns" env:"POSTGRES_MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"POSTGRES_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"POSTGRES_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"POSTGRES_HEALTH_CHECK_PERIOD"`
+	// ConnectTimeout bounds the total time Connect waits for the database to come up.
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" env:"POSTGRES_CONNECT_TIMEOUT"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" env:"POSTGRES_CONNECT_BACKOFF"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" env:"POSTGRES_CONNECT_MAX_BACKOFF"`
+}
+
+func (c Config) Dsn() string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.Database, c.SSLMode,
+	)
+}
+
+const pingTimeout = 5 * time.Second
+
 type PgDatabase struct {
 	Pool *pgxpool.Pool
 	Dsn  string
@@ -26,70 +48,63 @@ func NewPgDatabase(pool *pgxpool.Pool, dsn string) *PgDatabase {
 	return &PgDatabase{Pool: pool, Dsn: dsn}
 }
 
-func Connect() (*PgDatabase, error) {
+// Connect creates the pool and waits until the database answers a ping, retrying
+// with exponential backoff for up to cfg.ConnectTimeout or until ctx is done.
+// tracer, if not nil, observes every query.
+func Connect(ctx context.Context, cfg Config, logger logger.Logger, tracer pgx.QueryTracer) (*PgDatabase, error) {
 	const op = "PgDatabase.Connect"
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("POSTGRES_HOST"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("SSL_MODE"),
-	)
+	dsn := cfg.Dsn()
 
-	pool, err := pgxpool.New(context.Background(), dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	if tracer != nil {
+		poolCfg.ConnConfig.Tracer = tracer
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	if err := pool.Ping(ctx); err != nil {
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	return NewPgDatabase(pool, dsn), nil
-}
-
-func (db *PgDatabase) Close() {
-	if db.Pool != nil {
-		db.Pool.Close()
+	backoff := retry.Backoff{
+		Initial:  cfg.ConnectBackoff,
+		Max:      cfg.ConnectMaxBackoff,
+		Deadline: cfg.ConnectTimeout,
 	}
-}
 
-func (db *PgDatabase) RunMigrations(logger logger.Logger) error {
-	const op = "PgDatabase.RunMigrations"
+	ping := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
 
-	sqlDb, err := sql.Open("pgx", db.Dsn)
-	if err != nil {
-		return err
+		return pool.Ping(ctx)
 	}
-	defer sqlDb.Close()
 
-	driver, err := postgres.WithInstance(sqlDb, &postgres.Config{})
-	if err != nil {
-		return err
+	onRetry := func(attempt int, err error, delay time.Duration) {
+		logger.Warnf("database is not ready (attempt %d): %v, retrying in %s", attempt, err, delay)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://db/migrations",
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return e.Wrap(op, err)
+	if err := retry.Do(ctx, backoff, ping, onRetry); err != nil {
+		pool.Close()
+		return nil, e.Wrap(op, err)
 	}
 
-	err = m.Up()
-	if err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			return nil
-		}
-		return e.Wrap(op, err)
+	logger.Infof("connected to database %s at %s:%d", cfg.Database, cfg.Host, cfg.Port)
+	return NewPgDatabase(pool, dsn), nil
+}
+
+func (db *PgDatabase) Close() {
+	if db.Pool != nil {
+		db.Pool.Close()
 	}
+}
 
-	logger.Infof("migrations applied successfully")
-	return nil
+func (db *PgDatabase) Ping(ctx context.Context) error {
+	return db.Pool.Ping(ctx)
 }
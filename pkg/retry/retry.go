@@ -0,0 +1,63 @@
+// Package retry repeats an operation with exponential backoff.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrDeadlineExceeded = errors.New("retry deadline exceeded")
+
+type Backoff struct {
+	Initial  time.Duration
+	Max      time.Duration
+	Deadline time.Duration
+}
+
+// Delay returns the wait after the given failed attempt, counting from 1:
+// Initial doubled attempt-1 times, up to Max.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, b.Max)
+}
+
+// Do calls fn until it succeeds, ctx is done or the deadline is exceeded. The delay
+// starts at Initial and doubles after every failed attempt, up to Max. onRetry, if
+// not nil, is called after each failure with the attempt number and the next delay.
+func Do(ctx context.Context, b Backoff, fn func(ctx context.Context) error, onRetry func(attempt int, err error, delay time.Duration)) error {
+	deadline := time.Now().Add(b.Deadline)
+
+	for attempt := 1; ; attempt++ {
+		delay := b.Delay(attempt)
+
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(ctxErr, err)
+		}
+
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("%w after %d attempts: %w", ErrDeadlineExceeded, attempt, err)
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotReady = errors.New("not ready")
+
+func TestDo(t *testing.T) {
+	backoff := Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond, Deadline: time.Second}
+
+	t.Run("succeeds after failures with growing delays", func(t *testing.T) {
+		calls := 0
+		var delays []time.Duration
+
+		err := Do(context.Background(), backoff, func(context.Context) error {
+			calls++
+			if calls < 5 {
+				return errNotReady
+			}
+			return nil
+		}, func(attempt int, err error, delay time.Duration) {
+			assert.Equal(t, len(delays)+1, attempt)
+			assert.ErrorIs(t, err, errNotReady)
+			delays = append(delays, delay)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, calls)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}, delays)
+	})
+
+	t.Run("gives up after deadline", func(t *testing.T) {
+		b := Backoff{Initial: 10 * time.Millisecond, Max: 10 * time.Millisecond, Deadline: 35 * time.Millisecond}
+
+		err := Do(context.Background(), b, func(context.Context) error { return errNotReady }, nil)
+
+		assert.ErrorIs(t, err, ErrDeadlineExceeded)
+		assert.ErrorIs(t, err, errNotReady)
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		slow := Backoff{Initial: time.Hour, Max: time.Hour, Deadline: 2 * time.Hour}
+
+		err := Do(ctx, slow, func(context.Context) error { return errNotReady }, func(int, error, time.Duration) {
+			cancel()
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 5 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: time.Second},
+		{attempt: 2, expected: 2 * time.Second},
+		{attempt: 3, expected: 4 * time.Second},
+		{attempt: 4, expected: 5 * time.Second},
+		{attempt: 100, expected: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, b.Delay(tt.attempt), "attempt %d", tt.attempt)
+	}
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	migrations "avito-internship/db"
+	"avito-internship/pkg/e"
+	"avito-internship/pkg/logger"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "modernc.org/sqlite"
+)
+
+type Config struct {
+	Path string `yaml:"path" env:"SQLITE_PATH"`
+}
+
+type SqliteDatabase struct {
+	DB  *sql.DB
+	Dsn string
+}
+
+func NewSqliteDatabase(db *sql.DB, dsn string) *SqliteDatabase {
+	return &SqliteDatabase{DB: db, Dsn: dsn}
+}
+
+func Connect(cfg Config) (*SqliteDatabase, error) {
+	return Open(cfg.Path)
+}
+
+// Open opens the database file at path. SQLite allows a single writer, so the pool
+// is limited to one connection and transactions take the write lock on BEGIN.
+func Open(path string) (*SqliteDatabase, error) {
+	const op = "SqliteDatabase.Open"
+
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+	sqlDb, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	sqlDb.SetMaxOpenConns(1)
+
+	if err := sqlDb.Ping(); err != nil {
+		sqlDb.Close()
+		return nil, e.Wrap(op, err)
+	}
+
+	return NewSqliteDatabase(sqlDb, dsn), nil
+}
+
+func (db *SqliteDatabase) Close() {
+	if db.DB != nil {
+		db.DB.Close()
+	}
+}
+
+// Migrate runs fn against the embedded migrations. SQLite serializes writers on its
+// own, so no extra locking is needed.
+func (db *SqliteDatabase) Migrate(ctx context.Context, logger logger.Logger, fn func(m *migrate.Migrate) error) error {
+	const op = "SqliteDatabase.Migrate"
+
+	sqlDb, err := sql.Open("sqlite", migrationDsn(db.Dsn))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer sqlDb.Close()
+
+	driver, err := sqlite.WithInstance(sqlDb, &sqlite.Config{})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	source, err := iofs.New(migrations.SqliteMigrations, "sqlite/migrations")
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := fn(m); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// migrationDsn turns foreign keys off for migrations. Table rebuilds drop the old
+// table, which must not cascade into the tables referencing it; migrations that
+// rebuild a table check the references themselves. See
+// https://www.sqlite.org/lang_altertable.html#otheralter.
+func migrationDsn(dsn string) string {
+	return strings.Replace(dsn, "_pragma=foreign_keys(1)", "_pragma=foreign_keys(0)", 1)
+}
+
+func (db *SqliteDatabase) RunMigrations(ctx context.Context, logger logger.Logger) error {
+	return db.Migrate(ctx, logger, func(m *migrate.Migrate) error {
+		err := m.Up()
+		if errors.Is(err, migrate.ErrNoChange) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("migrations applied successfully")
+		return nil
+	})
+}
+
+func (db *SqliteDatabase) Ping(ctx context.Context) error {
+	return db.DB.PingContext(ctx)
+}
+
+// ExpectedSchemaVersion is the version of the newest embedded migration.
+func (db *SqliteDatabase) ExpectedSchemaVersion() (uint, error) {
+	const op = "SqliteDatabase.ExpectedSchemaVersion"
+
+	version, err := migrations.LatestVersion(migrations.SqliteMigrations, "sqlite/migrations")
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return version, nil
+}
+
+// SchemaVersion reads the applied schema version from the golang-migrate table.
+func (db *SqliteDatabase) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	const op = "SqliteDatabase.SchemaVersion"
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err := db.DB.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, e.Wrap(op, err)
+	}
+
+	return uint(version), dirty, nil
+}
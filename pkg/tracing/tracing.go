@@ -0,0 +1,141 @@
+// Package tracing configures the OpenTelemetry tracer provider. With the default
+// "none" exporter nothing is installed and instrumentation is not wired at all.
+package tracing
+
+import (
+	"avito-internship/pkg/e"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	Exporter     string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	File         string  `yaml:"file" env:"TRACING_FILE"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" env:"TRACING_OTLP_INSECURE"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	ServiceName  string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+}
+
+func (c Config) Enabled() bool {
+	return c.Exporter != ExporterNone
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	case ExporterFile:
+		if c.File == "" {
+			errs = append(errs, errors.New("tracing.file: must not be empty for the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: must be one of none, stdout, file, otlp, got %q", c.Exporter))
+	}
+
+	if c.Exporter == ExporterOTLP && c.OTLPEndpoint == "" {
+		errs = append(errs, errors.New("tracing.otlp_endpoint: must not be empty for the otlp exporter"))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio: must be between 0 and 1, got %v", c.SampleRatio))
+	}
+	if c.Enabled() && c.ServiceName == "" {
+		errs = append(errs, errors.New("tracing.service_name: must not be empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Setup installs the global tracer provider and propagator. The returned shutdown
+// flushes pending spans; it is a no-op when tracing is disabled.
+func Setup(ctx context.Context, cfg Config) (shutdown func(ctx context.Context) error, err error) {
+	const op = "tracing.Setup"
+
+	noop := func(context.Context) error { return nil }
+	if !cfg.Enabled() {
+		return noop, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return noop, e.Wrap(op, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return noop, e.Wrap(op, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		return errors.Join(err, closeOutput())
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, noClose, err
+	case ExporterFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, noClose, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(io.Writer(file)))
+		if err != nil {
+			file.Close()
+			return nil, noClose, err
+		}
+		return exporter, file.Close, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, noClose, err
+	default:
+		return nil, noClose, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+}
+
+// Tracer returns a named tracer of the global provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
@@ -0,0 +1,87 @@
+package transaction
+
+import (
+	"avito-internship/pkg/e"
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/drivers"
+)
+
+// SqlManager is a Manager for database/sql.
+type SqlManager struct {
+	db   *sql.DB
+	opts *sql.TxOptions
+}
+
+// NewSqlManager creates Manager for sql.DB.
+func NewSqlManager(db *sql.DB) *SqlManager {
+	return &SqlManager{db: db}
+}
+
+// Begin starts trm.Transaction for sql.Tx.
+func (m *SqlManager) Begin(ctx context.Context) (context.Context, trm.Transaction, error) {
+	tx, err := m.db.BeginTx(ctx, m.opts)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return ctx, newSqlTransaction(tx), nil
+}
+
+// SqlTransaction is trm.Transaction for sql.Tx.
+type SqlTransaction struct {
+	mu       sync.Mutex
+	tx       *sql.Tx
+	isClosed *drivers.IsClosed
+}
+
+func newSqlTransaction(tx *sql.Tx) *SqlTransaction {
+	return &SqlTransaction{
+		tx:       tx,
+		isClosed: drivers.NewIsClosed(),
+	}
+}
+
+// Transaction returns the real transaction sql.Tx.
+func (t *SqlTransaction) Transaction() interface{} {
+	return t.tx
+}
+
+// Commit the trm.Transaction.
+func (t *SqlTransaction) Commit(_ context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.isClosed.Close()
+
+	return t.tx.Commit()
+}
+
+// Rollback the trm.Transaction.
+func (t *SqlTransaction) Rollback(_ context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.isClosed.Close()
+
+	return t.tx.Rollback()
+}
+
+// IsActive returns true if the transaction started but not committed or rolled back.
+func (t *SqlTransaction) IsActive() bool {
+	return t.isClosed.IsActive()
+}
+
+// Closed returns a channel that's closed when transaction committed or rolled back.
+func (t *SqlTransaction) Closed() <-chan struct{} {
+	return t.isClosed.Closed()
+}
+
+func SqlTxFromCtx(ctx context.Context) (*sql.Tx, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, e.ErrTransactionNotFound
+	}
+	return tx, nil
+}
@@ -16,6 +16,32 @@ type Transactional interface {
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 }
 
+// Manager starts transactions on a storage backend.
+type Manager interface {
+	Begin(ctx context.Context) (context.Context, trm.Transaction, error)
+}
+
+// PgxManager is a Manager for pgx.Conn, pgxpool.Conn or pgxpool.Pool.
+type PgxManager struct {
+	db   Transactional
+	opts pgx.TxOptions
+}
+
+// NewPgxManager creates Manager for Transactional.
+func NewPgxManager(db Transactional) *PgxManager {
+	return &PgxManager{db: db}
+}
+
+// Begin starts trm.Transaction for pgx.Tx.
+func (m *PgxManager) Begin(ctx context.Context) (context.Context, trm.Transaction, error) {
+	ctx, tr, err := NewTransaction(ctx, m.opts, m.db)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return ctx, tr, nil
+}
+
 // Transaction is trm.Transaction for pgx.Tx.
 type Transaction struct {
 	mu       sync.Mutex
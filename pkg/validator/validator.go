@@ -2,31 +2,266 @@ package validator
 
 import (
 	"avito-internship/pkg/e"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
+	"errors"
+	"fmt"
+	"reflect"
 	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxIdLength is the length of the id columns, VARCHAR(50).
+const maxIdLength = 50
+
+// uuidV7 matches the lowercase UUIDv7 part of server-generated ids.
+const uuidV7 = `[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`
+
+// ingestedNumber matches the number part of ids of ingested pull requests.
+const ingestedNumber = `[1-9][0-9]*`
+
+// Prefixes of server-generated ids. Tombstone ids of anonymized users are
+// minted by the server only, so they are accepted where existing users are
+// referenced. Their prefix is reserved: ids starting with it are rejected where
+// users are created, even when the configured pattern matches them. Pull
+// requests ingested from GitHub are named after their number and their prefix
+// is reserved the same way.
+var (
+	generatedUserPrefixes        = []string{"u-"}
+	generatedPullRequestPrefixes = []string{"pr-"}
+	tombstoneUserPrefixes        = []string{"tombstone-"}
+	githubPullRequestPrefixes    = []string{"gh-"}
 )
 
 var (
-	userIDRegex = regexp.MustCompile(`^u([1-9]|[1-9][0-9]|[1-9][0-9]{2})$`)
-	prIDRegex   = regexp.MustCompile(`^pr-(100[1-9]|10[1-9][0-9]|1[1-9][0-9]{2}|[2-9][0-9]{3})$`)
-	vOnce       sync.Once
+	namespaceRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+	formats atomic.Pointer[idFormats]
+	vOnce   sync.Once
 )
 
-// ValidateUserID валидация для user_id
+// Namespace holds the id formats of one source, e.g. a forge. Ids of the source
+// are written as <namespace>:<id>. An empty pattern accepts no ids of that kind.
+type Namespace struct {
+	UserId        string `yaml:"user_id"`
+	PullRequestId string `yaml:"pull_request_id"`
+}
+
+// Config sets the formats of externally supplied ids. Patterns are regular
+// expressions matched against the whole id. Server-generated ids are accepted
+// whatever the patterns are.
+type Config struct {
+	UserId        string `yaml:"user_id" env:"ID_USER_PATTERN"`
+	PullRequestId string `yaml:"pull_request_id" env:"ID_PULL_REQUEST_PATTERN"`
+	// Namespaces are set in the YAML file only.
+	Namespaces map[string]Namespace `yaml:"namespaces"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		UserId:        `u([1-9]|[1-9][0-9]|[1-9][0-9]{2})`,
+		PullRequestId: `pr-(100[1-9]|10[1-9][0-9]|1[1-9][0-9]{2}|[2-9][0-9]{3})`,
+	}
+}
+
+func (c Config) Validate() error {
+	_, err := compile(c)
+	return err
+}
+
+type idFormat struct {
+	pattern   string
+	external  *regexp.Regexp
+	generated *regexp.Regexp
+	prefixes  []string
+	// ingested matches ids of pull requests ingested from GitHub, if any.
+	ingested         *regexp.Regexp
+	ingestedPrefixes []string
+	namespaces       map[string]*regexp.Regexp
+	names            []string
+	// reserved prefixes are rejected whatever the patterns are.
+	reserved []string
+}
+
+type idFormats struct {
+	// user accepts the ids of existing users, newUser the ids of created ones,
+	// and the same for pull requests.
+	user           idFormat
+	newUser        idFormat
+	pullRequest    idFormat
+	newPullRequest idFormat
+}
+
+func (f idFormat) match(id string) bool {
+	if utf8.RuneCountInString(id) > maxIdLength {
+		return false
+	}
+
+	for _, prefix := range f.reserved {
+		if strings.HasPrefix(id, prefix) {
+			return false
+		}
+	}
+
+	if f.external.MatchString(id) || f.generated.MatchString(id) {
+		return true
+	}
+	if f.ingested != nil && f.ingested.MatchString(id) {
+		return true
+	}
+
+	if name, rest, ok := strings.Cut(id, ":"); ok {
+		if re, ok := f.namespaces[name]; ok {
+			return re.MatchString(rest)
+		}
+	}
+
+	return false
+}
+
+func compile(c Config) (*idFormats, error) {
+	var errs []error
+
+	user := newIdFormat("ids.user_id", c.UserId, generatedUserPrefixes, c.Namespaces, func(ns Namespace) string { return ns.UserId }, &errs)
+	newUser := user.withReserved(tombstoneUserPrefixes)
+	user = user.withGenerated(slices.Concat(generatedUserPrefixes, tombstoneUserPrefixes))
+	pr := newIdFormat("ids.pull_request_id", c.PullRequestId, generatedPullRequestPrefixes, c.Namespaces, func(ns Namespace) string { return ns.PullRequestId }, &errs)
+	newPr := pr.withReserved(githubPullRequestPrefixes)
+	pr = pr.withIngested(githubPullRequestPrefixes)
+
+	for name := range c.Namespaces {
+		if !namespaceRegex.MatchString(name) {
+			errs = append(errs, fmt.Errorf("ids.namespaces: name %q must match %s", name, namespaceRegex))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &idFormats{user: user, newUser: newUser, pullRequest: pr, newPullRequest: newPr}, nil
+}
+
+func newIdFormat(key, pattern string, generatedPrefixes []string, namespaces map[string]Namespace,
+	nsPattern func(Namespace) string, errs *[]error) idFormat {
+	anchor := func(key, pattern string) *regexp.Regexp {
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: invalid pattern: %w", key, err))
+		}
+		return re
+	}
+
+	if pattern == "" {
+		*errs = append(*errs, fmt.Errorf("%s: must not be empty", key))
+	}
+
+	f := idFormat{
+		pattern:    pattern,
+		external:   anchor(key, pattern),
+		namespaces: make(map[string]*regexp.Regexp),
+	}
+
+	for name, ns := range namespaces {
+		if nsPattern(ns) == "" {
+			continue
+		}
+		f.namespaces[name] = anchor("ids.namespaces."+name, nsPattern(ns))
+		f.names = append(f.names, name+":<id>")
+	}
+	slices.Sort(f.names)
+
+	return f.withGenerated(generatedPrefixes)
+}
+
+// withGenerated returns the format accepting server-generated ids with the
+// given prefixes instead of the current ones.
+func (f idFormat) withGenerated(prefixes []string) idFormat {
+	f.generated = regexp.MustCompile(`^(?:` + strings.Join(prefixes, "|") + `)` + uuidV7 + `$`)
+	f.prefixes = prefixes
+
+	return f
+}
+
+// withIngested returns the format accepting ids of ingested pull requests, the
+// given prefixes followed by a number.
+func (f idFormat) withIngested(prefixes []string) idFormat {
+	f.ingested = regexp.MustCompile(`^(?:` + strings.Join(prefixes, "|") + `)` + ingestedNumber + `$`)
+	f.ingestedPrefixes = prefixes
+
+	return f
+}
+
+// withReserved returns the format rejecting ids with the given prefixes.
+func (f idFormat) withReserved(prefixes []string) idFormat {
+	f.reserved = prefixes
+
+	return f
+}
+
+// rule describes the accepted ids in validation errors.
+func (f idFormat) rule() string {
+	generated := make([]string, 0, len(f.prefixes))
+	for _, prefix := range f.prefixes {
+		generated = append(generated, prefix+"<UUIDv7>")
+	}
+
+	rule := fmt.Sprintf("must match %s or be a generated %s", f.pattern, strings.Join(generated, " or "))
+	if len(f.ingestedPrefixes) > 0 {
+		ingested := make([]string, 0, len(f.ingestedPrefixes))
+		for _, prefix := range f.ingestedPrefixes {
+			ingested = append(ingested, prefix+"<number>")
+		}
+		rule += ", or be an ingested " + strings.Join(ingested, " or ")
+	}
+	if len(f.names) > 0 {
+		rule += ", or be one of " + strings.Join(f.names, ", ")
+	}
+	if len(f.reserved) > 0 {
+		rule += ", not start with " + strings.Join(f.reserved, " or ")
+	}
+
+	return rule + fmt.Sprintf(", and be at most %d characters long", maxIdLength)
+}
+
+// ValidateUserID валидация для user_id по настроенным форматам
 func ValidateUserID(fl validator.FieldLevel) bool {
-	return userIDRegex.MatchString(fl.Field().String())
+	return formats.Load().user.match(fl.Field().String())
 }
 
-// ValidatePullRequestID валидация для pull_request_id
+// ValidateNewUserID валидация для user_id создаваемого пользователя: в отличие
+// от ValidateUserID, id надгробий не принимаются
+func ValidateNewUserID(fl validator.FieldLevel) bool {
+	return formats.Load().newUser.match(fl.Field().String())
+}
+
+// ValidatePullRequestID валидация для pull_request_id по настроенным форматам
 func ValidatePullRequestID(fl validator.FieldLevel) bool {
-	return prIDRegex.MatchString(fl.Field().String())
+	return formats.Load().pullRequest.match(fl.Field().String())
+}
+
+// ValidateNewPullRequestID валидация для pull_request_id создаваемого PR: в
+// отличие от ValidatePullRequestID, id PR из GitHub не принимаются
+func ValidateNewPullRequestID(fl validator.FieldLevel) bool {
+	return formats.Load().newPullRequest.match(fl.Field().String())
 }
 
-func RegisterValidators() error {
+// RegisterValidators installs the id validators in gin. The validators are
+// registered once; every call replaces the id formats.
+func RegisterValidators(cfg Config) error {
 	const op = "validator.RegisterValidators"
 
+	f, err := compile(cfg)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	formats.Store(f)
+
 	var result_err error
 	vOnce.Do(func() {
 		validator, ok := binding.Validator.Engine().(*validator.Validate)
@@ -35,16 +270,94 @@ func RegisterValidators() error {
 			return
 		}
 
+		// Errors name fields as the client sent them.
+		validator.RegisterTagNameFunc(fieldName)
+
 		if err := validator.RegisterValidation("userid", ValidateUserID); err != nil {
 			result_err = e.Wrap(op, err)
 			return
 		}
 
+		if err := validator.RegisterValidation("newuserid", ValidateNewUserID); err != nil {
+			result_err = e.Wrap(op, err)
+			return
+		}
+
 		if err := validator.RegisterValidation("prid", ValidatePullRequestID); err != nil {
 			result_err = e.Wrap(op, err)
 			return
 		}
+
+		if err := validator.RegisterValidation("newprid", ValidateNewPullRequestID); err != nil {
+			result_err = e.Wrap(op, err)
+			return
+		}
 	})
 
 	return result_err
 }
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// Describe turns the validation failure in err into an e.ValidationError that
+// names the first failed field and rule. It returns nil for other errors.
+func Describe(err error) *e.ValidationError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return nil
+	}
+
+	fe := errs[0]
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+
+	return &e.ValidationError{Field: field, Rule: fe.Tag(), Message: ruleMessage(fe)}
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "userid":
+		return formats.Load().user.rule()
+	case "newuserid":
+		return formats.Load().newUser.rule()
+	case "prid":
+		return formats.Load().pullRequest.rule()
+	case "newprid":
+		return formats.Load().newPullRequest.rule()
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must have %s %s items", bound, fe.Param())
+		}
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	}
+
+	if fe.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	}
+	return "must satisfy " + fe.Tag()
+}